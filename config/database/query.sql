@@ -27,6 +27,47 @@ FROM users
 WHERE tg_id = $1 LIMIT 1;
 
 
+/* User Settings */
+
+-- name: GetUserSettings :one
+SELECT id,
+       user_id,
+       timezone,
+       quiet_hours_start,
+       quiet_hours_end,
+       notify_new_seasons,
+       notify_status_changes,
+       notify_episode_reminders,
+       notify_releases,
+       notify_digest,
+       digest_mode,
+       region,
+       subscribed_providers,
+       notify_availability,
+       created_at,
+       updated_at
+FROM user_settings
+WHERE user_id = $1;
+
+-- name: UpsertUserSettings :exec
+INSERT INTO user_settings (user_id, timezone, quiet_hours_start, quiet_hours_end, notify_new_seasons,
+                           notify_status_changes, notify_episode_reminders, notify_releases, notify_digest,
+                           digest_mode, region, subscribed_providers, notify_availability)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) ON CONFLICT (user_id) DO
+UPDATE SET
+    timezone = EXCLUDED.timezone,
+    quiet_hours_start = EXCLUDED.quiet_hours_start,
+    quiet_hours_end = EXCLUDED.quiet_hours_end,
+    notify_new_seasons = EXCLUDED.notify_new_seasons,
+    notify_status_changes = EXCLUDED.notify_status_changes,
+    notify_episode_reminders = EXCLUDED.notify_episode_reminders,
+    notify_releases = EXCLUDED.notify_releases,
+    notify_digest = EXCLUDED.notify_digest,
+    digest_mode = EXCLUDED.digest_mode,
+    region = EXCLUDED.region,
+    subscribed_providers = EXCLUDED.subscribed_providers,
+    notify_availability = EXCLUDED.notify_availability;
+
 /* TV Shows Table */
 
 -- name: GetUserTVShows :many
@@ -37,6 +78,8 @@ SELECT id,
        episodes,
        runtime,
        status,
+       watch_status,
+       watch_status_updated_at,
        created_at,
        updated_at
 FROM tv_shows
@@ -58,6 +101,8 @@ SELECT id,
        episodes,
        runtime,
        status,
+       watch_status,
+       watch_status_updated_at,
        created_at,
        updated_at
 FROM tv_shows
@@ -79,6 +124,21 @@ WHERE api_id = $1
   AND user_id = $2
   AND deleted_at IS NULL;
 
+-- name: UpdateTVShowStatus :exec
+UPDATE tv_shows
+SET status = $3
+WHERE api_id = $1
+  AND user_id = $2
+  AND deleted_at IS NULL;
+
+-- name: UpdateTVShowWatchStatus :exec
+UPDATE tv_shows
+SET watch_status            = $3,
+    watch_status_updated_at = NOW()
+WHERE api_id = $1
+  AND user_id = $2
+  AND deleted_at IS NULL;
+
 -- name: SoftDeleteTVShow :exec
 UPDATE tv_shows
 SET deleted_at = NOW()
@@ -87,6 +147,37 @@ WHERE api_id = $1
   AND deleted_at IS NULL;
 
 
+-- name: GetShowNotificationState :one
+SELECT last_notified_seasons, snoozed_until
+FROM show_notification_states
+WHERE user_id = $1
+  AND show_api_id = $2;
+
+-- name: UpsertShowNotificationState :exec
+INSERT INTO show_notification_states (user_id, show_api_id, last_notified_seasons)
+VALUES ($1, $2, $3) ON CONFLICT (user_id, show_api_id) DO
+UPDATE SET
+    last_notified_seasons = EXCLUDED.last_notified_seasons,
+    snoozed_until = NULL;
+
+-- name: SnoozeShowNotification :execrows
+UPDATE show_notification_states
+SET snoozed_until = $3
+WHERE user_id = $1
+  AND show_api_id = $2;
+
+-- name: GetShowCheckSchedules :many
+SELECT show_api_id, next_check_at
+FROM show_check_schedules;
+
+-- name: UpsertShowCheckSchedule :exec
+INSERT INTO show_check_schedules (show_api_id, show_status, next_check_at, last_checked_at)
+VALUES ($1, $2, $3, NOW()) ON CONFLICT (show_api_id) DO
+UPDATE SET
+    show_status = EXCLUDED.show_status,
+    next_check_at = EXCLUDED.next_check_at,
+    last_checked_at = EXCLUDED.last_checked_at;
+
 /* Movies Table */
 
 -- name: GetUserMovies :many
@@ -95,6 +186,13 @@ FROM movies
 WHERE user_id = $1
   AND deleted_at IS NULL;
 
+-- name: GetUserMovie :one
+SELECT title, runtime
+FROM movies
+WHERE api_id = $1
+  AND user_id = $2
+  AND deleted_at IS NULL;
+
 -- name: MovieExists :one
 SELECT EXISTS(SELECT 1 FROM movies WHERE api_id = $1 AND user_id = $2 AND deleted_at IS NULL);
 
@@ -118,11 +216,100 @@ WHERE api_id = $1
   AND deleted_at IS NULL;
 
 
+/* Watch Events */
+
+-- name: CreateWatchEvent :exec
+INSERT INTO watch_events (user_id, api_id, type, runtime, watched_on)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetWatchEvents :many
+SELECT id, runtime, watched_on, created_at
+FROM watch_events
+WHERE user_id = $1
+  AND api_id = $2
+  AND type = $3
+ORDER BY watched_on DESC, created_at DESC;
+
+-- name: DeleteWatchEvents :many
+DELETE
+FROM watch_events
+WHERE user_id = $1
+  AND api_id = $2
+  AND type = $3 RETURNING runtime, watched_on, created_at;
+
+-- name: RestoreWatchEvent :exec
+INSERT INTO watch_events (user_id, api_id, type, runtime, watched_on, created_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetWatchTotals :one
+SELECT COUNT(DISTINCT api_id)::INT    AS titles,
+       COUNT(*)::INT                  AS watches,
+       COALESCE(SUM(runtime), 0)::INT AS runtime
+FROM watch_events
+WHERE user_id = $1
+  AND type = $2;
+
+/* Ratings */
+
+-- name: UpsertRating :exec
+INSERT INTO ratings (user_id, api_id, type, season_number, rating)
+VALUES ($1, $2, $3, $4, $5) ON CONFLICT (user_id, api_id, type, season_number) DO
+UPDATE SET rating = EXCLUDED.rating;
+
+-- name: SetRatingReview :execrows
+UPDATE ratings
+SET review = $5
+WHERE user_id = $1
+  AND api_id = $2
+  AND type = $3
+  AND season_number = $4;
+
+-- name: GetTitleRatings :many
+SELECT season_number, rating, review
+FROM ratings
+WHERE user_id = $1
+  AND api_id = $2
+  AND type = $3
+ORDER BY season_number;
+
+-- name: GetRatingSummary :one
+SELECT COUNT(*)::INT                      AS rated,
+       COALESCE(AVG(rating), 0)::FLOAT8 AS average
+FROM ratings
+WHERE user_id = $1
+  AND (type = $2 OR $2 = 'ALL')
+  AND season_number = 0;
+
+-- name: DeleteTitleRatings :many
+DELETE
+FROM ratings
+WHERE user_id = $1
+  AND api_id = $2
+  AND type = $3 RETURNING season_number, rating, review;
+
+-- name: RestoreRating :exec
+INSERT INTO ratings (user_id, api_id, type, season_number, rating, review)
+VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (user_id, api_id, type, season_number) DO NOTHING;
+
+-- name: GetTopRated :many
+SELECT r.api_id,
+       r.type,
+       r.rating,
+       COALESCE(m.title, t.name, '')::TEXT AS title
+FROM ratings r
+         LEFT JOIN movies m ON r.type = 'MOVIE' AND m.user_id = r.user_id AND m.api_id = r.api_id AND m.deleted_at IS NULL
+         LEFT JOIN tv_shows t ON r.type = 'TV_SHOW' AND t.user_id = r.user_id AND t.api_id = r.api_id AND t.deleted_at IS NULL
+WHERE r.user_id = $1
+  AND (r.type = $2 OR $2 = 'ALL')
+  AND r.season_number = 0
+ORDER BY r.rating DESC, r.updated_at DESC LIMIT $3;
+
 /* Watchlists Table */
 
 -- name: CreateWatchlist :exec
-INSERT INTO watchlists (user_id, show_api_id, type, title, image)
-VALUES ($1, $2, $3, $4, $5);
+INSERT INTO watchlists (user_id, show_api_id, type, title, image, runtime, position)
+VALUES ($1, $2, $3, $4, $5, $6,
+        (SELECT COALESCE(MAX(position), 0) + 1 FROM watchlists WHERE user_id = $1 AND deleted_at IS NULL));
 
 -- name: GetUserWatchlist :one
 SELECT id,
@@ -147,20 +334,381 @@ FROM watchlists
 WHERE user_id = $1
   AND deleted_at IS NULL;
 
--- name: GetUserWatchlistsWithType :many
-SELECT id, show_api_id, type, title, image, created_at, updated_at
+-- name: GetWatchlistEntries :many
+SELECT id,
+       show_api_id,
+       type,
+       title,
+       image,
+       priority,
+       note,
+       position,
+       release_date,
+       runtime,
+       created_at
 FROM watchlists
 WHERE user_id = $1
-  AND type = $2
+  AND (type = $2 OR $2 = 'ALL')
+  AND (priority = $3 OR $3 = 'ALL')
+  AND deleted_at IS NULL
+ORDER BY CASE WHEN @sort_by::TEXT = 'PRIORITY' THEN CASE priority WHEN 'HIGH' THEN 0 WHEN 'NORMAL' THEN 1 ELSE 2 END END,
+         CASE WHEN @sort_by::TEXT = 'ADDED' THEN created_at END DESC,
+         CASE WHEN @sort_by::TEXT = 'TITLE' THEN LOWER(title) END,
+         CASE WHEN @sort_by::TEXT = 'RELEASE' THEN release_date END NULLS LAST,
+         CASE WHEN @sort_by::TEXT = 'RUNTIME' THEN runtime END NULLS LAST,
+         position;
+
+-- name: GetWatchlistEntry :one
+SELECT id,
+       show_api_id,
+       type,
+       title,
+       image,
+       priority,
+       note,
+       position,
+       release_date,
+       runtime,
+       created_at
+FROM watchlists
+WHERE user_id = $1
+  AND show_api_id = $2
+  AND type = $3
+  AND deleted_at IS NULL;
+
+-- name: UpdateWatchlistPriority :execrows
+UPDATE watchlists
+SET priority = $4
+WHERE user_id = $1
+  AND show_api_id = $2
+  AND type = $3
+  AND deleted_at IS NULL;
+
+-- name: UpdateWatchlistNote :execrows
+UPDATE watchlists
+SET note = $4
+WHERE user_id = $1
+  AND show_api_id = $2
+  AND type = $3
   AND deleted_at IS NULL;
 
+-- name: UpdateWatchlistPosition :exec
+UPDATE watchlists
+SET position = $2
+WHERE id = $1;
+
+-- name: GetWatchlistsByType :many
+SELECT id, user_id, show_api_id, title, image, release_date, release_status, aired_seasons, runtime
+FROM watchlists
+WHERE type = $1
+  AND deleted_at IS NULL
+ORDER BY show_api_id;
+
+-- name: UpdateWatchlistRelease :exec
+UPDATE watchlists
+SET release_date   = $2,
+    release_status = $3,
+    runtime        = $4
+WHERE id = $1;
+
+-- name: UpdateWatchlistShowRelease :exec
+UPDATE watchlists
+SET release_date   = $2,
+    release_status = $3,
+    aired_seasons  = $4,
+    runtime        = $5
+WHERE id = $1;
+
+-- name: GetWatchlistAvailability :many
+SELECT w.id,
+       w.user_id,
+       w.show_api_id,
+       w.type,
+       w.title,
+       w.available_providers,
+       COALESCE(s.region, 'US')::TEXT                    AS region,
+       COALESCE(s.subscribed_providers, '{}')::INT[]     AS subscribed_providers
+FROM watchlists w
+         LEFT JOIN user_settings s ON s.user_id = w.user_id
+WHERE w.deleted_at IS NULL
+ORDER BY w.type, w.show_api_id;
+
+-- name: UpdateWatchlistProviders :exec
+UPDATE watchlists
+SET available_providers = $2
+WHERE id = $1;
+
+-- name: ResetWatchlistProviders :exec
+UPDATE watchlists
+SET available_providers = NULL
+WHERE user_id = $1;
+
 -- name: DeleteWatchlist :exec
 UPDATE watchlists
 SET deleted_at = NOW()
 WHERE show_api_id = $1
   AND user_id = $2
+  AND type = $3
   AND deleted_at IS NULL;
 
+/* User Lists */
+
+-- name: CreateUserList :one
+INSERT INTO user_lists (user_id, name)
+VALUES ($1, $2) RETURNING id;
+
+-- name: GetUserLists :many
+SELECT l.id,
+       l.name,
+       COUNT(i.id)::INT AS items
+FROM user_lists l
+         LEFT JOIN user_list_items i ON i.list_id = l.id
+WHERE l.user_id = $1
+GROUP BY l.id
+ORDER BY l.created_at;
+
+-- name: GetUserList :one
+SELECT id, name, created_at
+FROM user_lists
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: RenameUserList :execrows
+UPDATE user_lists
+SET name = $3
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: DeleteUserList :execrows
+DELETE
+FROM user_lists
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: GetListsWithTitle :many
+SELECT l.id,
+       l.name,
+       EXISTS(SELECT 1
+              FROM user_list_items i
+              WHERE i.list_id = l.id
+                AND i.show_api_id = $2
+                AND i.type = $3) AS has_title
+FROM user_lists l
+WHERE l.user_id = $1
+ORDER BY l.created_at;
+
+-- name: AddListItem :exec
+INSERT INTO user_list_items (list_id, show_api_id, type, title, image)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (list_id, show_api_id, type) DO NOTHING;
+
+-- name: RemoveListItem :execrows
+DELETE
+FROM user_list_items
+WHERE list_id = $1
+  AND show_api_id = $2
+  AND type = $3;
+
+-- name: GetListItems :many
+SELECT id, show_api_id, type, title, image, created_at
+FROM user_list_items
+WHERE list_id = $1
+ORDER BY created_at;
+
+/* Episode Schedules */
+
+-- name: UpsertEpisodeSchedule :exec
+INSERT INTO episode_schedules (show_api_id, show_name, season_number, episode_number, episode_name, air_date, runtime)
+VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (show_api_id, season_number, episode_number) DO
+UPDATE SET
+    show_name = EXCLUDED.show_name,
+    episode_name = EXCLUDED.episode_name,
+    air_date = EXCLUDED.air_date,
+    runtime = EXCLUDED.runtime;
+
+-- name: GetEpisodeSchedule :one
+SELECT id,
+       show_api_id,
+       show_name,
+       season_number,
+       episode_number,
+       episode_name,
+       air_date,
+       runtime,
+       created_at,
+       updated_at
+FROM episode_schedules
+WHERE show_api_id = $1
+  AND season_number = $2
+  AND episode_number = $3;
+
+-- name: GetLastScheduledEpisode :one
+SELECT COALESCE(MAX(episode_number), 0)::INT AS episode_number
+FROM episode_schedules
+WHERE show_api_id = $1
+  AND season_number = $2;
+
+-- name: GetDueEpisodeReminders :many
+SELECT s.show_api_id,
+       s.show_name,
+       s.season_number,
+       s.episode_number,
+       s.episode_name,
+       s.air_date,
+       t.user_id
+FROM episode_schedules s
+         JOIN tv_shows t ON t.api_id = s.show_api_id AND t.deleted_at IS NULL AND t.watch_status <> 'DROPPED'
+         LEFT JOIN episode_reminders r ON r.user_id = t.user_id
+    AND r.show_api_id = s.show_api_id
+    AND r.season_number = s.season_number
+    AND r.episode_number = s.episode_number
+WHERE s.air_date = $1
+  AND r.id IS NULL;
+
+-- name: CreateEpisodeReminder :exec
+INSERT INTO episode_reminders (user_id, show_api_id, season_number, episode_number)
+VALUES ($1, $2, $3, $4) ON CONFLICT (user_id, show_api_id, season_number, episode_number) DO NOTHING;
+
+-- name: MarkEpisodeReminderWatched :execrows
+UPDATE episode_reminders
+SET watched_at = NOW()
+WHERE user_id = $1
+  AND show_api_id = $2
+  AND season_number = $3
+  AND episode_number = $4
+  AND watched_at IS NULL;
+
+/* Episode Watches */
+
+-- name: MarkEpisodeWatched :execrows
+INSERT INTO episode_watches (user_id, show_api_id, season_number, episode_number, runtime)
+VALUES ($1, $2, $3, $4, $5) ON CONFLICT (user_id, show_api_id, season_number, episode_number) DO NOTHING;
+
+-- name: UnmarkEpisodeWatched :execrows
+DELETE
+FROM episode_watches
+WHERE user_id = $1
+  AND show_api_id = $2
+  AND season_number = $3
+  AND episode_number = $4;
+
+-- name: DeleteEpisodeWatchesAfter :many
+DELETE
+FROM episode_watches
+WHERE user_id = $1
+  AND show_api_id = $2
+  AND season_number > $3 RETURNING season_number, episode_number, runtime, watched_at;
+
+-- name: RestoreEpisodeWatch :exec
+INSERT INTO episode_watches (user_id, show_api_id, season_number, episode_number, runtime, watched_at)
+VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (user_id, show_api_id, season_number, episode_number) DO NOTHING;
+
+-- name: GetWatchedEpisodes :many
+SELECT season_number, episode_number
+FROM episode_watches
+WHERE user_id = $1
+  AND show_api_id = $2
+ORDER BY season_number, episode_number;
+
+-- name: GetEpisodeWatchTotals :one
+SELECT COUNT(*)::INT                AS episodes,
+       COALESCE(SUM(runtime), 0)::INT AS runtime
+FROM episode_watches
+WHERE user_id = $1
+  AND show_api_id = $2;
+
+/* Notifications */
+
+-- name: EnqueueNotification :one
+INSERT INTO notifications (user_id, kind, show_api_id, text, image_path, reply_markup)
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;
+
+-- name: GetDueNotifications :many
+SELECT id, user_id, kind, show_api_id, text, image_path, reply_markup, attempts, poster_sent
+FROM notifications
+WHERE status = 'pending'
+  AND next_attempt_at <= NOW()
+ORDER BY next_attempt_at LIMIT $1;
+
+-- name: MarkNotificationSent :exec
+UPDATE notifications
+SET status     = 'sent',
+    attempts   = attempts + 1,
+    last_error = NULL,
+    sent_at    = NOW()
+WHERE id = $1;
+
+-- name: MarkNotificationRetry :exec
+UPDATE notifications
+SET attempts        = attempts + 1,
+    next_attempt_at = $2,
+    last_error      = $3
+WHERE id = $1;
+
+-- name: MarkNotificationFailed :exec
+UPDATE notifications
+SET status     = 'failed',
+    attempts   = attempts + 1,
+    last_error = $2
+WHERE id = $1;
+
+-- name: DeferNotification :exec
+UPDATE notifications
+SET next_attempt_at = $2
+WHERE id = $1;
+
+-- name: MarkNotificationPosterSent :exec
+UPDATE notifications
+SET poster_sent = TRUE
+WHERE id = $1;
+
+-- name: MarkNotificationSkipped :exec
+UPDATE notifications
+SET status     = 'skipped',
+    last_error = $2
+WHERE id = $1;
+
+-- name: HoldNotification :exec
+INSERT INTO notifications (user_id, kind, show_api_id, text, show_name, status)
+VALUES ($1, $2, $3, $4, $5, 'held');
+
+-- name: GetHeldNotificationUsers :many
+SELECT user_id, MIN(created_at)::TIMESTAMPTZ AS oldest_at
+FROM notifications
+WHERE status = 'held'
+GROUP BY user_id;
+
+-- name: GetHeldNotifications :many
+SELECT n.id,
+       n.kind,
+       n.show_api_id,
+       n.text,
+       COALESCE(n.show_name,
+                (SELECT t.name
+                 FROM tv_shows t
+                 WHERE t.user_id = n.user_id
+                   AND t.api_id = n.show_api_id
+                 ORDER BY t.deleted_at DESC NULLS FIRST LIMIT 1),
+                (SELECT w.title
+                 FROM watchlists w
+                 WHERE w.user_id = n.user_id
+                   AND w.show_api_id = n.show_api_id
+                   AND w.type = 'TV_SHOW'
+                 ORDER BY w.deleted_at DESC NULLS FIRST LIMIT 1),
+                (SELECT e.show_name
+                 FROM episode_schedules e
+                 WHERE e.show_api_id = n.show_api_id LIMIT 1), '')::TEXT AS show_name
+FROM notifications n
+WHERE n.user_id = $1
+  AND n.status = 'held'
+ORDER BY n.created_at;
+
+-- name: MarkNotificationsDigested :exec
+UPDATE notifications
+SET status  = 'digested',
+    sent_at = NOW()
+WHERE id = ANY ($1::UUID[]);
+
 /* Workers Related */
 
 -- name: GetWorkerState :one
@@ -173,12 +721,32 @@ SELECT id,
        error,
        shows_checked,
        updates_found,
+       cycle_cursor,
+       paused,
        created_at,
        updated_at
 FROM worker_states
 WHERE worker_id = $1
 ORDER BY updated_at DESC LIMIT 1;
 
+-- name: GetLatestWorkerState :one
+SELECT id,
+       worker_id,
+       worker_type,
+       status,
+       last_check_time,
+       next_check_time,
+       error,
+       shows_checked,
+       updates_found,
+       cycle_cursor,
+       paused,
+       created_at,
+       updated_at
+FROM worker_states
+WHERE worker_type = $1
+ORDER BY updated_at DESC LIMIT 1;
+
 -- name: UpsertWorkerState :one
 INSERT INTO worker_states (worker_id, worker_type, status, last_check_time, next_check_time,
                            error, shows_checked, updates_found, created_at, updated_at)
@@ -207,6 +775,108 @@ SET status        = $2,
     updates_found = $7
 WHERE id = $1;
 
+-- name: UpdateWorkerTaskApiCallsSaved :exec
+UPDATE worker_tasks
+SET api_calls_saved = $2
+WHERE id = $1;
+
+-- name: UpdateWorkerCycleCursor :exec
+UPDATE worker_states
+SET cycle_cursor = $2
+WHERE worker_id = $1;
+
+-- name: SetWorkerPaused :execrows
+UPDATE worker_states
+SET paused = $2
+WHERE worker_type = $1;
+
+-- name: IsWorkerTypePaused :one
+SELECT COALESCE(BOOL_OR(paused), FALSE)::BOOLEAN AS paused
+FROM worker_states
+WHERE worker_type = $1;
+
+-- name: GetWorkerPerformance :many
+SELECT worker_id,
+       worker_type,
+       status,
+       last_check_time,
+       next_check_time,
+       shows_checked,
+       updates_found,
+       error,
+       total_tasks,
+       avg_task_duration_ms,
+       max_task_duration_ms,
+       error_tasks,
+       last_task_time,
+       paused
+FROM worker_performance
+ORDER BY worker_type, worker_id;
+
+-- name: AbortRunningWorkerTasks :execrows
+UPDATE worker_tasks
+SET status   = 'aborted',
+    end_time = NOW(),
+    error    = $2
+WHERE status = 'running'
+  AND worker_id IN (SELECT worker_id FROM worker_states WHERE worker_type = $1);
+
+-- name: AcquireWorkerLease :execrows
+INSERT INTO worker_leases (worker_type, holder_id, expires_at)
+VALUES ($1, $2, $3) ON CONFLICT (worker_type) DO
+UPDATE SET
+    holder_id = EXCLUDED.holder_id,
+    expires_at = EXCLUDED.expires_at
+WHERE worker_leases.holder_id = EXCLUDED.holder_id
+   OR worker_leases.expires_at < NOW();
+
+-- name: ReleaseWorkerLease :exec
+DELETE
+FROM worker_leases
+WHERE worker_type = $1
+  AND holder_id = $2;
+
+-- name: RollupWorkerTasks :one
+WITH pruned AS (
+    DELETE FROM worker_tasks
+    WHERE id IN (SELECT id
+                 FROM worker_tasks
+                 WHERE status <> 'running'
+                   AND created_at < $1
+                 ORDER BY created_at LIMIT $2)
+    RETURNING worker_id, task_type, status, duration_ms, shows_checked, updates_found, api_calls_saved, created_at),
+     rolled AS (
+         INSERT INTO worker_task_daily_stats (worker_id, task_type, day, task_count, error_count, timed_count,
+                                              total_duration_ms, max_duration_ms, shows_checked, updates_found,
+                                              api_calls_saved, last_task_time)
+             SELECT worker_id,
+                    task_type,
+                    (created_at AT TIME ZONE 'UTC')::DATE,
+                    COUNT(*),
+                    COUNT(*) FILTER (WHERE status = 'error'),
+                    COUNT(duration_ms),
+                    COALESCE(SUM(duration_ms), 0),
+                    MAX(duration_ms),
+                    COALESCE(SUM(shows_checked), 0),
+                    COALESCE(SUM(updates_found), 0),
+                    COALESCE(SUM(api_calls_saved), 0),
+                    MAX(created_at)
+             FROM pruned
+             GROUP BY worker_id, task_type, (created_at AT TIME ZONE 'UTC')::DATE
+             ON CONFLICT (worker_id, task_type, day) DO UPDATE SET
+                 task_count = worker_task_daily_stats.task_count + EXCLUDED.task_count,
+                 error_count = worker_task_daily_stats.error_count + EXCLUDED.error_count,
+                 timed_count = worker_task_daily_stats.timed_count + EXCLUDED.timed_count,
+                 total_duration_ms = worker_task_daily_stats.total_duration_ms + EXCLUDED.total_duration_ms,
+                 max_duration_ms = GREATEST(worker_task_daily_stats.max_duration_ms, EXCLUDED.max_duration_ms),
+                 shows_checked = worker_task_daily_stats.shows_checked + EXCLUDED.shows_checked,
+                 updates_found = worker_task_daily_stats.updates_found + EXCLUDED.updates_found,
+                 api_calls_saved = worker_task_daily_stats.api_calls_saved + EXCLUDED.api_calls_saved,
+                 last_task_time = GREATEST(worker_task_daily_stats.last_task_time, EXCLUDED.last_task_time)
+             RETURNING 1)
+SELECT (SELECT COUNT(*) FROM pruned) AS pruned_tasks,
+       (SELECT COUNT(*) FROM rolled) AS stat_rows;
+
 -- name: GetRecentTasks :many
 SELECT id,
        worker_id,
@@ -220,6 +890,7 @@ SELECT id,
        user_id,
        shows_checked,
        updates_found,
+       api_calls_saved,
        created_at
 FROM worker_tasks
 WHERE worker_id = $1
@@ -238,6 +909,7 @@ SELECT id,
        user_id,
        shows_checked,
        updates_found,
+       api_calls_saved,
        created_at
 FROM worker_tasks
 WHERE id = $1;
\ No newline at end of file
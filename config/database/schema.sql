@@ -38,24 +38,70 @@ CREATE UNIQUE INDEX idx_movies_user_api_unique ON movies USING btree (user_id, a
 
 COMMENT ON TABLE movies IS 'Stores movie information tracked by users';
 
--- public.tv_shows definition with user_id still BIGINT but now referencing users.tg_id
-CREATE TABLE IF NOT EXISTS tv_shows
+-- public.watch_events definition, one row per dated watch of a title so rewatches are kept
+CREATE TABLE IF NOT EXISTS watch_events
 (
     id         UUID        NOT NULL DEFAULT gen_random_uuid(),
     user_id    BIGINT      NOT NULL,
     api_id     BIGINT      NOT NULL,
-    name       TEXT        NOT NULL,
-    seasons    INT         NOT NULL,
-    episodes   INT         NOT NULL,
-    runtime    INT         NOT NULL,
-    status     TEXT        NOT NULL,
+    type       TEXT        NOT NULL,
+    runtime    INT         NOT NULL DEFAULT 0,
+    watched_on DATE        NOT NULL DEFAULT CURRENT_DATE,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    deleted_at TIMESTAMPTZ,
+
+    CONSTRAINT watch_events_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_watch_events_user FOREIGN KEY (user_id) REFERENCES users (tg_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_watch_events_user_title ON watch_events (user_id, type, api_id);
+
+COMMENT ON TABLE watch_events IS 'Stores every dated watch of a title, rewatches included';
+
+-- public.ratings definition, season_number 0 rates the whole movie or show
+CREATE TABLE IF NOT EXISTS ratings
+(
+    id            UUID        NOT NULL DEFAULT gen_random_uuid(),
+    user_id       BIGINT      NOT NULL,
+    api_id        BIGINT      NOT NULL,
+    type          TEXT        NOT NULL,
+    season_number INT         NOT NULL DEFAULT 0,
+    rating        SMALLINT    NOT NULL,
+    review        TEXT,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT ratings_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_ratings_user FOREIGN KEY (user_id) REFERENCES users (tg_id) ON DELETE CASCADE,
+    CONSTRAINT ratings_user_title_unique UNIQUE (user_id, api_id, type, season_number),
+    CONSTRAINT check_rating_range CHECK (rating >= 1 AND rating <= 10)
+);
+
+COMMENT ON TABLE ratings IS 'Stores ratings and short reviews of watched titles, season 0 rates the whole title';
+
+-- public.tv_shows definition with user_id still BIGINT but now referencing users.tg_id
+CREATE TABLE IF NOT EXISTS tv_shows
+(
+    id           UUID        NOT NULL DEFAULT gen_random_uuid(),
+    user_id      BIGINT      NOT NULL,
+    api_id       BIGINT      NOT NULL,
+    name         TEXT        NOT NULL,
+    seasons      INT         NOT NULL,
+    episodes     INT         NOT NULL,
+    runtime      INT         NOT NULL,
+    status       TEXT        NOT NULL,
+    -- where the user is with the show, status above is the TMDB status of the show itself
+    watch_status TEXT        NOT NULL DEFAULT 'WATCHING',
+    -- NULL for shows tracked before watch statuses existed, the show checker decides their status once
+    watch_status_updated_at TIMESTAMPTZ DEFAULT NOW(),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at   TIMESTAMPTZ,
 
     CONSTRAINT tv_shows_pkey PRIMARY KEY (id),
     CONSTRAINT fk_tv_shows_user FOREIGN KEY (user_id) REFERENCES users (tg_id) ON DELETE CASCADE,
-    CONSTRAINT check_tv_positive_values CHECK (seasons > 0 AND episodes > 0 AND runtime > 0)
+    -- seasons, episodes and runtime are derived from episode_watches, seasons counts fully watched seasons
+    CONSTRAINT check_tv_non_negative_values CHECK (seasons >= 0 AND episodes >= 0 AND runtime >= 0),
+    CONSTRAINT check_tv_watch_status CHECK (watch_status IN ('PLAN_TO_WATCH', 'WATCHING', 'COMPLETED', 'ON_HOLD', 'DROPPED'))
 );
 
 CREATE UNIQUE INDEX idx_tv_shows_user_api_unique ON tv_shows USING btree (user_id, api_id) WHERE deleted_at IS NULL;
@@ -74,15 +120,220 @@ CREATE TABLE IF NOT EXISTS watchlists
     created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     deleted_at  TIMESTAMPTZ,
+    -- last known release data, used by the release checkers. For shows release_date is the next season premiere.
+    release_date   DATE,
+    release_status TEXT,
+    aired_seasons  INT,
+    -- flatrate providers of the title in the owner's region at the last availability check
+    available_providers INT[],
+    -- set by the owner, position is the manual order of the watchlist
+    priority TEXT NOT NULL DEFAULT 'NORMAL',
+    note     TEXT,
+    position INT  NOT NULL DEFAULT 0,
+    -- kept up to date by the release checkers like release_date. For shows runtime is the episode length.
+    runtime  INT,
 
     CONSTRAINT watchlists_pkey PRIMARY KEY (id),
-    CONSTRAINT fk_watchlists_user FOREIGN KEY (user_id) REFERENCES users (tg_id) ON DELETE CASCADE
+    CONSTRAINT fk_watchlists_user FOREIGN KEY (user_id) REFERENCES users (tg_id) ON DELETE CASCADE,
+    CONSTRAINT check_watchlist_priority CHECK (priority IN ('HIGH', 'NORMAL', 'LOW'))
 );
 
 CREATE UNIQUE INDEX idx_watchlists_user_show_api_unique ON watchlists USING btree (user_id, show_api_id, type) WHERE deleted_at IS NULL;
 
 COMMENT ON TABLE watchlists IS 'Stores shows and movies users want to watch';
 
+-- public.user_lists definition, named lists of users next to the default watchlist
+CREATE TABLE IF NOT EXISTS user_lists
+(
+    id         UUID        NOT NULL DEFAULT gen_random_uuid(),
+    user_id    BIGINT      NOT NULL,
+    name       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT user_lists_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_user_lists_user FOREIGN KEY (user_id) REFERENCES users (tg_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_user_lists_user_name_unique ON user_lists USING btree (user_id, lower(name));
+
+COMMENT ON TABLE user_lists IS 'Stores custom lists users create besides their watchlist';
+
+-- public.user_list_items definition, a title can be on many lists
+CREATE TABLE IF NOT EXISTS user_list_items
+(
+    id          UUID        NOT NULL DEFAULT gen_random_uuid(),
+    list_id     UUID        NOT NULL,
+    show_api_id BIGINT      NOT NULL,
+    type        TEXT        NOT NULL,
+    title       TEXT        NOT NULL,
+    image       TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT user_list_items_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_user_list_items_list FOREIGN KEY (list_id) REFERENCES user_lists (id) ON DELETE CASCADE,
+    CONSTRAINT user_list_items_list_title_unique UNIQUE (list_id, show_api_id, type)
+);
+
+COMMENT ON TABLE user_list_items IS 'Stores the movies and shows on custom lists';
+
+-- public.episode_schedules definition, shared upcoming air dates of tracked shows
+CREATE TABLE IF NOT EXISTS episode_schedules
+(
+    id             UUID        NOT NULL DEFAULT gen_random_uuid(),
+    show_api_id    BIGINT      NOT NULL,
+    show_name      TEXT        NOT NULL,
+    season_number  INT         NOT NULL,
+    episode_number INT         NOT NULL,
+    episode_name   TEXT,
+    air_date       DATE        NOT NULL,
+    runtime        INT,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT episode_schedules_pkey PRIMARY KEY (id),
+    CONSTRAINT episode_schedules_show_episode_unique UNIQUE (show_api_id, season_number, episode_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_episode_schedules_air_date ON episode_schedules (air_date);
+
+COMMENT ON TABLE episode_schedules IS 'Stores air dates of episodes for shows tracked by users';
+
+-- public.episode_reminders definition, one row per reminder sent to a user
+CREATE TABLE IF NOT EXISTS episode_reminders
+(
+    id             UUID        NOT NULL DEFAULT gen_random_uuid(),
+    user_id        BIGINT      NOT NULL,
+    show_api_id    BIGINT      NOT NULL,
+    season_number  INT         NOT NULL,
+    episode_number INT         NOT NULL,
+    sent_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    watched_at     TIMESTAMPTZ,
+
+    CONSTRAINT episode_reminders_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_episode_reminders_user FOREIGN KEY (user_id) REFERENCES users (tg_id) ON DELETE CASCADE,
+    CONSTRAINT episode_reminders_user_episode_unique UNIQUE (user_id, show_api_id, season_number, episode_number)
+);
+
+COMMENT ON TABLE episode_reminders IS 'Stores air day reminders sent to users and whether they watched the episode';
+
+-- public.episode_watches definition, one row per episode a user has watched
+CREATE TABLE IF NOT EXISTS episode_watches
+(
+    id             UUID        NOT NULL DEFAULT gen_random_uuid(),
+    user_id        BIGINT      NOT NULL,
+    show_api_id    BIGINT      NOT NULL,
+    season_number  INT         NOT NULL,
+    episode_number INT         NOT NULL,
+    runtime        INT         NOT NULL DEFAULT 0,
+    watched_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT episode_watches_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_episode_watches_user FOREIGN KEY (user_id) REFERENCES users (tg_id) ON DELETE CASCADE,
+    CONSTRAINT episode_watches_user_episode_unique UNIQUE (user_id, show_api_id, season_number, episode_number)
+);
+
+COMMENT ON TABLE episode_watches IS 'Stores every episode a user has watched, the totals of tv_shows are derived from it';
+
+-- public.user_settings definition, notification preferences of a user
+CREATE TABLE IF NOT EXISTS user_settings
+(
+    id                       UUID        NOT NULL DEFAULT gen_random_uuid(),
+    user_id                  BIGINT      NOT NULL,
+    timezone                 TEXT        NOT NULL DEFAULT 'UTC',
+    quiet_hours_start        INT,
+    quiet_hours_end          INT,
+    notify_new_seasons       BOOLEAN     NOT NULL DEFAULT TRUE,
+    notify_status_changes    BOOLEAN     NOT NULL DEFAULT TRUE,
+    notify_episode_reminders BOOLEAN     NOT NULL DEFAULT TRUE,
+    notify_releases          BOOLEAN     NOT NULL DEFAULT TRUE,
+    notify_digest            BOOLEAN     NOT NULL DEFAULT TRUE,
+    digest_mode              TEXT        NOT NULL DEFAULT 'off',
+    -- TMDB watch region and the provider IDs of the streaming services the user pays for
+    region                   TEXT        NOT NULL DEFAULT 'US',
+    subscribed_providers     INT[]       NOT NULL DEFAULT '{}',
+    notify_availability      BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at               TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at               TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT user_settings_pkey PRIMARY KEY (id),
+    CONSTRAINT user_settings_user_unique UNIQUE (user_id),
+    CONSTRAINT fk_user_settings_user FOREIGN KEY (user_id) REFERENCES users (tg_id) ON DELETE CASCADE,
+    CONSTRAINT user_settings_quiet_hours_check CHECK (
+        (quiet_hours_start IS NULL AND quiet_hours_end IS NULL) OR
+        (quiet_hours_start BETWEEN 0 AND 23 AND quiet_hours_end BETWEEN 0 AND 23)
+        ),
+    CONSTRAINT user_settings_digest_mode_check CHECK (digest_mode IN ('off', 'cycle', 'weekly'))
+);
+
+COMMENT ON TABLE user_settings IS 'Stores timezone, region, streaming services, quiet hours, digest mode and notification toggles of users';
+
+-- public.show_notification_states definition, what each user was last told about a show
+CREATE TABLE IF NOT EXISTS show_notification_states
+(
+    id                    UUID        NOT NULL DEFAULT gen_random_uuid(),
+    user_id               BIGINT      NOT NULL,
+    show_api_id           BIGINT      NOT NULL,
+    last_notified_seasons INT         NOT NULL DEFAULT 0,
+    snoozed_until         TIMESTAMPTZ,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT show_notification_states_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_show_notification_states_user FOREIGN KEY (user_id) REFERENCES users (tg_id) ON DELETE CASCADE,
+    CONSTRAINT show_notification_states_user_show_unique UNIQUE (user_id, show_api_id)
+);
+
+COMMENT ON TABLE show_notification_states IS 'Stores the last season count each user was notified about and snoozed alerts';
+
+-- public.show_check_schedules definition, when each tracked show is fetched from TMDB next
+CREATE TABLE IF NOT EXISTS show_check_schedules
+(
+    show_api_id     BIGINT      NOT NULL,
+    show_status     TEXT        NOT NULL,
+    next_check_at   TIMESTAMPTZ NOT NULL,
+    last_checked_at TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT show_check_schedules_pkey PRIMARY KEY (show_api_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_show_check_schedules_next_check_at ON show_check_schedules (next_check_at);
+
+COMMENT ON TABLE show_check_schedules IS 'Per-show next check times derived from the TMDB status and the next air date';
+
+-- public.notifications definition, outbox of messages produced by workers
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id              UUID        NOT NULL DEFAULT gen_random_uuid(),
+    user_id         BIGINT      NOT NULL,
+    kind            TEXT        NOT NULL,
+    show_api_id     BIGINT,
+    text            TEXT        NOT NULL,
+    image_path      TEXT,
+    reply_markup    JSONB,
+    status          TEXT        NOT NULL DEFAULT 'pending',
+    attempts        INT         NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error      TEXT,
+    sent_at         TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- name of the show at the time the update was held, the show may be gone when the digest goes out
+    show_name       TEXT,
+    -- set once the poster of a text too long for a caption went out, retries then only send the text
+    poster_sent     BOOLEAN     NOT NULL DEFAULT FALSE,
+
+    CONSTRAINT notifications_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_notifications_user FOREIGN KEY (user_id) REFERENCES users (tg_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_status_next_attempt ON notifications (status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_notifications_kind_show ON notifications (kind, show_api_id);
+
+COMMENT ON TABLE notifications IS 'Outbox of worker messages with their delivery status';
+
 -- Create worker_states table to track the state of workers
 CREATE TABLE IF NOT EXISTS worker_states
 (
@@ -95,6 +346,8 @@ CREATE TABLE IF NOT EXISTS worker_states
     error           TEXT,
     shows_checked   INTEGER                  NOT NULL DEFAULT 0,
     updates_found   INTEGER                  NOT NULL DEFAULT 0,
+    cycle_cursor    BIGINT,
+    paused          BOOLEAN                  NOT NULL DEFAULT FALSE,
     created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
     updated_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
 );
@@ -117,6 +370,7 @@ CREATE TABLE IF NOT EXISTS worker_tasks
     user_id       BIGINT,
     shows_checked INTEGER,
     updates_found INTEGER,
+    api_calls_saved INTEGER,
     created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
 
     CONSTRAINT fk_worker_tasks_worker_id FOREIGN KEY (worker_id) REFERENCES worker_states (worker_id) ON DELETE NO ACTION
@@ -127,8 +381,63 @@ CREATE INDEX IF NOT EXISTS idx_worker_tasks_worker_id ON worker_tasks (worker_id
 CREATE INDEX IF NOT EXISTS idx_worker_tasks_status ON worker_tasks (status);
 CREATE INDEX IF NOT EXISTS idx_worker_tasks_created_at ON worker_tasks (created_at);
 
--- Create a view for easy monitoring of worker performance
+-- public.worker_leases definition, one row per worker type naming the instance allowed to run it
+CREATE TABLE IF NOT EXISTS worker_leases
+(
+    worker_type VARCHAR(50)              NOT NULL PRIMARY KEY,
+    holder_id   VARCHAR(255)             NOT NULL,
+    expires_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE worker_leases IS 'Leases electing the single bot instance that runs each worker type';
+
+-- public.worker_task_daily_stats definition, finished tasks rolled up per day once they pass the retention period
+CREATE TABLE IF NOT EXISTS worker_task_daily_stats
+(
+    worker_id         VARCHAR(255)             NOT NULL,
+    task_type         VARCHAR(50)              NOT NULL,
+    day               DATE                     NOT NULL,
+    task_count        INTEGER                  NOT NULL DEFAULT 0,
+    error_count       INTEGER                  NOT NULL DEFAULT 0,
+    timed_count       INTEGER                  NOT NULL DEFAULT 0,
+    total_duration_ms BIGINT                   NOT NULL DEFAULT 0,
+    max_duration_ms   BIGINT,
+    shows_checked     INTEGER                  NOT NULL DEFAULT 0,
+    updates_found     INTEGER                  NOT NULL DEFAULT 0,
+    api_calls_saved   INTEGER                  NOT NULL DEFAULT 0,
+    last_task_time    TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (worker_id, task_type, day),
+    CONSTRAINT fk_worker_task_daily_stats_worker_id FOREIGN KEY (worker_id) REFERENCES worker_states (worker_id) ON DELETE NO ACTION
+);
+
+COMMENT ON TABLE worker_task_daily_stats IS 'Daily aggregates of worker tasks removed by the retention job';
+
+-- Create a view for easy monitoring of worker performance, rolled up tasks count next to the raw ones
 CREATE OR REPLACE VIEW worker_performance AS
+WITH task_totals AS (SELECT worker_id,
+                            COUNT(id)                                         AS task_count,
+                            COUNT(duration_ms)                                AS timed_count,
+                            SUM(duration_ms)                                  AS total_duration_ms,
+                            MAX(duration_ms)                                  AS max_duration_ms,
+                            SUM(CASE WHEN status = 'error' THEN 1 ELSE 0 END) AS error_count,
+                            MAX(created_at)                                   AS last_task_time
+                     FROM worker_tasks
+                     GROUP BY worker_id
+                     UNION ALL
+                     SELECT worker_id,
+                            SUM(task_count),
+                            SUM(timed_count),
+                            SUM(total_duration_ms),
+                            MAX(max_duration_ms),
+                            SUM(error_count),
+                            MAX(last_task_time)
+                     FROM worker_task_daily_stats
+                     GROUP BY worker_id)
 SELECT w.worker_id,
        w.worker_type,
        w.status,
@@ -137,16 +446,17 @@ SELECT w.worker_id,
        w.shows_checked,
        w.updates_found,
        w.error,
-       COUNT(t.id)                                         AS total_tasks,
-       AVG(t.duration_ms)                                  AS avg_task_duration_ms,
-       MAX(t.duration_ms)                                  AS max_task_duration_ms,
-       SUM(CASE WHEN t.status = 'error' THEN 1 ELSE 0 END) AS error_tasks,
-       MAX(t.created_at)                                   AS last_task_time
+       COALESCE(SUM(t.task_count), 0)::BIGINT                                              AS total_tasks,
+       COALESCE(SUM(t.total_duration_ms) / NULLIF(SUM(t.timed_count), 0), 0)::NUMERIC       AS avg_task_duration_ms,
+       COALESCE(MAX(t.max_duration_ms), 0)::BIGINT                                         AS max_task_duration_ms,
+       COALESCE(SUM(t.error_count), 0)::BIGINT                                             AS error_tasks,
+       MAX(t.last_task_time)                                                               AS last_task_time,
+       w.paused
 FROM worker_states w
          LEFT JOIN
-     worker_tasks t ON w.worker_id = t.worker_id
+     task_totals t ON w.worker_id = t.worker_id
 GROUP BY w.id, w.worker_id, w.worker_type, w.status, w.last_check_time,
-         w.next_check_time, w.shows_checked, w.updates_found, w.error;
+         w.next_check_time, w.shows_checked, w.updates_found, w.error, w.paused;
 
 -- Function for updating timestamps
 CREATE OR REPLACE FUNCTION update_modified_column()
@@ -181,4 +491,58 @@ CREATE TRIGGER update_watchlists_timestamp
     BEFORE UPDATE
     ON watchlists
     FOR EACH ROW
-EXECUTE FUNCTION update_modified_column();
\ No newline at end of file
+EXECUTE FUNCTION update_modified_column();
+
+CREATE TRIGGER update_episode_schedules_timestamp
+    BEFORE UPDATE
+    ON episode_schedules
+    FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
+
+CREATE TRIGGER update_notifications_timestamp
+    BEFORE UPDATE
+    ON notifications
+    FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
+
+CREATE TRIGGER update_show_notification_states_timestamp
+    BEFORE UPDATE
+    ON show_notification_states
+    FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
+
+CREATE TRIGGER update_user_settings_timestamp
+    BEFORE UPDATE
+    ON user_settings
+    FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
+
+CREATE TRIGGER update_worker_leases_timestamp
+    BEFORE UPDATE
+    ON worker_leases
+    FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
+
+CREATE TRIGGER update_worker_task_daily_stats_timestamp
+    BEFORE UPDATE
+    ON worker_task_daily_stats
+    FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
+
+CREATE TRIGGER update_show_check_schedules_timestamp
+    BEFORE UPDATE
+    ON show_check_schedules
+    FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
+
+CREATE TRIGGER update_ratings_timestamp
+    BEFORE UPDATE
+    ON ratings
+    FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
+
+CREATE TRIGGER update_user_lists_timestamp
+    BEFORE UPDATE
+    ON user_lists
+    FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
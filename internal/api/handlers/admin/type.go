@@ -0,0 +1,28 @@
+package admin
+
+import (
+	"github.com/erkinov-wtf/movie-manager-bot/internal/api/interfaces"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/workers"
+)
+
+type AdminHandler struct {
+	app       *app.App
+	scheduler *workers.Scheduler
+}
+
+func NewAdminHandler(app *app.App, scheduler *workers.Scheduler) interfaces.AdminInterface {
+	return &AdminHandler{
+		app:       app,
+		scheduler: scheduler,
+	}
+}
+
+const (
+	// recentTasksWindow is how many of the latest tasks of a worker are searched for failures
+	recentTasksWindow = 50
+	// maxFailures is how many recent failed tasks are listed per worker
+	maxFailures = 3
+	// maxErrorLength keeps long task errors from flooding the overview
+	maxErrorLength = 80
+)
@@ -0,0 +1,313 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/workers"
+	"gopkg.in/telebot.v3"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const workerTimeFormat = "2006-01-02 15:04 MST"
+
+func (h *AdminHandler) Worker(ctx telebot.Context) error {
+	const op = "admin.Worker"
+	h.app.Logger.Info(op, ctx, "Worker command received")
+
+	payload := strings.Fields(ctx.Message().Payload)
+	if len(payload) > 0 && payload[0] == "recheck" {
+		return h.handleRecheck(ctx, payload[1:])
+	}
+
+	text, btn, err := h.workerOverview(ctx)
+	if err != nil {
+		return ctx.Send(messages.InternalError)
+	}
+
+	if err = ctx.Send(text, btn); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send worker overview", "error", err.Error())
+		return err
+	}
+
+	h.app.Logger.Info(op, ctx, "Worker overview sent successfully")
+	return nil
+}
+
+func (h *AdminHandler) WorkerCallback(ctx telebot.Context) error {
+	const op = "admin.WorkerCallback"
+	callback := ctx.Callback()
+	trimmed := strings.TrimSpace(callback.Data)
+	h.app.Logger.Info(op, ctx, "Processing worker callback", "callback_data", trimmed)
+
+	if !strings.HasPrefix(trimmed, "worker|") {
+		h.app.Logger.Warning(op, ctx, "Invalid callback prefix", "callback_data", trimmed)
+		return ctx.Send(messages.InternalError)
+	}
+
+	dataParts := strings.Split(trimmed, "|")
+	if len(dataParts) != 3 {
+		h.app.Logger.Warning(op, ctx, "Malformed callback data", "callback_data", callback.Data,
+			"parts_count", len(dataParts))
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	action := dataParts[1]
+	workerType := dataParts[2]
+	h.app.Logger.Debug(op, ctx, "Processing callback action", "action", action, "worker_type", workerType)
+
+	switch action {
+	case "menu":
+		return h.refreshOverview(ctx, "")
+
+	case "run":
+		return h.handleRun(ctx, workerType)
+
+	case "pause":
+		return h.handlePause(ctx, workerType, true)
+
+	case "resume":
+		return h.handlePause(ctx, workerType, false)
+
+	case "recheck":
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.WorkerRecheckUsage, ShowAlert: true})
+
+	case "close":
+		if err := ctx.Delete(); err != nil {
+			h.app.Logger.Error(op, ctx, "Failed to delete worker overview", "error", err.Error())
+		}
+		return ctx.Respond()
+
+	default:
+		h.app.Logger.Warning(op, ctx, "Unknown callback action", "action", action)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.UnknownAction})
+	}
+}
+
+func (h *AdminHandler) handleRun(ctx telebot.Context, workerType string) error {
+	const op = "admin.handleRun"
+	err := h.scheduler.RunNow(workerType)
+	switch {
+	case err == nil:
+		h.app.Logger.Info(op, ctx, "Manual worker run requested", "worker_type", workerType)
+		return h.refreshOverview(ctx, messages.WorkerRunStarted)
+	case errors.Is(err, workers.ErrNotLeader):
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.WorkerNotLeader})
+	case errors.Is(err, workers.ErrJobRunning):
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.WorkerAlreadyRunning})
+	case errors.Is(err, workers.ErrUnknownJob):
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.WorkerNotScheduled})
+	default:
+		h.app.Logger.Error(op, ctx, "Failed to start worker run", "worker_type", workerType, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+}
+
+func (h *AdminHandler) handlePause(ctx telebot.Context, workerType string, paused bool) error {
+	const op = "admin.handlePause"
+	ctxDb, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if _, err := h.app.Repository.Worker.SetWorkerPaused(ctxDb, workerType, paused); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to change worker pause",
+			"worker_type", workerType, "paused", paused, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+
+	h.app.Logger.Info(op, ctx, "Worker pause changed", "worker_type", workerType, "paused", paused)
+	if paused {
+		return h.refreshOverview(ctx, messages.WorkerPaused)
+	}
+	return h.refreshOverview(ctx, messages.WorkerResumed)
+}
+
+func (h *AdminHandler) handleRecheck(ctx telebot.Context, args []string) error {
+	const op = "admin.handleRecheck"
+	if len(args) != 2 {
+		return ctx.Send(messages.WorkerRecheckUsage)
+	}
+
+	userId, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return ctx.Send(messages.WorkerRecheckUsage)
+	}
+	showId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return ctx.Send(messages.WorkerRecheckUsage)
+	}
+
+	h.app.Logger.Info(op, ctx, "Re-checking show for user", "target_user_id", userId, "show_id", showId)
+	updated, err := h.scheduler.RecheckShow(userId, showId)
+	switch {
+	case errors.Is(err, workers.ErrShowNotTracked):
+		return ctx.Send(messages.ShowNotTracked)
+	case errors.Is(err, workers.ErrNotLeader):
+		return ctx.Send(messages.WorkerNotLeader)
+	case err != nil:
+		h.app.Logger.Error(op, ctx, "Failed to re-check show",
+			"target_user_id", userId, "show_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	case updated:
+		return ctx.Send(messages.WorkerRecheckUpdated)
+	default:
+		return ctx.Send(messages.WorkerRecheckNoUpdates)
+	}
+}
+
+// refreshOverview redraws the overview in place and answers the callback with an optional toast
+func (h *AdminHandler) refreshOverview(ctx telebot.Context, notice string) error {
+	const op = "admin.refreshOverview"
+	text, btn, err := h.workerOverview(ctx)
+	if err != nil {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+
+	if err = ctx.Edit(text, btn); err != nil && !errors.Is(err, telebot.ErrSameMessageContent) {
+		h.app.Logger.Error(op, ctx, "Failed to edit worker overview", "error", err.Error())
+		return err
+	}
+
+	return ctx.Respond(&telebot.CallbackResponse{Text: notice})
+}
+
+// workerOverview builds the status of every worker with its recent failures and the admin buttons
+func (h *AdminHandler) workerOverview(ctx telebot.Context) (string, *telebot.ReplyMarkup, error) {
+	const op = "admin.workerOverview"
+	ctxDb, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	states, err := h.app.Repository.Worker.GetWorkerPerformance(ctxDb)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to get worker performance", "error", err.Error())
+		return "", nil, err
+	}
+
+	var text strings.Builder
+	text.WriteString("⚙️ Workers\n")
+	if len(states) == 0 {
+		text.WriteString("\nNo workers have reported yet\n")
+	}
+
+	// Several instances may report the same worker type, buttons act on the type
+	var workerTypes []string
+	pausedTypes := make(map[string]bool)
+	for _, state := range states {
+		if _, seen := pausedTypes[state.WorkerType]; !seen {
+			workerTypes = append(workerTypes, state.WorkerType)
+		}
+		pausedTypes[state.WorkerType] = pausedTypes[state.WorkerType] || state.Paused
+
+		failures, err := h.recentFailures(ctxDb, state.WorkerID)
+		if err != nil {
+			h.app.Logger.Error(op, ctx, "Failed to get recent tasks",
+				"worker_id", state.WorkerID, "error", err.Error())
+		}
+		text.WriteString(formatWorker(state, failures))
+	}
+
+	btn := &telebot.ReplyMarkup{}
+	var btnRows []telebot.Row
+	for _, workerType := range workerTypes {
+		var row telebot.Row
+		if h.scheduler.IsScheduled(workerType) {
+			row = append(row, btn.Data("▶️ Run "+workerType, "", "worker|run|"+workerType))
+		}
+		if pausedTypes[workerType] {
+			row = append(row, btn.Data("⏯ Resume", "", "worker|resume|"+workerType))
+		} else {
+			row = append(row, btn.Data("⏸ Pause", "", "worker|pause|"+workerType))
+		}
+		btnRows = append(btnRows, row)
+	}
+	btnRows = append(btnRows,
+		btn.Row(
+			btn.Data("🔄 Refresh", "", "worker|menu|"),
+			btn.Data("🔁 Re-check a show", "", "worker|recheck|"),
+		),
+		btn.Row(btn.Data("✖️ Close", "", "worker|close|")),
+	)
+	btn.Inline(btnRows...)
+
+	return text.String(), btn, nil
+}
+
+func (h *AdminHandler) recentFailures(ctx context.Context, workerId string) ([]database.WorkerTask, error) {
+	tasks, err := h.app.Repository.Worker.GetRecentTasks(ctx, database.GetRecentTasksParams{
+		WorkerID: workerId,
+		Limit:    recentTasksWindow,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var failed []database.WorkerTask
+	for _, task := range tasks {
+		if task.Status == workers.TaskStatusError {
+			failed = append(failed, task)
+		}
+		if len(failed) == maxFailures {
+			break
+		}
+	}
+	return failed, nil
+}
+
+func formatWorker(state database.WorkerPerformance, failures []database.WorkerTask) string {
+	icon := "🟢"
+	switch {
+	case state.Paused:
+		icon = "⏸"
+	case state.Status == workers.StatusRunning:
+		icon = "🔄"
+	case state.Status == workers.StatusError:
+		icon = "🔴"
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("\n%s %s (%s)\n", icon, state.WorkerType, state.WorkerID))
+	text.WriteString(fmt.Sprintf("Status: %s", state.Status))
+	if state.Paused {
+		text.WriteString(", paused")
+	}
+	text.WriteString("\n")
+	text.WriteString(fmt.Sprintf("Last check: %s\n", formatTime(state.LastCheckTime.Time, state.LastCheckTime.Valid)))
+	text.WriteString(fmt.Sprintf("Next check: %s\n", formatTime(state.NextCheckTime.Time, state.NextCheckTime.Valid)))
+	text.WriteString(fmt.Sprintf("Last cycle: %d checked, %d updates\n", state.ShowsChecked, state.UpdatesFound))
+	text.WriteString(fmt.Sprintf("Tasks: %d total, %d failed\n", state.TotalTasks, state.ErrorTasks))
+	if state.Error != nil {
+		text.WriteString(fmt.Sprintf("Error: %s\n", truncate(*state.Error)))
+	}
+
+	if len(failures) > 0 {
+		text.WriteString("Recent failures:\n")
+		for _, task := range failures {
+			taskError := "unknown error"
+			if task.Error != nil {
+				taskError = truncate(*task.Error)
+			}
+			text.WriteString(fmt.Sprintf("└ %s %s: %s\n",
+				task.StartTime.Time.UTC().Format(workerTimeFormat), task.TaskType, taskError))
+		}
+	}
+
+	return text.String()
+}
+
+func formatTime(t time.Time, valid bool) string {
+	if !valid {
+		return "never"
+	}
+	return t.UTC().Format(workerTimeFormat)
+}
+
+func truncate(text string) string {
+	runes := []rune(text)
+	if len(runes) <= maxErrorLength {
+		return text
+	}
+	return string(runes[:maxErrorLength]) + "…"
+}
@@ -3,7 +3,9 @@ package info
 import (
 	"context"
 	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
 	"gopkg.in/telebot.v3"
 	"strconv"
 	"strings"
@@ -53,11 +55,28 @@ func (h *InfoHandler) handleTVDetails(ctx telebot.Context, msgId string) error {
 		return ctx.Send(messages.InternalError)
 	}
 
-	info := tvStats{}
+	info := tvStats{byStatus: make(map[string]int), byWatchStatus: make(map[string]int)}
 	h.app.Logger.Debug(op, ctx, "Calculating TV show statistics")
+	var showList strings.Builder
 	for _, s := range watchedShows {
 		info.amount++
 		info.totalTime += s.Runtime
+		info.byStatus[s.Status]++
+		info.byWatchStatus[s.WatchStatus]++
+
+		if info.amount <= maxListedShows {
+			showList.WriteString(fmt.Sprintf("└ %s - %d seasons - _%s_\n", s.Name, s.Seasons, s.Status))
+		}
+	}
+	if info.amount > maxListedShows {
+		showList.WriteString(fmt.Sprintf("└ ...and %d more\n", info.amount-maxListedShows))
+	}
+
+	h.app.Logger.Debug(op, ctx, "Retrieving user TV show ratings from database")
+	ratings, err := h.ratingSection(ctxDb, ctx.Sender().ID, constants.TVShowType)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to retrieve user TV show ratings", "error", err.Error())
+		return ctx.Send(messages.InternalError)
 	}
 
 	msgID, _ := strconv.Atoi(msgId)
@@ -71,10 +90,21 @@ func (h *InfoHandler) handleTVDetails(ctx telebot.Context, msgId string) error {
 └ 🕙 Total Time Wasted: *%d* minutes
 └ ⌛️ Time Breakdown: *%s*
 
+🏷 *Your Progress:*
+%s
+📜 *By Status:*
+%s
+🎬 *Your Shows:*
+%s
+%s
 🎯 *Achievement:* You've spent *%d* hours watching TV shows! Keep ruining your precious time! 👍`,
 		info.amount,
 		info.totalTime,
 		formattedTime,
+		formatWatchStatusBreakdown(info.byWatchStatus),
+		formatStatusBreakdown(info.byStatus),
+		showList.String(),
+		ratings,
 		info.totalTime/60,
 	)
 
@@ -95,18 +125,18 @@ func (h *InfoHandler) handleMovieDetails(ctx telebot.Context, msgId string) erro
 	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
-	h.app.Logger.Debug(op, ctx, "Retrieving user movies from database")
-	watchedMovies, err := h.app.Repository.Movies.GetUserMovies(ctxDb, ctx.Sender().ID)
+	h.app.Logger.Debug(op, ctx, "Retrieving user movie watches from database")
+	info, err := h.movieStats(ctxDb, ctx.Sender().ID)
 	if err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to retrieve user movies", "error", err.Error())
+		h.app.Logger.Error(op, ctx, "Failed to retrieve user movie watches", "error", err.Error())
 		return ctx.Send(messages.InternalError)
 	}
 
-	info := movieStats{}
-	h.app.Logger.Debug(op, ctx, "Calculating movie statistics")
-	for _, s := range watchedMovies {
-		info.amount++
-		info.totalTime += s.Runtime
+	h.app.Logger.Debug(op, ctx, "Retrieving user movie ratings from database")
+	ratings, err := h.ratingSection(ctxDb, ctx.Sender().ID, constants.MovieType)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to retrieve user movie ratings", "error", err.Error())
+		return ctx.Send(messages.InternalError)
 	}
 
 	msgID, _ := strconv.Atoi(msgId)
@@ -117,13 +147,17 @@ func (h *InfoHandler) handleMovieDetails(ctx telebot.Context, msgId string) erro
 
 📊 *Statistics:*
 └ 📝 Movies Watched: *%d*
+└ 🔁 Rewatches: *%d*
 └ 🕙 Total Time Wasted: *%d* minutes
 └ ⌛️ Time Breakdown: *%s*
 
+%s
 🎯 *Achievement:* You've spent *%d* hours watching movies! Keep ruining your precious time! 👍`,
 		info.amount,
+		info.rewatches,
 		info.totalTime,
 		formattedTime,
+		ratings,
 		info.totalTime/60,
 	)
 
@@ -146,10 +180,10 @@ func (h *InfoHandler) handleFullDetails(ctx telebot.Context, data string) error
 	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
-	h.app.Logger.Debug(op, ctx, "Retrieving user movies from database")
-	watchedMovies, err := h.app.Repository.Movies.GetUserMovies(ctxDb, ctx.Sender().ID)
+	h.app.Logger.Debug(op, ctx, "Retrieving user movie watches from database")
+	movieInfo, err := h.movieStats(ctxDb, ctx.Sender().ID)
 	if err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to retrieve user movies", "error", err.Error())
+		h.app.Logger.Error(op, ctx, "Failed to retrieve user movie watches", "error", err.Error())
 		return ctx.Send(messages.InternalError)
 	}
 
@@ -160,11 +194,11 @@ func (h *InfoHandler) handleFullDetails(ctx telebot.Context, data string) error
 		return ctx.Send(messages.InternalError)
 	}
 
-	h.app.Logger.Debug(op, ctx, "Calculating movie statistics")
-	movieInfo := movieStats{}
-	for _, s := range watchedMovies {
-		movieInfo.amount++
-		movieInfo.totalTime += s.Runtime
+	h.app.Logger.Debug(op, ctx, "Retrieving user ratings from database")
+	ratings, err := h.ratingSection(ctxDb, ctx.Sender().ID, constants.AllType)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to retrieve user ratings", "error", err.Error())
+		return ctx.Send(messages.InternalError)
 	}
 
 	h.app.Logger.Debug(op, ctx, "Calculating TV show statistics")
@@ -189,6 +223,7 @@ func (h *InfoHandler) handleFullDetails(ctx telebot.Context, data string) error
 🎥 *Movies - Total Info*
 📊 *Statistics:*
 └ 📝 Movies Watched: *%d*
+└ 🔁 Rewatches: *%d*
 └ 🕙 Total Time Wasted: *%d* minutes
 └ ⌛️ Time Breakdown: *%s*
 
@@ -203,8 +238,10 @@ func (h *InfoHandler) handleFullDetails(ctx telebot.Context, data string) error
 └ 🕙 Total Time Wasted: *%d* minutes
 └ ⌛️ Total Time Breakdown: *%s*
 
+%s
 🎯 *Achievement:* You've spent *%d* hours watching movies and TV shows! Keep ruining your precious time! 👍`,
 		movieInfo.amount,
+		movieInfo.rewatches,
 		movieInfo.totalTime,
 		movieFormattedTime,
 		tvInfo.amount,
@@ -213,6 +250,7 @@ func (h *InfoHandler) handleFullDetails(ctx telebot.Context, data string) error
 		movieInfo.amount+tvInfo.amount,
 		totalTime,
 		totalFormattedTime,
+		ratings,
 		totalTime/60,
 	)
 
@@ -268,6 +306,93 @@ func (h *InfoHandler) InfoCallback(ctx telebot.Context) error {
 	}
 }
 
+// movieStats sums up the watched movies of a user, rewatches add to the time spent
+func (h *InfoHandler) movieStats(ctx context.Context, userId int64) (movieStats, error) {
+	totals, err := h.app.Repository.WatchEvents.GetWatchTotals(ctx, userId, constants.MovieType)
+	if err != nil {
+		return movieStats{}, err
+	}
+
+	return movieStats{
+		amount:    int(totals.Titles),
+		rewatches: int(totals.Watches - totals.Titles),
+		totalTime: totals.Runtime,
+	}, nil
+}
+
+// ratingSection summarizes the title ratings of one type and lists the best rated titles, constants.AllType covers both
+func (h *InfoHandler) ratingSection(ctx context.Context, userId int64, showType string) (string, error) {
+	summary, err := h.app.Repository.Ratings.GetRatingSummary(ctx, userId, showType)
+	if err != nil {
+		return "", err
+	}
+	if summary.Rated == 0 {
+		return "⭐ *Ratings:*\n└ Nothing rated yet\n", nil
+	}
+
+	topRated, err := h.app.Repository.Ratings.GetTopRated(ctx, userId, showType, maxTopRated)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("⭐ *Ratings:*\n└ 📝 Titles Rated: *%d*\n└ 📈 Average Rating: *%.1f*/10\n\n🏆 *Top Rated:*\n",
+		summary.Rated, summary.Average))
+	for _, title := range topRated {
+		emoji := "🎥"
+		if title.Type == constants.TVShowType {
+			emoji = "📺"
+		}
+		name := title.Title
+		if name == "" {
+			name = "Unknown title"
+		}
+		sb.WriteString(fmt.Sprintf("└ %s %s - *%d*/10\n", emoji, name, title.Rating))
+	}
+
+	return sb.String(), nil
+}
+
+// formatStatusBreakdown lists show counts per status, airing statuses first
+func formatStatusBreakdown(byStatus map[string]int) string {
+	order := []string{
+		constants.ShowStatusReturning,
+		constants.ShowStatusInProduction,
+		constants.ShowStatusPlanned,
+		constants.ShowStatusPilot,
+		constants.ShowStatusEnded,
+		constants.ShowStatusCanceled,
+	}
+
+	var sb strings.Builder
+	for _, status := range order {
+		if count, ok := byStatus[status]; ok {
+			sb.WriteString(fmt.Sprintf("└ %s: *%d*\n", status, count))
+			delete(byStatus, status)
+		}
+	}
+	for status, count := range byStatus {
+		if status == "" {
+			status = "Unknown"
+		}
+		sb.WriteString(fmt.Sprintf("└ %s: *%d*\n", status, count))
+	}
+
+	return sb.String()
+}
+
+// formatWatchStatusBreakdown lists show counts per watch status of the user, skipping the empty ones
+func formatWatchStatusBreakdown(byWatchStatus map[string]int) string {
+	var sb strings.Builder
+	for _, status := range utils.WatchStatuses {
+		if count := byWatchStatus[status]; count > 0 {
+			sb.WriteString(fmt.Sprintf("└ %s: *%d*\n", utils.WatchStatusLabel(status), count))
+		}
+	}
+
+	return sb.String()
+}
+
 func formatDuration(minutes int32) string {
 	days := minutes / (24 * 60)
 	remainingMinutes := minutes % (24 * 60)
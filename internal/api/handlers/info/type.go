@@ -16,11 +16,20 @@ func NewInfoHandler(app *app.App) interfaces.InfoInterface {
 }
 
 type tvStats struct {
-	amount    int
-	totalTime int32
+	amount        int
+	totalTime     int32
+	byStatus      map[string]int
+	byWatchStatus map[string]int
 }
 
+// maxListedShows limits the per-show listing so the message stays within Telegram's size limit
+const maxListedShows = 30
+
+// maxTopRated is the number of titles listed under the best rated ones
+const maxTopRated = 5
+
 type movieStats struct {
 	amount    int
+	rewatches int
 	totalTime int32
 }
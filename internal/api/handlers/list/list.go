@@ -0,0 +1,578 @@
+package list
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/movie"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/tv"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/paginators"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gopkg.in/telebot.v3"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+func (h *ListHandler) Lists(ctx telebot.Context) error {
+	const op = "list.Lists"
+	h.app.Logger.Info(op, ctx, "Lists command received")
+
+	msg, err := ctx.Bot().Send(ctx.Chat(), messages.Loading)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send loading message", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	btn, err := h.overviewKeyboard(ctx.Sender().ID)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to load lists", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	if _, err = ctx.Bot().Edit(msg, messages.ListsOverview, btn, telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to edit message with lists", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "Lists displayed successfully")
+	return nil
+}
+
+// overviewKeyboard lists the watchlist first and the custom lists after it, the watchlist opens as a list page like
+// the others
+func (h *ListHandler) overviewKeyboard(userId int64) (*telebot.ReplyMarkup, error) {
+	ctxDb, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	watchlist, err := h.app.Repository.Watchlists.GetUserWatchlists(ctxDb, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching watchlist: %w", err)
+	}
+
+	lists, err := h.app.Repository.Lists.GetUserLists(ctxDb, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching lists: %w", err)
+	}
+
+	btn := &telebot.ReplyMarkup{}
+	rows := []telebot.Row{
+		btn.Row(btn.Data(fmt.Sprintf("%s (%d)", watchlistName, len(watchlist)), "", fmt.Sprintf("list|open|%s-1", defaultListKey))),
+	}
+	for _, list := range lists {
+		rows = append(rows, btn.Row(btn.Data(fmt.Sprintf("📂 %s (%d)", list.Name, list.Items), "",
+			fmt.Sprintf("list|open|%s-1", listKey(list.ID)))))
+	}
+	if len(lists) < maxLists {
+		rows = append(rows, btn.Row(btn.Data("➕ New list", "", "list|new|-")))
+	}
+	btn.Inline(rows...)
+
+	return btn, nil
+}
+
+func (h *ListHandler) handleOverview(ctx telebot.Context) error {
+	const op = "list.handleOverview"
+	h.app.Logger.Info(op, ctx, "Showing lists")
+
+	btn, err := h.overviewKeyboard(ctx.Sender().ID)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to load lists", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	if err = ctx.Edit(messages.ListsOverview, btn, telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to edit message with lists", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	return ctx.Respond()
+}
+
+// handlePage shows a page of a list, data looks like <list_key>-<page> and offset moves from that page
+func (h *ListHandler) handlePage(ctx telebot.Context, data string, offset int) error {
+	const op = "list.handlePage"
+	h.app.Logger.Info(op, ctx, "Showing list page", "data", data, "offset", offset)
+
+	key, pagePart, found := strings.Cut(data, "-")
+	if !found || !validListKey(key) {
+		h.app.Logger.Warning(op, ctx, "Malformed list data", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidList})
+	}
+
+	currentPage, err := strconv.Atoi(pagePart)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Invalid page number", "page", pagePart, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidPageNumber})
+	}
+
+	response, btn, err := h.listPage(ctx.Sender().ID, key, currentPage, offset)
+	if err != nil {
+		return h.respondListError(op, ctx, key, err)
+	}
+
+	if err = ctx.Edit(response, btn, telebot.ModeMarkdown); err != nil {
+		if strings.Contains(err.Error(), "message is not modified") {
+			h.app.Logger.Debug(op, ctx, "No changes detected in message")
+			return ctx.Respond(&telebot.CallbackResponse{Text: messages.NoChanges})
+		}
+		h.app.Logger.Error(op, ctx, "Failed to edit message with list", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	return ctx.Respond()
+}
+
+// listPage renders a page of a list of the user by its key, offset moves from the given page within the page range.
+// The watchlist is paged like any other list, its sorting and priorities stay in the full watchlist view.
+func (h *ListHandler) listPage(userId int64, key string, currentPage, offset int) (string, *telebot.ReplyMarkup, error) {
+	ctxDb, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var name string
+	var items []database.GetListItemsRow
+	if key == defaultListKey {
+		entries, err := h.app.Repository.Watchlists.GetWatchlistEntries(ctxDb, userId, constants.AllType, constants.AllType, constants.WatchlistSortManual)
+		if err != nil {
+			return "", nil, fmt.Errorf("error fetching watchlist: %w", err)
+		}
+
+		name = watchlistName
+		for _, entry := range entries {
+			items = append(items, database.GetListItemsRow{
+				ID:        entry.ID,
+				ShowApiID: entry.ShowApiID,
+				Type:      entry.Type,
+				Title:     entry.Title,
+				Image:     entry.Image,
+				CreatedAt: entry.CreatedAt,
+			})
+		}
+	} else {
+		listId, err := parseListKey(key)
+		if err != nil {
+			return "", nil, err
+		}
+
+		list, err := h.app.Repository.Lists.GetUserList(ctxDb, listId, userId)
+		if err != nil {
+			return "", nil, err
+		}
+
+		items, err = h.app.Repository.Lists.GetListItems(ctxDb, listId)
+		if err != nil {
+			return "", nil, fmt.Errorf("error fetching list items: %w", err)
+		}
+		name = "📂 " + utils.EscapeMarkdown(list.Name)
+	}
+
+	totalItems := len(items)
+	totalPages := (totalItems + itemsPerPage - 1) / itemsPerPage
+	if page := currentPage + offset; page >= 1 && page <= totalPages {
+		currentPage = page
+	}
+
+	paginatedItems := paginators.PaginateListItems(items, currentPage)
+	response, btn := paginators.GenerateListItemsResponse(&paginatedItems, currentPage, totalPages, totalItems, key, name, key == defaultListKey)
+	return response, btn, nil
+}
+
+// handleInfo opens the detail card of a list item, data looks like <list_key>-<type>-<api_id>
+func (h *ListHandler) handleInfo(ctx telebot.Context, data string) error {
+	const op = "list.handleInfo"
+	h.app.Logger.Info(op, ctx, "Fetching list item details", "data", data)
+
+	key, refPart, _ := strings.Cut(data, "-")
+	ref, err := parseTitleRef(refPart)
+	if err != nil || !validListKey(key) {
+		h.app.Logger.Warning(op, ctx, "Malformed list item data", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	backData := fmt.Sprintf("list|back|%s", key)
+	if ref.showType == constants.MovieType {
+		tmdbCtx, cancel := h.app.TMDBContext()
+		movieData, err := movie.GetMovie(tmdbCtx, h.app, int(ref.apiId), ctx.Sender().ID)
+		cancel()
+		if err != nil {
+			h.app.Logger.Error(op, ctx, "Failed to get movie data", "movie_id", ref.apiId, "error", err.Error())
+			return ctx.Send(messages.InternalError)
+		}
+
+		if err = movie.ShowMovie(h.app, ctx, movieData, backData); err != nil {
+			h.app.Logger.Error(op, ctx, "Failed to show movie details", "movie_id", ref.apiId, "error", err.Error())
+			return ctx.Send(messages.InternalError)
+		}
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MovieSelected})
+	}
+
+	tmdbCtx, cancel := h.app.TMDBContext()
+	tvData, err := tv.GetTV(tmdbCtx, h.app, int(ref.apiId), ctx.Sender().ID)
+	cancel()
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to get TV show data", "tv_id", ref.apiId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	if err = tv.ShowTV(h.app, ctx, tvData, backData); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to show TV show details", "tv_id", ref.apiId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	return ctx.Respond(&telebot.CallbackResponse{Text: messages.TVShowSelected})
+}
+
+// handleBack replaces a detail card with the first page of the list it was opened from
+func (h *ListHandler) handleBack(ctx telebot.Context, key string) error {
+	const op = "list.handleBack"
+	h.app.Logger.Info(op, ctx, "Returning to list", "list_key", key)
+
+	if !validListKey(key) {
+		h.app.Logger.Warning(op, ctx, "Malformed list key", "list_key", key)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidList})
+	}
+
+	response, btn, err := h.listPage(ctx.Sender().ID, key, 1, 0)
+	if err != nil {
+		return h.respondListError(op, ctx, key, err)
+	}
+
+	if err = ctx.Delete(); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to delete message", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	if _, err = ctx.Bot().Send(ctx.Chat(), response, btn, telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send list", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	return ctx.Respond()
+}
+
+func (h *ListHandler) handleNew(ctx telebot.Context) error {
+	const op = "list.handleNew"
+	h.app.Logger.Info(op, ctx, "Starting list creation")
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	lists, err := h.app.Repository.Lists.GetUserLists(ctxDb, ctx.Sender().ID)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to fetch lists", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	if len(lists) >= maxLists {
+		h.app.Logger.Info(op, ctx, "List limit reached", "lists", len(lists))
+		return ctx.Respond(&telebot.CallbackResponse{Text: fmt.Sprintf(messages.ListLimitReached, maxLists)})
+	}
+
+	h.app.Cache.UserCache.SetListNameStart(ctx.Sender().ID, uuid.Nil)
+	if err = ctx.Send(fmt.Sprintf(messages.ListNamePrompt, maxListNameLength)); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send list name prompt", "error", err.Error())
+		return err
+	}
+
+	return ctx.Respond()
+}
+
+func (h *ListHandler) handleRename(ctx telebot.Context, key string) error {
+	const op = "list.handleRename"
+	h.app.Logger.Info(op, ctx, "Starting list rename", "list_key", key)
+
+	listId, err := parseListKey(key)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed list key", "list_key", key)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidList})
+	}
+
+	list, err := h.userList(ctx.Sender().ID, listId)
+	if err != nil {
+		return h.respondListError(op, ctx, listKey(listId), err)
+	}
+
+	h.app.Cache.UserCache.SetListNameStart(ctx.Sender().ID, list.ID)
+	if err = ctx.Send(fmt.Sprintf(messages.ListRenamePrompt, utils.EscapeMarkdown(list.Name), maxListNameLength), telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send list name prompt", "error", err.Error())
+		return err
+	}
+
+	return ctx.Respond()
+}
+
+// handleDelete asks before a list goes away, the list page stays one tap away
+func (h *ListHandler) handleDelete(ctx telebot.Context, key string) error {
+	const op = "list.handleDelete"
+	h.app.Logger.Info(op, ctx, "Confirming list deletion", "list_key", key)
+
+	listId, err := parseListKey(key)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed list key", "list_key", key)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidList})
+	}
+
+	list, err := h.userList(ctx.Sender().ID, listId)
+	if err != nil {
+		return h.respondListError(op, ctx, listKey(listId), err)
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	items, err := h.app.Repository.Lists.GetListItems(ctxDb, list.ID)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to fetch list items", "list_id", list.ID, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	btn := &telebot.ReplyMarkup{}
+	btn.Inline(btn.Row(
+		btn.Data("🗑 Delete", "", fmt.Sprintf("list|confirm_delete|%s", key)),
+		btn.Data("Cancel", "", fmt.Sprintf("list|open|%s-1", key)),
+	))
+
+	if err = ctx.Edit(fmt.Sprintf(messages.ListDeleteConfirm, utils.EscapeMarkdown(list.Name), len(items)), btn, telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to edit message with confirmation", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	return ctx.Respond()
+}
+
+func (h *ListHandler) handleConfirmDelete(ctx telebot.Context, key string) error {
+	const op = "list.handleConfirmDelete"
+	h.app.Logger.Info(op, ctx, "Deleting list", "list_key", key)
+
+	listId, err := parseListKey(key)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed list key", "list_key", key)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidList})
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	deleted, err := h.app.Repository.Lists.DeleteUserList(ctxDb, listId, ctx.Sender().ID)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to delete list", "list_id", listId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	if !deleted {
+		h.app.Logger.Info(op, ctx, "List not found", "list_id", listId)
+	}
+
+	btn, err := h.overviewKeyboard(ctx.Sender().ID)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to load lists", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	if err = ctx.Edit(messages.ListsOverview, btn, telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to edit message with lists", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "List deleted", "list_id", listId)
+	return ctx.Respond(&telebot.CallbackResponse{Text: messages.ListDeleted})
+}
+
+// HandleListNameInput creates a list or renames one with the text the user sent, see cache.ListState
+func (h *ListHandler) HandleListNameInput(ctx telebot.Context) error {
+	const op = "list.HandleListNameInput"
+	userId := ctx.Sender().ID
+	h.app.Logger.Info(op, ctx, "Processing list name input")
+
+	_, userCache := h.app.Cache.UserCache.Fetch(userId)
+	state := userCache.ListState
+
+	name := strings.Join(strings.Fields(ctx.Message().Text), " ")
+	if name == "" {
+		h.app.Logger.Warning(op, ctx, "Empty list name received")
+		return ctx.Send(messages.ListNameEmpty)
+	}
+	if utf8.RuneCountInString(name) > maxListNameLength {
+		h.app.Logger.Warning(op, ctx, "List name too long", "length", utf8.RuneCountInString(name))
+		return ctx.Send(fmt.Sprintf(messages.ListNameTooLong, maxListNameLength))
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	lists, err := h.app.Repository.Lists.GetUserLists(ctxDb, userId)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to fetch lists", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	for _, list := range lists {
+		if list.ID != state.ListId && strings.EqualFold(list.Name, name) {
+			h.app.Logger.Info(op, ctx, "List name already taken", "name", name)
+			return ctx.Send(fmt.Sprintf(messages.ListNameTaken, name))
+		}
+	}
+
+	h.app.Cache.UserCache.SetListNameDone(userId)
+
+	if state.ListId != uuid.Nil {
+		renamed, err := h.app.Repository.Lists.RenameUserList(ctxDb, state.ListId, userId, name)
+		if err != nil {
+			h.app.Logger.Error(op, ctx, "Failed to rename list", "list_id", state.ListId, "error", err.Error())
+			return ctx.Send(messages.InternalError)
+		}
+		if !renamed {
+			h.app.Logger.Info(op, ctx, "Renamed list not found", "list_id", state.ListId)
+			return ctx.Send(messages.ListNotFound)
+		}
+
+		h.app.Logger.Info(op, ctx, "List renamed", "list_id", state.ListId, "name", name)
+		return ctx.Send(fmt.Sprintf(messages.ListRenamed, utils.EscapeMarkdown(name)), openListKeyboard(state.ListId), telebot.ModeMarkdown)
+	}
+
+	if len(lists) >= maxLists {
+		h.app.Logger.Info(op, ctx, "List limit reached", "lists", len(lists))
+		return ctx.Send(fmt.Sprintf(messages.ListLimitReached, maxLists))
+	}
+
+	listId, err := h.app.Repository.Lists.CreateUserList(ctxDb, userId, name)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to create list", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "List created", "list_id", listId, "name", name)
+	return ctx.Send(fmt.Sprintf(messages.ListCreated, utils.EscapeMarkdown(name)), openListKeyboard(listId), telebot.ModeMarkdown)
+}
+
+func openListKeyboard(listId uuid.UUID) *telebot.ReplyMarkup {
+	btn := &telebot.ReplyMarkup{}
+	btn.Inline(btn.Row(btn.Data("📂 Open list", "", fmt.Sprintf("list|open|%s-1", listKey(listId)))))
+	return btn
+}
+
+// userList returns a list of the user by its callback key, pgx.ErrNoRows when it is gone or someone else's
+func (h *ListHandler) userList(userId int64, listId uuid.UUID) (database.GetUserListRow, error) {
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	return h.app.Repository.Lists.GetUserList(ctxDb, listId, userId)
+}
+
+// respondListError answers a callback whose list couldn't be loaded, a deleted list isn't worth an error message
+func (h *ListHandler) respondListError(op string, ctx telebot.Context, key string, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.app.Logger.Info(op, ctx, "List not found", "list_key", key)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.ListNotFound})
+	}
+
+	h.app.Logger.Error(op, ctx, "Failed to load list", "list_key", key, "error", err.Error())
+	return ctx.Send(messages.InternalError)
+}
+
+func (h *ListHandler) ListCallback(ctx telebot.Context) error {
+	const op = "list.ListCallback"
+	callback := ctx.Callback()
+	trimmed := strings.TrimSpace(callback.Data)
+	h.app.Logger.Info(op, ctx, "Processing list callback", "callback_data", trimmed)
+
+	if !strings.HasPrefix(trimmed, "list|") {
+		h.app.Logger.Warning(op, ctx, "Invalid callback prefix", "callback_data", trimmed)
+		return ctx.Send(messages.InternalError)
+	}
+
+	dataParts := strings.Split(trimmed, "|")
+	if len(dataParts) < 3 {
+		h.app.Logger.Warning(op, ctx, "Malformed callback data", "callback_data", callback.Data,
+			"parts_count", len(dataParts))
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	action := dataParts[1]
+	data := dataParts[2]
+	h.app.Logger.Debug(op, ctx, "Processing callback action", "action", action, "data", data)
+
+	switch action {
+	case "overview":
+		return h.handleOverview(ctx)
+
+	case "open":
+		return h.handlePage(ctx, data, 0)
+
+	case "next":
+		return h.handlePage(ctx, data, 1)
+
+	case "prev":
+		return h.handlePage(ctx, data, -1)
+
+	case "info":
+		return h.handleInfo(ctx, data)
+
+	case "back":
+		return h.handleBack(ctx, data)
+
+	case "new":
+		return h.handleNew(ctx)
+
+	case "rename":
+		return h.handleRename(ctx, data)
+
+	case "delete":
+		return h.handleDelete(ctx, data)
+
+	case "confirm_delete":
+		return h.handleConfirmDelete(ctx, data)
+
+	case "picker":
+		return h.handlePicker(ctx, data)
+
+	case "toggle":
+		return h.handleToggle(ctx, data)
+
+	case "done":
+		return h.handleDone(ctx)
+
+	default:
+		h.app.Logger.Warning(op, ctx, "Unknown callback action", "action", action)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.UnknownAction})
+	}
+}
+
+// listKey writes a list ID without dashes, they separate the fields of callback data
+func listKey(id uuid.UUID) string {
+	return hex.EncodeToString(id[:])
+}
+
+// validListKey reports whether callback data names the watchlist or a custom list
+func validListKey(key string) bool {
+	if key == defaultListKey {
+		return true
+	}
+	_, err := parseListKey(key)
+	return err == nil
+}
+
+func parseListKey(key string) (uuid.UUID, error) {
+	if len(key) != 32 {
+		return uuid.Nil, fmt.Errorf("expected 32 characters, got %d", len(key))
+	}
+	return uuid.Parse(key)
+}
+
+// parseTitleRef splits data like <type>-<api_id>
+func parseTitleRef(data string) (titleRef, error) {
+	showType, idPart, found := strings.Cut(data, "-")
+	if !found || (showType != constants.MovieType && showType != constants.TVShowType) {
+		return titleRef{}, fmt.Errorf("unknown title reference %q", data)
+	}
+
+	apiId, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil {
+		return titleRef{}, err
+	}
+
+	return titleRef{showType: showType, apiId: apiId}, nil
+}
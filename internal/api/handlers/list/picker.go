@@ -0,0 +1,235 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/movie"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/tv"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"gopkg.in/telebot.v3"
+	"strings"
+	"time"
+)
+
+// handlePicker sends the lists a title can be put on, data looks like <type>-<api_id>
+func (h *ListHandler) handlePicker(ctx telebot.Context, data string) error {
+	const op = "list.handlePicker"
+	h.app.Logger.Info(op, ctx, "Showing list picker", "data", data)
+
+	ref, err := parseTitleRef(data)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed title data", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	title, _, _, err := h.titleDetails(ctx.Sender().ID, ref)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error fetching title from TMDB", "api_id", ref.apiId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	btn, err := h.pickerKeyboard(ctx.Sender().ID, ref)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to load lists", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	if _, err = ctx.Bot().Send(ctx.Chat(), fmt.Sprintf(messages.ListPickerPrompt, title), btn, telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send list picker", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	return ctx.Respond()
+}
+
+// pickerKeyboard marks the lists the title is on already, the watchlist comes first as the default list
+func (h *ListHandler) pickerKeyboard(userId int64, ref titleRef) (*telebot.ReplyMarkup, error) {
+	ctxDb, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	watchlisted, err := h.app.Repository.Watchlists.WatchlistExists(ctxDb, ref.apiId, userId, ref.showType)
+	if err != nil {
+		return nil, fmt.Errorf("error checking watchlist: %w", err)
+	}
+
+	lists, err := h.app.Repository.Lists.GetListsWithTitle(ctxDb, userId, ref.apiId, ref.showType)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching lists: %w", err)
+	}
+
+	btn := &telebot.ReplyMarkup{}
+	toggle := func(name string, checked bool, key string) telebot.Row {
+		if checked {
+			name = "✅ " + name
+		}
+		return btn.Row(btn.Data(name, "", fmt.Sprintf("list|toggle|%s-%s-%d", key, ref.showType, ref.apiId)))
+	}
+
+	rows := []telebot.Row{toggle(watchlistName, watchlisted, defaultListKey)}
+	for _, list := range lists {
+		rows = append(rows, toggle("📂 "+list.Name, list.HasTitle, listKey(list.ID)))
+	}
+
+	actions := btn.Row(btn.Data("✅ Done", "", "list|done|-"))
+	if len(lists) < maxLists {
+		actions = append(actions, btn.Data("➕ New list", "", "list|new|-"))
+	}
+	btn.Inline(append(rows, actions)...)
+
+	return btn, nil
+}
+
+// handleToggle puts a title on a list or takes it off, data looks like <list_key>-<type>-<api_id>
+func (h *ListHandler) handleToggle(ctx telebot.Context, data string) error {
+	const op = "list.handleToggle"
+	h.app.Logger.Info(op, ctx, "Toggling list item", "data", data)
+
+	key, refPart, _ := strings.Cut(data, "-")
+	ref, err := parseTitleRef(refPart)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed title data", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	var response string
+	if key == defaultListKey {
+		response, err = h.toggleWatchlist(ctx.Sender().ID, ref)
+	} else {
+		listId, keyErr := parseListKey(key)
+		if keyErr != nil {
+			h.app.Logger.Warning(op, ctx, "Malformed list key", "list_key", key)
+			return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidList})
+		}
+
+		list, listErr := h.userList(ctx.Sender().ID, listId)
+		if listErr != nil {
+			return h.respondListError(op, ctx, key, listErr)
+		}
+		response, err = h.toggleListItem(ctx.Sender().ID, list, ref)
+	}
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to toggle list item", "list_key", key, "api_id", ref.apiId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	btn, err := h.pickerKeyboard(ctx.Sender().ID, ref)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to load lists", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	if _, err = ctx.Bot().EditReplyMarkup(ctx.Message(), btn); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to update list picker", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "List item toggled", "list_key", key, "type", ref.showType, "api_id", ref.apiId)
+	return ctx.Respond(&telebot.CallbackResponse{Text: response})
+}
+
+// toggleWatchlist treats the watchlist like any other list, its entries keep feeding the release checkers
+func (h *ListHandler) toggleWatchlist(userId int64, ref titleRef) (string, error) {
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	watchlisted, err := h.app.Repository.Watchlists.WatchlistExists(ctxDb, ref.apiId, userId, ref.showType)
+	if err != nil {
+		return "", fmt.Errorf("error checking watchlist: %w", err)
+	}
+
+	if watchlisted {
+		if err = h.app.Repository.Watchlists.DeleteWatchlist(ctxDb, ref.apiId, userId, ref.showType); err != nil {
+			return "", fmt.Errorf("error removing from watchlist: %w", err)
+		}
+		return fmt.Sprintf(messages.RemovedFromList, "Watchlist"), nil
+	}
+
+	// The TMDB request must not eat into the time of the insert
+	title, image, runtime, err := h.titleDetails(userId, ref)
+	if err != nil {
+		return "", fmt.Errorf("error fetching title from TMDB: %w", err)
+	}
+
+	ctxDb, cancel = context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	err = h.app.Repository.Watchlists.CreateWatchlist(ctxDb, database.CreateWatchlistParams{
+		UserID:    userId,
+		ShowApiID: ref.apiId,
+		Type:      ref.showType,
+		Title:     title,
+		Image:     &image,
+		Runtime:   runtime,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error adding to watchlist: %w", err)
+	}
+	return fmt.Sprintf(messages.AddedToList, "Watchlist"), nil
+}
+
+func (h *ListHandler) toggleListItem(userId int64, list database.GetUserListRow, ref titleRef) (string, error) {
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	removed, err := h.app.Repository.Lists.RemoveListItem(ctxDb, list.ID, ref.apiId, ref.showType)
+	if err != nil {
+		return "", fmt.Errorf("error removing list item: %w", err)
+	}
+	if removed {
+		return fmt.Sprintf(messages.RemovedFromList, list.Name), nil
+	}
+
+	title, image, _, err := h.titleDetails(userId, ref)
+	if err != nil {
+		return "", fmt.Errorf("error fetching title from TMDB: %w", err)
+	}
+
+	ctxDb, cancel = context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	err = h.app.Repository.Lists.AddListItem(ctxDb, database.AddListItemParams{
+		ListID:    list.ID,
+		ShowApiID: ref.apiId,
+		Type:      ref.showType,
+		Title:     title,
+		Image:     &image,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error adding list item: %w", err)
+	}
+	return fmt.Sprintf(messages.AddedToList, list.Name), nil
+}
+
+// handleDone closes the picker, the detail card above it stays
+func (h *ListHandler) handleDone(ctx telebot.Context) error {
+	const op = "list.handleDone"
+	if err := ctx.Delete(); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to delete list picker", "error", err.Error())
+	}
+	return ctx.Respond()
+}
+
+// titleDetails returns the title, poster path and runtime of a movie or show, list items store them like watchlist
+// entries
+func (h *ListHandler) titleDetails(userId int64, ref titleRef) (string, string, *int32, error) {
+	if ref.showType == constants.MovieType {
+		tmdbCtx, cancel := h.app.TMDBContext()
+		movieData, err := movie.GetMovie(tmdbCtx, h.app, int(ref.apiId), userId)
+		cancel()
+		if err != nil {
+			return "", "", nil, err
+		}
+		return movieData.Title, movieData.PosterPath, utils.WatchlistRuntime(movieData.Runtime), nil
+	}
+
+	tmdbCtx, cancel := h.app.TMDBContext()
+	tvData, err := tv.GetTV(tmdbCtx, h.app, int(ref.apiId), userId)
+	cancel()
+	if err != nil {
+		return "", "", nil, err
+	}
+	return tvData.Name, tvData.PosterPath, tv.EpisodeRuntime(tvData), nil
+}
@@ -0,0 +1,39 @@
+package list
+
+import (
+	"github.com/erkinov-wtf/movie-manager-bot/internal/api/interfaces"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+)
+
+type ListHandler struct {
+	app *app.App
+}
+
+func NewListHandler(app *app.App) interfaces.ListInterface {
+	return &ListHandler{
+		app: app,
+	}
+}
+
+// titleRef is a movie or a show as it appears in callback data, <type>-<api_id>
+type titleRef struct {
+	showType string
+	apiId    int64
+}
+
+// defaultListKey stands for the watchlist in callback data, every user has it as their default list. The watchlist
+// keeps its own table instead of being a user_lists row: the release and availability checkers, priorities, notes
+// and the manual order all work on watchlist entries. The list views page it like any other list, it just can't
+// be renamed or deleted.
+const defaultListKey = "default"
+
+// watchlistName is how the default list is labelled wherever lists are shown
+const watchlistName = "⭐ Watchlist"
+
+const (
+	itemsPerPage = 3
+
+	// maxLists keeps the list picker below a screen of buttons
+	maxLists          = 20
+	maxListNameLength = 40
+)
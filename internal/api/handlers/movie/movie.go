@@ -10,6 +10,8 @@ import (
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/paginators"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"github.com/jackc/pgx/v5/pgtype"
 	"gopkg.in/telebot.v3"
 	"strconv"
 	"strings"
@@ -47,7 +49,9 @@ func (h *MovieHandler) SearchMovie(ctx telebot.Context) error {
 	}
 
 	// Fetch search results
-	movieData, err := search.SearchMovie(h.app, searchQuery, userId)
+	tmdbCtx, cancel := h.app.TMDBContext()
+	movieData, err := search.SearchMovie(tmdbCtx, h.app, searchQuery, userId)
+	cancel()
 	if err != nil || movieData.TotalResults == 0 {
 		h.app.Logger.Info(op, ctx, "No movies found for query", "query", searchQuery)
 		_, err = ctx.Bot().Edit(msg, fmt.Sprintf("No movies found for *%s*", searchQuery), telebot.ModeMarkdown)
@@ -97,13 +101,15 @@ func (h *MovieHandler) handleMovieDetails(ctx telebot.Context, data string) erro
 		return err
 	}
 
-	movieData, err := movie.GetMovie(h.app, parsedId, ctx.Sender().ID)
+	tmdbCtx, cancel := h.app.TMDBContext()
+	movieData, err := movie.GetMovie(tmdbCtx, h.app, parsedId, ctx.Sender().ID)
+	cancel()
 	if err != nil {
 		h.app.Logger.Error(op, ctx, "Failed to get movie data from TMDB", "movie_id", parsedId, "error", err.Error())
 		return err
 	}
 
-	err = movie.ShowMovie(h.app, ctx, movieData, true)
+	err = movie.ShowMovie(h.app, ctx, movieData, "movie|back_to_pagination|")
 	if err != nil {
 		h.app.Logger.Error(op, ctx, "Failed to show movie details", "movie_id", parsedId, "error", err.Error())
 		return err
@@ -113,86 +119,173 @@ func (h *MovieHandler) handleMovieDetails(ctx telebot.Context, data string) erro
 	return ctx.Respond(&telebot.CallbackResponse{Text: messages.MovieSelected})
 }
 
+// handleWatchedDetails asks when the movie was watched, a movie can be marked watched again to record a rewatch
 func (h *MovieHandler) handleWatchedDetails(ctx telebot.Context, movieIdStr string) error {
 	const op = "movie.handleWatchedDetails"
-	h.app.Logger.Info(op, ctx, "Marking movie as watched", "movie_id", movieIdStr)
+	h.app.Logger.Info(op, ctx, "Asking for watch date of movie", "movie_id", movieIdStr)
 
-	ctxDb, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	movieId, err := strconv.ParseInt(movieIdStr, 10, 64)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to parse movie ID", "movie_id", movieIdStr, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
-	h.app.Logger.Debug(op, ctx, "Starting database transaction")
-	tx, err := h.app.Repository.BeginTx(ctxDb)
+	h.app.Logger.Debug(op, ctx, "Fetching previous watches of movie", "movie_id", movieId)
+	events, err := h.app.Repository.WatchEvents.GetWatchEvents(ctxDb, ctx.Sender().ID, movieId, constants.MovieType)
 	if err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to begin transaction", "error", err.Error())
+		h.app.Logger.Error(op, ctx, "Failed to fetch previous watches", "movie_id", movieId, "error", err.Error())
 		return ctx.Send(messages.InternalError)
 	}
-	defer tx.Rollback(ctxDb)
 
-	movieId, err := strconv.Atoi(movieIdStr)
-	if err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to parse movie ID", "movie_id", movieIdStr, "error", err.Error())
+	text := messages.WatchDatePrompt
+	if len(events) > 0 {
+		text = fmt.Sprintf("🔁 You watched it %d time(s), last on *%s*\n\n%s",
+			len(events), events[0].WatchedOn.Time.Format(constants.DateFormat), text)
+	}
+
+	today := h.userToday(ctx.Sender().ID)
+	if _, err = ctx.Bot().Send(ctx.Chat(), text, watchDateKeyboard(movieId, today), telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send watch date prompt", "error", err.Error())
 		return ctx.Send(messages.InternalError)
 	}
 
-	h.app.Logger.Debug(op, ctx, "Checking if movie exists in watched list", "movie_id", movieId)
-	movieExists, err := h.app.Repository.Movies.MovieExists(ctxDb, int64(movieId), ctx.Sender().ID)
+	h.app.Logger.Info(op, ctx, "Watch date prompt displayed", "movie_id", movieId, "previous_watches", len(events))
+	return nil
+}
+
+// handleWatchedOn records a watch of a movie on the picked day. The first watch also adds the movie to the watched list.
+func (h *MovieHandler) handleWatchedOn(ctx telebot.Context, data string) error {
+	const op = "movie.handleWatchedOn"
+	h.app.Logger.Info(op, ctx, "Marking movie as watched", "data", data)
+
+	movieId, watchedOn, err := parseWatchDate(data, callbackDateLayout)
 	if err != nil {
-		h.app.Logger.Error(op, ctx, "Database error when checking movie existence", "error", err.Error())
-		return ctx.Send(messages.InternalError)
+		h.app.Logger.Warning(op, ctx, "Malformed watch date", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidWatchDate})
 	}
 
-	if movieExists {
-		// Movie already exists in watched list (movies table)
-		h.app.Logger.Info(op, ctx, "Movie already marked as watched", "movie_id", movieId)
-		return ctx.Send(messages.WatchedMovie)
+	userId := ctx.Sender().ID
+	if watchedOn.After(h.userToday(userId)) {
+		h.app.Logger.Warning(op, ctx, "Watch date is in the future", "watched_on", watchedOn)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.FutureWatchDate})
 	}
 
 	h.app.Logger.Debug(op, ctx, "Retrieving movie data from TMDB", "movie_id", movieId)
-	movieData, err := movie.GetMovie(h.app, movieId, ctx.Sender().ID)
+	tmdbCtx, cancel := h.app.TMDBContext()
+	movieData, err := movie.GetMovie(tmdbCtx, h.app, int(movieId), userId)
+	cancel()
 	if err != nil {
 		h.app.Logger.Error(op, ctx, "Failed to retrieve movie from API", "movie_id", movieId, "error", err.Error())
 		return ctx.Send(messages.InternalError)
 	}
 
-	newMovie := database.CreateMovieParams{
-		UserID:  ctx.Sender().ID,
-		ApiID:   movieData.ID,
-		Title:   movieData.Title,
-		Runtime: movieData.Runtime,
+	ctxDb, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	h.app.Logger.Debug(op, ctx, "Starting database transaction")
+	tx, err := h.app.Repository.BeginTx(ctxDb)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to begin transaction", "error", err.Error())
+		return ctx.Send(messages.InternalError)
 	}
+	defer tx.Rollback(ctxDb)
 
-	h.app.Logger.Debug(op, ctx, "Adding movie to watched list", "movie_title", movieData.Title)
-	err = h.app.Repository.Movies.CreateMovie(ctxDb, newMovie)
+	h.app.Logger.Debug(op, ctx, "Checking if movie exists in watched list", "movie_id", movieId)
+	movieExists, err := tx.Repos.Movies.MovieExists(ctxDb, movieId, userId)
 	if err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to create new movie record", "error", err.Error())
+		h.app.Logger.Error(op, ctx, "Database error when checking movie existence", "error", err.Error())
 		return ctx.Send(messages.InternalError)
 	}
 
-	h.app.Logger.Debug(op, ctx, "Removing movie from watchlist", "movie_id", movieId)
-	err = h.app.Repository.Watchlists.DeleteWatchlist(ctxDb, int64(movieId), ctx.Sender().ID)
+	var previousWatches int
+	if movieExists {
+		events, err := tx.Repos.WatchEvents.GetWatchEvents(ctxDb, userId, movieId, constants.MovieType)
+		if err != nil {
+			h.app.Logger.Error(op, ctx, "Failed to fetch previous watches", "error", err.Error())
+			return ctx.Send(messages.InternalError)
+		}
+		previousWatches = len(events)
+	} else {
+		h.app.Logger.Debug(op, ctx, "Adding movie to watched list", "movie_title", movieData.Title)
+		err = tx.Repos.Movies.CreateMovie(ctxDb, database.CreateMovieParams{
+			UserID:  userId,
+			ApiID:   movieData.ID,
+			Title:   movieData.Title,
+			Runtime: movieData.Runtime,
+		})
+		if err != nil {
+			h.app.Logger.Error(op, ctx, "Failed to create new movie record", "error", err.Error())
+			return ctx.Send(messages.InternalError)
+		}
+
+		h.app.Logger.Debug(op, ctx, "Removing movie from watchlist", "movie_id", movieId)
+		if err = tx.Repos.Watchlists.DeleteWatchlist(ctxDb, movieId, userId, constants.MovieType); err != nil {
+			h.app.Logger.Error(op, ctx, "Failed to delete movie from watchlist", "error", err.Error())
+			return ctx.Send(messages.InternalError)
+		}
+	}
+
+	err = tx.Repos.WatchEvents.CreateWatchEvent(ctxDb, database.CreateWatchEventParams{
+		UserID:    userId,
+		ApiID:     movieData.ID,
+		Type:      constants.MovieType,
+		Runtime:   movieData.Runtime,
+		WatchedOn: pgtype.Date{Time: watchedOn, Valid: true},
+	})
 	if err != nil {
-		h.app.Logger.Warning(op, ctx, "Failed to delete movie from watchlist, may not exist", "error", err.Error())
-		// Continue execution as this is not critical
+		h.app.Logger.Error(op, ctx, "Failed to record watch", "error", err.Error())
+		return ctx.Send(messages.InternalError)
 	}
 
 	h.app.Logger.Debug(op, ctx, "Committing transaction")
-	if err = tx.Commit(context.Background()); err != nil {
+	if err = tx.Commit(ctxDb); err != nil {
 		h.app.Logger.Error(op, ctx, "Failed to commit transaction", "error", err.Error())
 		return ctx.Send(messages.InternalError)
 	}
 
-	_, err = ctx.Bot().Send(ctx.Chat(),
-		fmt.Sprintf("The Movie has been marked as watched:\nDuration: *%d minutes*", movieData.Runtime),
-		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
-	)
-	if err != nil {
+	text := fmt.Sprintf("The Movie has been marked as watched:\nDuration: *%d minutes*\nWatched on: *%s*",
+		movieData.Runtime, watchedOn.Format(constants.DateFormat))
+	if previousWatches > 0 {
+		text += fmt.Sprintf("\n🔁 Rewatch #%d", previousWatches)
+	}
+
+	if err = ctx.Edit(text, telebot.ModeMarkdown); err != nil {
 		h.app.Logger.Error(op, ctx, "Failed to send confirmation message", "error", err.Error())
 		return err
 	}
 
+	// The watch is saved already, a missing rating prompt is not worth an error
+	if err = h.ratingHandler.PromptRating(ctx, constants.MovieType, movieData.ID, 0, movieData.Title); err != nil {
+		h.app.Logger.Warning(op, ctx, "Failed to prompt for a rating", "movie_id", movieId, "error", err.Error())
+	}
+
 	h.app.Logger.Info(op, ctx, "Movie successfully marked as watched",
-		"movie_id", movieId, "title", movieData.Title, "runtime", movieData.Runtime)
-	return nil
+		"movie_id", movieId, "title", movieData.Title, "runtime", movieData.Runtime,
+		"watched_on", watchedOn.Format(constants.DateFormat), "rewatch", previousWatches > 0)
+	return ctx.Respond()
+}
+
+// handlePickDate shows the calendar of one month to pick a watch date from
+func (h *MovieHandler) handlePickDate(ctx telebot.Context, data string) error {
+	const op = "movie.handlePickDate"
+	h.app.Logger.Info(op, ctx, "Showing watch date calendar", "data", data)
+
+	movieId, month, err := parseWatchDate(data, callbackMonthLayout)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed calendar month", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidWatchDate})
+	}
+
+	today := h.userToday(ctx.Sender().ID)
+	if err = ctx.Edit(messages.SelectWatchDate, calendarKeyboard(movieId, month, today)); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to show calendar", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	return ctx.Respond()
 }
 
 func (h *MovieHandler) handleWatchlist(ctx telebot.Context, data string) error {
@@ -205,7 +298,9 @@ func (h *MovieHandler) handleWatchlist(ctx telebot.Context, data string) error {
 		return ctx.Send(messages.WatchedMovie)
 	}
 
-	movieData, err := movie.GetMovie(h.app, movieId, ctx.Sender().ID)
+	tmdbCtx, cancel := h.app.TMDBContext()
+	movieData, err := movie.GetMovie(tmdbCtx, h.app, movieId, ctx.Sender().ID)
+	cancel()
 	if err != nil {
 		h.app.Logger.Error(op, ctx, "Failed to retrieve movie data", "movie_id", movieId, "error", err.Error())
 		return ctx.Send(messages.WatchedMovie)
@@ -220,6 +315,7 @@ func (h *MovieHandler) handleWatchlist(ctx telebot.Context, data string) error {
 		Type:      constants.MovieType,
 		Title:     movieData.Title,
 		Image:     &movieData.PosterPath,
+		Runtime:   utils.WatchlistRuntime(movieData.Runtime),
 	}
 
 	h.app.Logger.Debug(op, ctx, "Adding movie to watchlist in database", "movie_title", movieData.Title)
@@ -358,9 +454,27 @@ func (h *MovieHandler) MovieCallback(ctx telebot.Context) error {
 	case "watched":
 		return h.handleWatchedDetails(ctx, data)
 
+	case "watched_on":
+		return h.handleWatchedOn(ctx, data)
+
+	case "pick_date":
+		return h.handlePickDate(ctx, data)
+
+	case "noop":
+		return ctx.Respond()
+
+	case "unwatch":
+		return h.handleUnwatch(ctx, data)
+
+	case "undo":
+		return h.handleUndo(ctx, data)
+
 	case "watchlist":
 		return h.handleWatchlist(ctx, data)
 
+	case "watchlisted":
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.AlreadyWatchlisted})
+
 	case "back_to_pagination":
 		return h.handleBackToPagination(ctx)
 
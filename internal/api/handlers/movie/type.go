@@ -6,11 +6,13 @@ import (
 )
 
 type MovieHandler struct {
-	app *app.App
+	app           *app.App
+	ratingHandler interfaces.RatingInterface
 }
 
-func NewMovieHandler(app *app.App) interfaces.MovieInterface {
+func NewMovieHandler(app *app.App, ratingHandler interfaces.RatingInterface) interfaces.MovieInterface {
 	return &MovieHandler{
-		app: app,
+		app:           app,
+		ratingHandler: ratingHandler,
 	}
 }
@@ -0,0 +1,181 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/cache"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
+	"github.com/jackc/pgx/v5"
+	"gopkg.in/telebot.v3"
+	"strconv"
+	"time"
+)
+
+// handleUnwatch removes a movie with its watches and ratings from the watched library, it can be undone for a while
+func (h *MovieHandler) handleUnwatch(ctx telebot.Context, data string) error {
+	const op = "movie.handleUnwatch"
+	h.app.Logger.Info(op, ctx, "Removing movie from watched library", "movie_id", data)
+
+	movieId, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to parse movie ID", "movie_id", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	userId := ctx.Sender().ID
+	ctxDb, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	h.app.Logger.Debug(op, ctx, "Starting database transaction")
+	tx, err := h.app.Repository.BeginTx(ctxDb)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to begin transaction", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	defer tx.Rollback(ctxDb)
+
+	watchedMovie, err := tx.Repos.Movies.GetUserMovie(ctxDb, movieId, userId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.app.Logger.Info(op, ctx, "Movie is not in watched library", "movie_id", movieId)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.NotInLibrary})
+	}
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to fetch watched movie", "movie_id", movieId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	snapshot := cache.UndoSnapshot{Title: watchedMovie.Title, Runtime: watchedMovie.Runtime}
+	if err = tx.Repos.Movies.SoftDeleteMovie(ctxDb, movieId, userId); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to delete movie", "movie_id", movieId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	if snapshot.WatchEvents, err = tx.Repos.WatchEvents.DeleteWatchEvents(ctxDb, userId, movieId, constants.MovieType); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to delete watches", "movie_id", movieId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	if snapshot.Ratings, err = tx.Repos.Ratings.DeleteTitleRatings(ctxDb, userId, movieId, constants.MovieType); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to delete ratings", "movie_id", movieId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Debug(op, ctx, "Committing transaction")
+	if err = tx.Commit(ctxDb); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to commit transaction", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Cache.UndoCache.Put(userId, constants.MovieType, movieId, snapshot)
+
+	_, err = ctx.Bot().Send(ctx.Chat(), fmt.Sprintf(messages.MovieUnwatched, snapshot.Title), undoKeyboard(movieId), telebot.ModeMarkdown)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send confirmation message", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "Movie removed from watched library",
+		"movie_id", movieId, "title", snapshot.Title, "watches", len(snapshot.WatchEvents), "ratings", len(snapshot.Ratings))
+	return ctx.Respond()
+}
+
+// handleUndo puts a removed movie back into the watched library with its watches and ratings
+func (h *MovieHandler) handleUndo(ctx telebot.Context, data string) error {
+	const op = "movie.handleUndo"
+	h.app.Logger.Info(op, ctx, "Restoring removed movie", "movie_id", data)
+
+	movieId, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to parse movie ID", "movie_id", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	userId := ctx.Sender().ID
+	snapshot, ok := h.app.Cache.UndoCache.Take(userId, constants.MovieType, movieId)
+	if !ok {
+		h.app.Logger.Info(op, ctx, "Nothing to undo", "movie_id", movieId)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.UndoExpired})
+	}
+
+	if err = h.restoreMovie(userId, movieId, snapshot); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to restore movie", "movie_id", movieId, "error", err.Error())
+		// The snapshot stays available so the user can try again
+		h.app.Cache.UndoCache.Put(userId, constants.MovieType, movieId, snapshot)
+		return ctx.Send(messages.InternalError)
+	}
+
+	if err = ctx.Edit(fmt.Sprintf(messages.TitleRestored, snapshot.Title), telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Warning(op, ctx, "Failed to update confirmation message", "error", err.Error())
+	}
+
+	h.app.Logger.Info(op, ctx, "Movie restored", "movie_id", movieId, "title", snapshot.Title)
+	return ctx.Respond()
+}
+
+func (h *MovieHandler) restoreMovie(userId, movieId int64, snapshot cache.UndoSnapshot) error {
+	ctxDb, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	tx, err := h.app.Repository.BeginTx(ctxDb)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctxDb)
+
+	// The movie may have been marked watched again in the meantime, its watches are merged then
+	exists, err := tx.Repos.Movies.MovieExists(ctxDb, movieId, userId)
+	if err != nil {
+		return fmt.Errorf("error checking watched movie: %w", err)
+	}
+	if !exists {
+		err = tx.Repos.Movies.CreateMovie(ctxDb, database.CreateMovieParams{
+			UserID:  userId,
+			ApiID:   movieId,
+			Title:   snapshot.Title,
+			Runtime: snapshot.Runtime,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating movie: %w", err)
+		}
+	}
+
+	for _, event := range snapshot.WatchEvents {
+		err = tx.Repos.WatchEvents.RestoreWatchEvent(ctxDb, database.RestoreWatchEventParams{
+			UserID:    userId,
+			ApiID:     movieId,
+			Type:      constants.MovieType,
+			Runtime:   event.Runtime,
+			WatchedOn: event.WatchedOn,
+			CreatedAt: event.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("error restoring watch: %w", err)
+		}
+	}
+
+	for _, rating := range snapshot.Ratings {
+		err = tx.Repos.Ratings.RestoreRating(ctxDb, database.RestoreRatingParams{
+			UserID:       userId,
+			ApiID:        movieId,
+			Type:         constants.MovieType,
+			SeasonNumber: rating.SeasonNumber,
+			Rating:       rating.Rating,
+			Review:       rating.Review,
+		})
+		if err != nil {
+			return fmt.Errorf("error restoring rating: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctxDb); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+func undoKeyboard(movieId int64) *telebot.ReplyMarkup {
+	btn := &telebot.ReplyMarkup{}
+	btn.Inline(btn.Row(btn.Data("↩️ Undo", "", fmt.Sprintf("movie|undo|%v", movieId))))
+	return btn
+}
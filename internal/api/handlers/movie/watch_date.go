@@ -0,0 +1,125 @@
+package movie
+
+import (
+	"context"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"gopkg.in/telebot.v3"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Callback data carries watch dates and calendar months without separators, "|" and "-" are taken already
+const (
+	callbackDateLayout  = "20060102"
+	callbackMonthLayout = "200601"
+)
+
+var weekdayLabels = []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"}
+
+// userToday returns the current date in the user's timezone, as midnight UTC like dates read from the database
+func (h *MovieHandler) userToday(userId int64) time.Time {
+	const op = "movie.userToday"
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	settings, err := h.app.Repository.Settings.GetUserSettings(ctxDb, userId)
+	if err != nil {
+		h.app.Logger.Warning(op, nil, "Failed to get user timezone, using UTC", "error", err.Error())
+	} else {
+		now = now.In(utils.LoadLocation(settings.Timezone))
+	}
+
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// parseWatchDate splits callback data like <movie_id>-<date> where the date follows the given layout
+func parseWatchDate(data, layout string) (int64, time.Time, error) {
+	parts := strings.Split(data, "-")
+	if len(parts) != 2 {
+		return 0, time.Time{}, fmt.Errorf("expected 2 parts, got %d", len(parts))
+	}
+
+	movieId, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	date, err := time.Parse(layout, parts[1])
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return movieId, date, nil
+}
+
+// watchDateKeyboard offers the usual watch days and a calendar for anything earlier
+func watchDateKeyboard(movieId int64, today time.Time) *telebot.ReplyMarkup {
+	btn := &telebot.ReplyMarkup{}
+	btn.Inline(
+		btn.Row(
+			btn.Data("📅 Today", "", fmt.Sprintf("movie|watched_on|%v-%v", movieId, today.Format(callbackDateLayout))),
+			btn.Data("🌙 Yesterday", "", fmt.Sprintf("movie|watched_on|%v-%v", movieId, today.AddDate(0, 0, -1).Format(callbackDateLayout))),
+		),
+		btn.Row(btn.Data("📆 Pick a date", "", fmt.Sprintf("movie|pick_date|%v-%v", movieId, today.Format(callbackMonthLayout)))),
+	)
+	return btn
+}
+
+// calendarKeyboard renders one month with the days up to today as buttons. Later days and later months can't be picked.
+func calendarKeyboard(movieId int64, month, today time.Time) *telebot.ReplyMarkup {
+	btn := &telebot.ReplyMarkup{}
+	noop := func(text string) telebot.Btn {
+		return btn.Data(text, "", "movie|noop|-")
+	}
+
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	currentMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if first.After(currentMonth) {
+		first = currentMonth
+	}
+
+	prev := btn.Data("◀️", "", fmt.Sprintf("movie|pick_date|%v-%v", movieId, first.AddDate(0, -1, 0).Format(callbackMonthLayout)))
+	next := noop(" ")
+	if first.Before(currentMonth) {
+		next = btn.Data("▶️", "", fmt.Sprintf("movie|pick_date|%v-%v", movieId, first.AddDate(0, 1, 0).Format(callbackMonthLayout)))
+	}
+
+	rows := []telebot.Row{btn.Row(prev, noop(first.Format("January 2006")), next)}
+
+	header := make(telebot.Row, 0, len(weekdayLabels))
+	for _, label := range weekdayLabels {
+		header = append(header, noop(label))
+	}
+	rows = append(rows, header)
+
+	// Weeks start on Monday
+	week := make(telebot.Row, 0, 7)
+	for i := 0; i < (int(first.Weekday())+6)%7; i++ {
+		week = append(week, noop(" "))
+	}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		if day.After(today) {
+			week = append(week, noop("·"))
+		} else {
+			week = append(week, btn.Data(strconv.Itoa(day.Day()), "",
+				fmt.Sprintf("movie|watched_on|%v-%v", movieId, day.Format(callbackDateLayout))))
+		}
+
+		if len(week) == 7 {
+			rows = append(rows, week)
+			week = make(telebot.Row, 0, 7)
+		}
+	}
+	if len(week) > 0 {
+		for len(week) < 7 {
+			week = append(week, noop(" "))
+		}
+		rows = append(rows, week)
+	}
+
+	btn.Inline(rows...)
+	return btn
+}
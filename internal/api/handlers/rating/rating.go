@@ -0,0 +1,244 @@
+package rating
+
+import (
+	"context"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
+	"gopkg.in/telebot.v3"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// PromptRating asks the user to rate a title they just watched
+func (h *RatingHandler) PromptRating(ctx telebot.Context, showType string, apiId int64, season int32, title string) error {
+	const op = "rating.PromptRating"
+	h.app.Logger.Info(op, ctx, "Sending rating prompt",
+		"type", showType, "api_id", apiId, "season", season)
+
+	text := fmt.Sprintf("⭐ How would you rate *%s*?", title)
+	if season > 0 {
+		text = fmt.Sprintf("⭐ How would you rate season %d of *%s*?", season, title)
+	}
+
+	_, err := ctx.Bot().Send(ctx.Chat(), text, ratingKeyboard(ratedTitle{showType: showType, apiId: apiId, season: season}), telebot.ModeMarkdown)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send rating prompt", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (h *RatingHandler) handleRate(ctx telebot.Context, data string) error {
+	const op = "rating.handleRate"
+	h.app.Logger.Info(op, ctx, "Saving rating", "data", data)
+
+	parts := strings.Split(data, "-")
+	if len(parts) != 4 {
+		h.app.Logger.Warning(op, ctx, "Malformed rating data", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidRating})
+	}
+
+	title, err := parseRatedTitle(parts[:3])
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed rated title", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidRating})
+	}
+
+	score, err := strconv.Atoi(parts[3])
+	if err != nil || score < minRating || score > maxRating {
+		h.app.Logger.Warning(op, ctx, "Rating out of range", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidRating})
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	err = h.app.Repository.Ratings.UpsertRating(ctxDb, database.UpsertRatingParams{
+		UserID:       ctx.Sender().ID,
+		ApiID:        title.apiId,
+		Type:         title.showType,
+		SeasonNumber: title.season,
+		Rating:       int16(score),
+	})
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to save rating", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	btn := &telebot.ReplyMarkup{}
+	btn.Inline(btn.Row(
+		btn.Data("✍️ Add a review", "", fmt.Sprintf("rating|review|%s", title.callbackData())),
+		btn.Data("✅ Done", "", "rating|done|-"),
+	))
+
+	if err = ctx.Edit(fmt.Sprintf(messages.RatingSaved, score), btn, telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to confirm rating", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "Rating saved successfully",
+		"type", title.showType, "api_id", title.apiId, "season", title.season, "rating", score)
+	return ctx.Respond()
+}
+
+// handleReview waits for the review of a saved rating as the next text message
+func (h *RatingHandler) handleReview(ctx telebot.Context, data string) error {
+	const op = "rating.handleReview"
+	h.app.Logger.Info(op, ctx, "Waiting for review", "data", data)
+
+	title, err := parseRatedTitle(strings.Split(data, "-"))
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed rated title", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidRating})
+	}
+
+	h.app.Cache.UserCache.SetReviewStart(ctx.Sender().ID, title.showType, title.apiId, title.season)
+
+	if err = ctx.Edit(fmt.Sprintf(messages.ReviewPrompt, maxReviewLength)); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send review prompt", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	return ctx.Respond()
+}
+
+// HandleReviewInput stores the text message of a user as the review of the rating picked before
+func (h *RatingHandler) HandleReviewInput(ctx telebot.Context) error {
+	const op = "rating.HandleReviewInput"
+	userId := ctx.Sender().ID
+	h.app.Logger.Info(op, ctx, "Processing review input")
+
+	_, userCache := h.app.Cache.UserCache.Fetch(userId)
+	state := userCache.ReviewState
+
+	review := strings.TrimSpace(ctx.Message().Text)
+	if review == "" {
+		h.app.Logger.Warning(op, ctx, "Empty review received")
+		return ctx.Send(messages.ReviewEmpty)
+	}
+	if utf8.RuneCountInString(review) > maxReviewLength {
+		review = string([]rune(review)[:maxReviewLength])
+	}
+
+	h.app.Cache.UserCache.SetReviewDone(userId)
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	found, err := h.app.Repository.Ratings.SetRatingReview(ctxDb, database.SetRatingReviewParams{
+		UserID:       userId,
+		ApiID:        state.ApiId,
+		Type:         state.ShowType,
+		SeasonNumber: state.SeasonNumber,
+		Review:       &review,
+	})
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to save review", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	if !found {
+		h.app.Logger.Warning(op, ctx, "Review for a missing rating",
+			"type", state.ShowType, "api_id", state.ApiId, "season", state.SeasonNumber)
+		return ctx.Send(messages.RatingNotFound)
+	}
+
+	h.app.Logger.Info(op, ctx, "Review saved successfully",
+		"type", state.ShowType, "api_id", state.ApiId, "season", state.SeasonNumber, "length", utf8.RuneCountInString(review))
+	return ctx.Send(messages.ReviewSaved)
+}
+
+func (h *RatingHandler) RatingCallback(ctx telebot.Context) error {
+	const op = "rating.RatingCallback"
+	callback := ctx.Callback()
+	trimmed := strings.TrimSpace(callback.Data)
+	h.app.Logger.Info(op, ctx, "Processing rating callback", "callback_data", trimmed)
+
+	if !strings.HasPrefix(trimmed, "rating|") {
+		h.app.Logger.Warning(op, ctx, "Invalid callback prefix", "callback_data", trimmed)
+		return nil
+	}
+
+	dataParts := strings.Split(trimmed, "|")
+	if len(dataParts) != 3 {
+		h.app.Logger.Warning(op, ctx, "Malformed callback data", "callback_data", callback.Data,
+			"parts_count", len(dataParts))
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	action := dataParts[1]
+	data := dataParts[2]
+	h.app.Logger.Debug(op, ctx, "Processing callback action", "action", action, "data", data)
+
+	switch action {
+	case "rate":
+		return h.handleRate(ctx, data)
+
+	case "review":
+		return h.handleReview(ctx, data)
+
+	case "done":
+		if _, err := ctx.Bot().EditReplyMarkup(ctx.Message(), nil); err != nil {
+			h.app.Logger.Warning(op, ctx, "Failed to remove rating keyboard", "error", err.Error())
+		}
+		return ctx.Respond()
+
+	case "skip":
+		if err := ctx.Delete(); err != nil {
+			h.app.Logger.Warning(op, ctx, "Failed to delete rating prompt", "error", err.Error())
+		}
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.RatingSkipped})
+
+	default:
+		h.app.Logger.Warning(op, ctx, "Unknown callback action", "action", action)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.UnknownAction})
+	}
+}
+
+// ratingKeyboard offers the scores from 1 to 10 in two rows
+func ratingKeyboard(title ratedTitle) *telebot.ReplyMarkup {
+	btn := &telebot.ReplyMarkup{}
+	rows := make([]telebot.Row, 2)
+	for score := minRating; score <= maxRating; score++ {
+		row := (score - minRating) * 2 / (maxRating - minRating + 1)
+		rows[row] = append(rows[row], btn.Data(strconv.Itoa(score), "",
+			fmt.Sprintf("rating|rate|%s-%d", title.callbackData(), score)))
+	}
+	rows = append(rows, btn.Row(btn.Data("⏭ Skip", "", "rating|skip|-")))
+
+	btn.Inline(rows...)
+	return btn
+}
+
+// callbackData encodes the title as <type>-<api_id>-<season_number>
+func (t ratedTitle) callbackData() string {
+	return fmt.Sprintf("%s-%d-%d", t.showType, t.apiId, t.season)
+}
+
+// parseRatedTitle reads the parts written by callbackData
+func parseRatedTitle(parts []string) (ratedTitle, error) {
+	if len(parts) != 3 {
+		return ratedTitle{}, fmt.Errorf("expected 3 parts, got %d", len(parts))
+	}
+
+	showType := parts[0]
+	if showType != constants.MovieType && showType != constants.TVShowType {
+		return ratedTitle{}, fmt.Errorf("unknown title type %q", showType)
+	}
+
+	apiId, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return ratedTitle{}, err
+	}
+
+	season, err := strconv.Atoi(parts[2])
+	if err != nil || season < 0 {
+		return ratedTitle{}, fmt.Errorf("invalid season %q", parts[2])
+	}
+
+	return ratedTitle{showType: showType, apiId: apiId, season: int32(season)}, nil
+}
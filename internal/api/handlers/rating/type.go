@@ -0,0 +1,31 @@
+package rating
+
+import (
+	"github.com/erkinov-wtf/movie-manager-bot/internal/api/interfaces"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+)
+
+type RatingHandler struct {
+	app *app.App
+}
+
+func NewRatingHandler(app *app.App) interfaces.RatingInterface {
+	return &RatingHandler{
+		app: app,
+	}
+}
+
+// ratedTitle is a movie, a show or a season of a show, season 0 stands for the whole title
+type ratedTitle struct {
+	showType string
+	apiId    int64
+	season   int32
+}
+
+const (
+	minRating = 1
+	maxRating = 10
+
+	// maxReviewLength keeps reviews to a few sentences, detail cards show a shortened version
+	maxReviewLength = 500
+)
@@ -0,0 +1,654 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/providers"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
+	"gopkg.in/telebot.v3"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var toggles = []notificationToggle{
+	{kind: constants.NotificationKindNewSeason, label: "New seasons"},
+	{kind: constants.NotificationKindStatusChange, label: "Status changes"},
+	{kind: constants.NotificationKindEpisodeReminder, label: "Episode reminders"},
+	{kind: constants.NotificationKindMovieRelease, label: "Movie releases"},
+	{kind: constants.NotificationKindDigest, label: "Digest"},
+	{kind: constants.NotificationKindAvailability, label: "Streaming availability"},
+}
+
+func (h *SettingsHandler) Settings(ctx telebot.Context) error {
+	const op = "settings.Settings"
+	h.app.Logger.Info(op, ctx, "Settings command received")
+
+	settings, err := h.loadSettings(ctx)
+	if err != nil {
+		return ctx.Send(messages.InternalError)
+	}
+
+	payload := strings.Fields(ctx.Message().Payload)
+	if len(payload) == 2 && payload[0] == "tz" {
+		if _, err = time.LoadLocation(payload[1]); err != nil {
+			h.app.Logger.Warning(op, ctx, "Invalid timezone received", "timezone", payload[1])
+			return ctx.Send(messages.InvalidTimezone)
+		}
+
+		settings.Timezone = payload[1]
+		if err = h.saveSettings(ctx, settings); err != nil {
+			return ctx.Send(messages.InternalError)
+		}
+	}
+
+	if len(payload) == 2 && payload[0] == "region" {
+		region, ok := parseRegion(payload[1])
+		if !ok {
+			h.app.Logger.Warning(op, ctx, "Invalid region received", "region", payload[1])
+			return ctx.Send(messages.InvalidRegion)
+		}
+
+		if err = h.changeRegion(ctx, &settings, region); err != nil {
+			return ctx.Send(messages.InternalError)
+		}
+	}
+
+	err = ctx.Send(formatSettings(settings), settingsMenu(settings), telebot.ModeMarkdown)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send settings menu", "error", err.Error())
+		return err
+	}
+
+	h.app.Logger.Info(op, ctx, "Settings menu sent successfully")
+	return nil
+}
+
+func (h *SettingsHandler) SettingsCallback(ctx telebot.Context) error {
+	const op = "settings.SettingsCallback"
+	callback := ctx.Callback()
+	trimmed := strings.TrimSpace(callback.Data)
+	h.app.Logger.Info(op, ctx, "Processing settings callback", "callback_data", trimmed)
+
+	if !strings.HasPrefix(trimmed, "settings|") {
+		h.app.Logger.Warning(op, ctx, "Invalid callback prefix", "callback_data", trimmed)
+		return ctx.Send(messages.InternalError)
+	}
+
+	dataParts := strings.Split(trimmed, "|")
+	if len(dataParts) != 3 {
+		h.app.Logger.Warning(op, ctx, "Malformed callback data", "callback_data", callback.Data,
+			"parts_count", len(dataParts))
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	action := dataParts[1]
+	data := dataParts[2]
+	h.app.Logger.Debug(op, ctx, "Processing callback action", "action", action, "data", data)
+
+	switch action {
+	case "menu":
+		return h.showMenu(ctx)
+
+	case "toggle":
+		return h.handleToggle(ctx, data)
+
+	case "digest_mode":
+		return h.handleDigestMode(ctx, data)
+
+	case "timezone":
+		return h.showTimezones(ctx)
+
+	case "set_tz":
+		return h.handleSetTimezone(ctx, data)
+
+	case "quiet":
+		return h.showQuietStart(ctx)
+
+	case "quiet_start":
+		return h.showQuietEnd(ctx, data)
+
+	case "quiet_end":
+		return h.handleQuietHours(ctx, data)
+
+	case "quiet_off":
+		return h.handleQuietOff(ctx)
+
+	case "region":
+		return h.showRegions(ctx)
+
+	case "set_region":
+		return h.handleSetRegion(ctx, data)
+
+	case "providers":
+		return h.showProviders(ctx, data)
+
+	case "provider":
+		return h.handleProvider(ctx, data)
+
+	case "close":
+		if err := ctx.Delete(); err != nil {
+			h.app.Logger.Error(op, ctx, "Failed to delete settings menu", "error", err.Error())
+		}
+		return ctx.Respond()
+
+	default:
+		h.app.Logger.Warning(op, ctx, "Unknown callback action", "action", action)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.UnknownAction})
+	}
+}
+
+func (h *SettingsHandler) showMenu(ctx telebot.Context) error {
+	const op = "settings.showMenu"
+	settings, err := h.loadSettings(ctx)
+	if err != nil {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+
+	if err = ctx.Edit(formatSettings(settings), settingsMenu(settings), telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to edit settings menu", "error", err.Error())
+		return err
+	}
+
+	return ctx.Respond()
+}
+
+func (h *SettingsHandler) handleToggle(ctx telebot.Context, kind string) error {
+	const op = "settings.handleToggle"
+	settings, err := h.loadSettings(ctx)
+	if err != nil {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+
+	enabled := toggleField(&settings, kind)
+	if enabled == nil {
+		h.app.Logger.Warning(op, ctx, "Unknown notification kind", "kind", kind)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.UnknownAction})
+	}
+	*enabled = !*enabled
+
+	return h.saveAndRefresh(ctx, settings)
+}
+
+func (h *SettingsHandler) handleDigestMode(ctx telebot.Context, mode string) error {
+	const op = "settings.handleDigestMode"
+	if _, ok := digestModeLabels[mode]; !ok {
+		h.app.Logger.Warning(op, ctx, "Unknown digest mode", "mode", mode)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.UnknownAction})
+	}
+
+	settings, err := h.loadSettings(ctx)
+	if err != nil {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+	settings.DigestMode = mode
+
+	return h.saveAndRefresh(ctx, settings)
+}
+
+func (h *SettingsHandler) showTimezones(ctx telebot.Context) error {
+	const op = "settings.showTimezones"
+	btn := &telebot.ReplyMarkup{}
+	var btnRows []telebot.Row
+	for i := 0; i < len(presetTimezones); i += 2 {
+		row := telebot.Row{btn.Data(presetTimezones[i], "", "settings|set_tz|"+presetTimezones[i])}
+		if i+1 < len(presetTimezones) {
+			row = append(row, btn.Data(presetTimezones[i+1], "", "settings|set_tz|"+presetTimezones[i+1]))
+		}
+		btnRows = append(btnRows, row)
+	}
+	btnRows = append(btnRows, btn.Row(btn.Data("⬅️ Back", "", "settings|menu|")))
+	btn.Inline(btnRows...)
+
+	if err := ctx.Edit(messages.SettingsSelectTimezone, btn, telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to show timezone picker", "error", err.Error())
+		return err
+	}
+
+	return ctx.Respond()
+}
+
+func (h *SettingsHandler) handleSetTimezone(ctx telebot.Context, timezone string) error {
+	const op = "settings.handleSetTimezone"
+	if _, err := time.LoadLocation(timezone); err != nil {
+		h.app.Logger.Warning(op, ctx, "Invalid timezone received", "timezone", timezone)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidTimezone})
+	}
+
+	settings, err := h.loadSettings(ctx)
+	if err != nil {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+	settings.Timezone = timezone
+
+	return h.saveAndRefresh(ctx, settings)
+}
+
+func (h *SettingsHandler) showQuietStart(ctx telebot.Context) error {
+	const op = "settings.showQuietStart"
+	btn := hourGrid(func(hour int) string {
+		return fmt.Sprintf("settings|quiet_start|%d", hour)
+	})
+	btn.InlineKeyboard = append(btn.InlineKeyboard, []telebot.InlineButton{
+		{Text: "🔔 Turn off", Data: "settings|quiet_off|"},
+		{Text: "⬅️ Back", Data: "settings|menu|"},
+	})
+
+	if err := ctx.Edit(messages.SettingsSelectQuietStart, btn); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to show quiet hours start picker", "error", err.Error())
+		return err
+	}
+
+	return ctx.Respond()
+}
+
+func (h *SettingsHandler) showQuietEnd(ctx telebot.Context, data string) error {
+	const op = "settings.showQuietEnd"
+	start, err := parseHour(data)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Invalid quiet hours start", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	btn := hourGrid(func(hour int) string {
+		return fmt.Sprintf("settings|quiet_end|%d-%d", start, hour)
+	})
+	btn.InlineKeyboard = append(btn.InlineKeyboard, []telebot.InlineButton{
+		{Text: "⬅️ Back", Data: "settings|quiet|"},
+	})
+
+	if err = ctx.Edit(fmt.Sprintf(messages.SettingsSelectQuietEnd, start), btn); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to show quiet hours end picker", "error", err.Error())
+		return err
+	}
+
+	return ctx.Respond()
+}
+
+func (h *SettingsHandler) handleQuietHours(ctx telebot.Context, data string) error {
+	const op = "settings.handleQuietHours"
+	hours := strings.Split(data, "-")
+	if len(hours) != 2 {
+		h.app.Logger.Warning(op, ctx, "Malformed quiet hours data", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	start, err := parseHour(hours[0])
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Invalid quiet hours start", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+	end, err := parseHour(hours[1])
+	if err != nil || end == start {
+		h.app.Logger.Warning(op, ctx, "Invalid quiet hours end", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidQuietHours})
+	}
+
+	settings, err := h.loadSettings(ctx)
+	if err != nil {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+	settings.QuietHoursStart = &start
+	settings.QuietHoursEnd = &end
+
+	return h.saveAndRefresh(ctx, settings)
+}
+
+func (h *SettingsHandler) handleQuietOff(ctx telebot.Context) error {
+	settings, err := h.loadSettings(ctx)
+	if err != nil {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+	settings.QuietHoursStart = nil
+	settings.QuietHoursEnd = nil
+
+	return h.saveAndRefresh(ctx, settings)
+}
+
+func (h *SettingsHandler) showRegions(ctx telebot.Context) error {
+	const op = "settings.showRegions"
+	btn := &telebot.ReplyMarkup{}
+	var btnRows []telebot.Row
+	for i := 0; i < len(presetRegions); i += 4 {
+		var row telebot.Row
+		for _, region := range presetRegions[i:min(i+4, len(presetRegions))] {
+			row = append(row, btn.Data(region, "", "settings|set_region|"+region))
+		}
+		btnRows = append(btnRows, row)
+	}
+	btnRows = append(btnRows, btn.Row(btn.Data("⬅️ Back", "", "settings|menu|")))
+	btn.Inline(btnRows...)
+
+	if err := ctx.Edit(messages.SettingsSelectRegion, btn, telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to show region picker", "error", err.Error())
+		return err
+	}
+
+	return ctx.Respond()
+}
+
+func (h *SettingsHandler) handleSetRegion(ctx telebot.Context, data string) error {
+	const op = "settings.handleSetRegion"
+	region, ok := parseRegion(data)
+	if !ok {
+		h.app.Logger.Warning(op, ctx, "Invalid region received", "region", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidRegion})
+	}
+
+	settings, err := h.loadSettings(ctx)
+	if err != nil {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+
+	if err = h.changeRegion(ctx, &settings, region); err != nil {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+
+	if err = ctx.Edit(formatSettings(settings), settingsMenu(settings), telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to refresh settings menu", "error", err.Error())
+		return err
+	}
+
+	return ctx.Respond(&telebot.CallbackResponse{Text: messages.SettingsSaved})
+}
+
+// changeRegion stores a new region. What the watchlist was available on belongs to the old region, so it is
+// forgotten and the next availability check starts from a fresh baseline instead of alerting about everything.
+func (h *SettingsHandler) changeRegion(ctx telebot.Context, settings *database.UserSetting, region string) error {
+	const op = "settings.changeRegion"
+	if settings.Region == region {
+		return nil
+	}
+
+	settings.Region = region
+	if err := h.saveSettings(ctx, *settings); err != nil {
+		return err
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	if err := h.app.Repository.Watchlists.ResetWatchlistProviders(ctxDb, ctx.Sender().ID); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to reset watchlist availability", "error", err.Error())
+		return err
+	}
+
+	h.app.Logger.Info(op, ctx, "Region changed", "region", region)
+	return nil
+}
+
+// showProviders lists the streaming services of the user's region page by page, subscribed ones are checked
+func (h *SettingsHandler) showProviders(ctx telebot.Context, data string) error {
+	const op = "settings.showProviders"
+	page, err := strconv.Atoi(data)
+	if err != nil || page < 0 {
+		page = 0
+	}
+
+	settings, err := h.loadSettings(ctx)
+	if err != nil {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+
+	fetchCtx, cancel := context.WithTimeout(h.app.Ctx, 10*time.Second)
+	defer cancel()
+
+	list, err := providers.GetRegionProviders(fetchCtx, h.app, settings.Region, ctx.Sender().ID)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to fetch providers of region",
+			"region", settings.Region, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+
+	if err = ctx.Edit(fmt.Sprintf(messages.SettingsSelectProviders, settings.Region), providersMenu(settings, list, page)); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to show provider picker", "error", err.Error())
+		return err
+	}
+
+	return ctx.Respond()
+}
+
+// handleProvider subscribes to or unsubscribes from a streaming service and redraws the page it was tapped on
+func (h *SettingsHandler) handleProvider(ctx telebot.Context, data string) error {
+	const op = "settings.handleProvider"
+	parts := strings.Split(data, "-")
+	if len(parts) != 2 {
+		h.app.Logger.Warning(op, ctx, "Malformed provider data", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	providerId, err := strconv.ParseInt(parts[0], 10, 32)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Invalid provider ID", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	settings, err := h.loadSettings(ctx)
+	if err != nil {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+
+	if idx := slices.Index(settings.SubscribedProviders, int32(providerId)); idx >= 0 {
+		settings.SubscribedProviders = slices.Delete(settings.SubscribedProviders, idx, idx+1)
+	} else {
+		settings.SubscribedProviders = append(settings.SubscribedProviders, int32(providerId))
+	}
+
+	if err = h.saveSettings(ctx, settings); err != nil {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+
+	return h.showProviders(ctx, parts[1])
+}
+
+// saveAndRefresh stores the settings and redraws the main menu in place
+func (h *SettingsHandler) saveAndRefresh(ctx telebot.Context, settings database.UserSetting) error {
+	const op = "settings.saveAndRefresh"
+	if err := h.saveSettings(ctx, settings); err != nil {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InternalError})
+	}
+
+	if err := ctx.Edit(formatSettings(settings), settingsMenu(settings), telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to refresh settings menu", "error", err.Error())
+		return err
+	}
+
+	return ctx.Respond(&telebot.CallbackResponse{Text: messages.SettingsSaved})
+}
+
+func (h *SettingsHandler) loadSettings(ctx telebot.Context) (database.UserSetting, error) {
+	const op = "settings.loadSettings"
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	settings, err := h.app.Repository.Settings.GetUserSettings(ctxDb, ctx.Sender().ID)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to get user settings", "error", err.Error())
+	}
+	return settings, err
+}
+
+func (h *SettingsHandler) saveSettings(ctx telebot.Context, settings database.UserSetting) error {
+	const op = "settings.saveSettings"
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	err := h.app.Repository.Settings.UpsertUserSettings(ctxDb, database.UpsertUserSettingsParams{
+		UserID:                 ctx.Sender().ID,
+		Timezone:               settings.Timezone,
+		QuietHoursStart:        settings.QuietHoursStart,
+		QuietHoursEnd:          settings.QuietHoursEnd,
+		NotifyNewSeasons:       settings.NotifyNewSeasons,
+		NotifyStatusChanges:    settings.NotifyStatusChanges,
+		NotifyEpisodeReminders: settings.NotifyEpisodeReminders,
+		NotifyReleases:         settings.NotifyReleases,
+		NotifyDigest:           settings.NotifyDigest,
+		DigestMode:             settings.DigestMode,
+		Region:                 settings.Region,
+		SubscribedProviders:    settings.SubscribedProviders,
+		NotifyAvailability:     settings.NotifyAvailability,
+	})
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to save user settings", "error", err.Error())
+		return err
+	}
+
+	h.app.Logger.Info(op, ctx, "User settings saved", "timezone", settings.Timezone)
+	return nil
+}
+
+// toggleField points at the settings flag of a notification kind, nil for unknown kinds
+func toggleField(settings *database.UserSetting, kind string) *bool {
+	switch kind {
+	case constants.NotificationKindNewSeason:
+		return &settings.NotifyNewSeasons
+	case constants.NotificationKindStatusChange:
+		return &settings.NotifyStatusChanges
+	case constants.NotificationKindEpisodeReminder:
+		return &settings.NotifyEpisodeReminders
+	case constants.NotificationKindMovieRelease:
+		return &settings.NotifyReleases
+	case constants.NotificationKindDigest:
+		return &settings.NotifyDigest
+	case constants.NotificationKindAvailability:
+		return &settings.NotifyAvailability
+	default:
+		return nil
+	}
+}
+
+func formatSettings(settings database.UserSetting) string {
+	quietHours := "Off"
+	if settings.QuietHoursStart != nil && settings.QuietHoursEnd != nil {
+		quietHours = fmt.Sprintf("%02d:00 - %02d:00", *settings.QuietHoursStart, *settings.QuietHoursEnd)
+	}
+
+	return fmt.Sprintf(`⚙️ *Notification Settings*
+
+🌍 *Timezone:* `+"`%s`"+`
+🗺 *Region:* `+"`%s`"+`
+📺 *Streaming Services:* %d selected
+🌙 *Quiet Hours:* %s
+📬 *Show Updates:* %s
+
+Messages arriving during quiet hours are delivered once they end. Tap a notification type below to switch it on or off.`,
+		settings.Timezone,
+		settings.Region,
+		len(settings.SubscribedProviders),
+		quietHours,
+		digestModeLabels[settings.DigestMode],
+	)
+}
+
+func settingsMenu(settings database.UserSetting) *telebot.ReplyMarkup {
+	btn := &telebot.ReplyMarkup{}
+	var btnRows []telebot.Row
+	for _, toggle := range toggles {
+		mark := "❌"
+		if enabled := toggleField(&settings, toggle.kind); enabled != nil && *enabled {
+			mark = "✅"
+		}
+		btnRows = append(btnRows, btn.Row(btn.Data(fmt.Sprintf("%s %s", mark, toggle.label), "", "settings|toggle|"+toggle.kind)))
+	}
+
+	btnRows = append(btnRows,
+		btn.Row(
+			btn.Data("🌍 Timezone", "", "settings|timezone|"),
+			btn.Data("🌙 Quiet Hours", "", "settings|quiet|"),
+		),
+		btn.Row(
+			btn.Data("🗺 Region", "", "settings|region|"),
+			btn.Data("📺 Streaming Services", "", "settings|providers|0"),
+		),
+		btn.Row(btn.Data("📬 "+digestModeLabels[settings.DigestMode], "", "settings|digest_mode|"+nextDigestMode(settings.DigestMode))),
+		btn.Row(btn.Data("✖️ Close", "", "settings|close|")),
+	)
+	btn.Inline(btnRows...)
+	return btn
+}
+
+// providersMenu lays out one page of streaming services in two columns, followed by paging and back buttons
+func providersMenu(settings database.UserSetting, list []providers.Provider, page int) *telebot.ReplyMarkup {
+	pages := max((len(list)+providersPageSize-1)/providersPageSize, 1)
+	page = min(page, pages-1)
+	pageItems := list[page*providersPageSize : min((page+1)*providersPageSize, len(list))]
+
+	btn := &telebot.ReplyMarkup{}
+	var btnRows []telebot.Row
+	for i := 0; i < len(pageItems); i += 2 {
+		var row telebot.Row
+		for _, provider := range pageItems[i:min(i+2, len(pageItems))] {
+			mark := "❌"
+			if slices.Contains(settings.SubscribedProviders, provider.ID) {
+				mark = "✅"
+			}
+			row = append(row, btn.Data(fmt.Sprintf("%s %s", mark, provider.Name), "",
+				fmt.Sprintf("settings|provider|%d-%d", provider.ID, page)))
+		}
+		btnRows = append(btnRows, row)
+	}
+
+	var navRow telebot.Row
+	if page > 0 {
+		navRow = append(navRow, btn.Data("⬅️ Prev", "", fmt.Sprintf("settings|providers|%d", page-1)))
+	}
+	if page < pages-1 {
+		navRow = append(navRow, btn.Data("Next ➡️", "", fmt.Sprintf("settings|providers|%d", page+1)))
+	}
+	if len(navRow) > 0 {
+		btnRows = append(btnRows, navRow)
+	}
+	btnRows = append(btnRows, btn.Row(btn.Data("⬅️ Back", "", "settings|menu|")))
+	btn.Inline(btnRows...)
+	return btn
+}
+
+// parseRegion accepts a two letter country code in any case
+func parseRegion(value string) (string, bool) {
+	region := strings.ToUpper(value)
+	if len(region) != 2 || region[0] < 'A' || region[0] > 'Z' || region[1] < 'A' || region[1] > 'Z' {
+		return "", false
+	}
+	return region, true
+}
+
+// nextDigestMode cycles through the digest modes on every tap of the menu button
+func nextDigestMode(mode string) string {
+	switch mode {
+	case constants.DigestModeOff:
+		return constants.DigestModeCycle
+	case constants.DigestModeCycle:
+		return constants.DigestModeWeekly
+	default:
+		return constants.DigestModeOff
+	}
+}
+
+// hourGrid lays out the 24 hours of a day in rows of six buttons
+func hourGrid(data func(hour int) string) *telebot.ReplyMarkup {
+	btn := &telebot.ReplyMarkup{}
+	var btnRows []telebot.Row
+	for row := 0; row < 4; row++ {
+		var btnRow telebot.Row
+		for hour := row * 6; hour < row*6+6; hour++ {
+			btnRow = append(btnRow, btn.Data(fmt.Sprintf("%02d", hour), "", data(hour)))
+		}
+		btnRows = append(btnRows, btnRow)
+	}
+	btn.Inline(btnRows...)
+	return btn
+}
+
+func parseHour(value string) (int32, error) {
+	hour, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("hour out of range: %d", hour)
+	}
+	return int32(hour), nil
+}
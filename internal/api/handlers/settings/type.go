@@ -0,0 +1,56 @@
+package settings
+
+import (
+	"github.com/erkinov-wtf/movie-manager-bot/internal/api/interfaces"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+)
+
+type SettingsHandler struct {
+	app *app.App
+}
+
+func NewSettingsHandler(app *app.App) interfaces.SettingsInterface {
+	return &SettingsHandler{
+		app: app,
+	}
+}
+
+// presetTimezones are offered as buttons, any other IANA name can be set with "/settings tz <name>"
+var presetTimezones = []string{
+	"UTC",
+	"Europe/London",
+	"Europe/Berlin",
+	"Europe/Moscow",
+	"Asia/Tashkent",
+	"Asia/Dubai",
+	"Asia/Kolkata",
+	"Asia/Tokyo",
+	"America/New_York",
+	"America/Chicago",
+	"America/Los_Angeles",
+	"Australia/Sydney",
+}
+
+// presetRegions are offered as buttons, any other country code can be set with "/settings region <code>"
+var presetRegions = []string{
+	"US", "GB", "CA", "AU",
+	"DE", "FR", "ES", "IT",
+	"NL", "PL", "TR", "RU",
+	"IN", "JP", "KR", "BR",
+}
+
+// providersPageSize is how many streaming services one page of the picker shows
+const providersPageSize = 16
+
+// notificationToggle describes one notification kind the user can switch on and off
+type notificationToggle struct {
+	kind  string
+	label string
+}
+
+var digestModeLabels = map[string]string{
+	constants.DigestModeOff:    "One message per update",
+	constants.DigestModeCycle:  "Digest after every check",
+	constants.DigestModeWeekly: "Weekly digest",
+}
@@ -0,0 +1,580 @@
+package tv
+
+import (
+	"context"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database/repository"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/tv"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"gopkg.in/telebot.v3"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// episodesPerRow keeps the episode keyboard readable on phones
+const episodesPerRow = 5
+
+// fetchSeasons fetches the seasons from..to of a show concurrently, ordered by season number
+func (h *TVHandler) fetchSeasons(userId, showId int64, from, to int) ([]*tv.Season, error) {
+	if from > to {
+		return nil, nil
+	}
+
+	type seasonResult struct {
+		Index  int
+		Season *tv.Season
+		Error  error
+	}
+
+	count := to - from + 1
+	resultChan := make(chan seasonResult, count)
+
+	// Create a context with a reasonable timeout for all API calls, it ends early when the bot shuts down
+	fetchCtx, fetchCancel := context.WithTimeout(h.app.Ctx, 10*time.Second)
+	defer fetchCancel()
+
+	for i := from; i <= to; i++ {
+		go func(seasonIndex int) {
+			tvSeason, err := tv.GetSeason(fetchCtx, h.app, int(showId), seasonIndex, userId)
+			if err != nil {
+				err = fmt.Errorf("error fetching season %d: %v", seasonIndex, err)
+			}
+			resultChan <- seasonResult{Index: seasonIndex - from, Season: tvSeason, Error: err}
+		}(i)
+	}
+
+	seasons := make([]*tv.Season, count)
+	for i := 0; i < count; i++ {
+		select {
+		case result := <-resultChan:
+			if result.Error != nil {
+				return nil, result.Error
+			}
+			seasons[result.Index] = result.Season
+		case <-fetchCtx.Done():
+			return nil, fmt.Errorf("timed out while fetching seasons: %w", fetchCtx.Err())
+		}
+	}
+
+	return seasons, nil
+}
+
+// legacySeasons returns the seasons a show was marked watched with before episode watches existed. Such shows
+// only have totals, so their seasons are recorded episode by episode ahead of the first change to keep them.
+// The database reads and the TMDB fetch have deadlines of their own, the fetch may take a while for long shows.
+func (h *TVHandler) legacySeasons(userId, showId int64) ([]*tv.Season, error) {
+	ctxDb, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	totals, err := h.app.Repository.Episodes.GetEpisodeWatchTotals(ctxDb, userId, showId)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching episode watch totals: %w", err)
+	}
+	if totals.Episodes > 0 {
+		return nil, nil
+	}
+
+	watchedSeasons, err := h.app.Repository.TVShows.GetWatchedSeasons(ctxDb, showId, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching watched seasons: %w", err)
+	}
+	cancel()
+
+	return h.fetchSeasons(userId, showId, 1, int(watchedSeasons))
+}
+
+// recordSeasons marks every episode of the given seasons as watched, episodes watched before are kept as they are
+func recordSeasons(ctx context.Context, repos *repository.ReposTx, userId, showId int64, seasons []*tv.Season) error {
+	for _, season := range seasons {
+		for _, episode := range season.Episodes {
+			_, err := repos.Episodes.MarkEpisodeWatched(ctx, database.MarkEpisodeWatchedParams{
+				UserID:        userId,
+				ShowApiID:     showId,
+				SeasonNumber:  season.SeasonNumber,
+				EpisodeNumber: episode.EpisodeNumber,
+				Runtime:       episode.Runtime,
+			})
+			if err != nil {
+				return fmt.Errorf("error marking S%02dE%02d as watched: %w", season.SeasonNumber, episode.EpisodeNumber, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// completedSeasons counts the seasons watched in full, starting from the first one
+func completedSeasons(tvShow *tv.TV, watched []database.GetWatchedEpisodesRow) int32 {
+	episodeCounts := make(map[int32]int32, len(tvShow.SeasonList))
+	for _, season := range tvShow.SeasonList {
+		episodeCounts[season.SeasonNumber] = season.EpisodeCount
+	}
+
+	watchedCounts := make(map[int32]int32)
+	for _, episode := range watched {
+		watchedCounts[episode.SeasonNumber]++
+	}
+
+	var seasons int32
+	for {
+		total := episodeCounts[seasons+1]
+		if total == 0 || watchedCounts[seasons+1] < total {
+			return seasons
+		}
+		seasons++
+	}
+}
+
+// syncShowTotals derives the seasons, episodes and runtime of a tracked show from its episode watches. The show
+// starts being tracked, and leaves the watchlist, with its first watched episode.
+func syncShowTotals(ctx context.Context, repos *repository.ReposTx, userId int64, tvShow *tv.TV) (int32, database.GetEpisodeWatchTotalsRow, error) {
+	var totals database.GetEpisodeWatchTotalsRow
+	watched, err := repos.Episodes.GetWatchedEpisodes(ctx, userId, tvShow.Id)
+	if err != nil {
+		return 0, totals, fmt.Errorf("error fetching watched episodes: %w", err)
+	}
+
+	totals, err = repos.Episodes.GetEpisodeWatchTotals(ctx, userId, tvShow.Id)
+	if err != nil {
+		return 0, totals, fmt.Errorf("error fetching episode watch totals: %w", err)
+	}
+
+	seasons := completedSeasons(tvShow, watched)
+	exists, err := repos.TVShows.TVShowExists(ctx, tvShow.Id, userId)
+	if err != nil {
+		return 0, totals, fmt.Errorf("error checking tracked show: %w", err)
+	}
+
+	if exists {
+		err = repos.TVShows.UpdateTVShow(ctx, database.UpdateTVShowParams{
+			ApiID:    tvShow.Id,
+			UserID:   userId,
+			Seasons:  seasons,
+			Episodes: totals.Episodes,
+			Runtime:  totals.Runtime,
+		})
+	} else if totals.Episodes > 0 {
+		err = repos.TVShows.CreateTVShow(ctx, database.CreateTVShowParams{
+			UserID:   userId,
+			ApiID:    tvShow.Id,
+			Name:     tvShow.Name,
+			Seasons:  seasons,
+			Episodes: totals.Episodes,
+			Runtime:  totals.Runtime,
+			Status:   tvShow.Status,
+		})
+	}
+	if err != nil {
+		return 0, totals, fmt.Errorf("error storing show totals: %w", err)
+	}
+
+	if exists || totals.Episodes > 0 {
+		if err = syncWatchStatus(ctx, repos, userId, tvShow, seasons); err != nil {
+			return 0, totals, err
+		}
+	}
+
+	if totals.Episodes > 0 {
+		if err = repos.Watchlists.DeleteWatchlist(ctx, tvShow.Id, userId, constants.TVShowType); err != nil {
+			return 0, totals, fmt.Errorf("error deleting from watchlist: %w", err)
+		}
+	}
+
+	return seasons, totals, nil
+}
+
+// syncWatchStatus moves a tracked show to the watch status its progress calls for, see utils.AutoWatchStatus
+func syncWatchStatus(ctx context.Context, repos *repository.ReposTx, userId int64, tvShow *tv.TV, seasons int32) error {
+	trackedShow, err := repos.TVShows.GetUserTVShow(ctx, tvShow.Id, userId)
+	if err != nil {
+		return fmt.Errorf("error fetching tracked show: %w", err)
+	}
+
+	watchStatus := utils.AutoWatchStatus(trackedShow.WatchStatus, seasons, tvShow.Seasons, tvShow.Status)
+	if watchStatus == trackedShow.WatchStatus {
+		return nil
+	}
+
+	if err = repos.TVShows.UpdateTVShowWatchStatus(ctx, tvShow.Id, userId, watchStatus); err != nil {
+		return fmt.Errorf("error storing watch status: %w", err)
+	}
+	return nil
+}
+
+// showDetails returns the show picked in the season keyboard, fetching it again when another show was picked
+// since or the picked one expired
+func (h *TVHandler) showDetails(userId, showId int64) (*tv.TV, error) {
+	selectionMu.Lock()
+	picked, ok := selectedTvShow[userId]
+	selectionMu.Unlock()
+	if ok && picked.Id == showId && time.Now().Before(picked.expires) {
+		return picked.TV, nil
+	}
+
+	tmdbCtx, cancel := h.app.TMDBContext()
+	tvShow, err := tv.GetTV(tmdbCtx, h.app, int(showId), userId)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	pickShow(userId, tvShow)
+	return tvShow, nil
+}
+
+// pickShow remembers the show of a user's season keyboard
+func pickShow(userId int64, tvShow *tv.TV) {
+	selectionMu.Lock()
+	defer selectionMu.Unlock()
+	selectedTvShow[userId] = pickedShow{TV: tvShow, expires: time.Now().Add(selectionTTL)}
+}
+
+// pickedShowId returns the ID of the show in a user's season keyboard, even when its details expired
+func pickedShowId(userId int64) (int64, bool) {
+	selectionMu.Lock()
+	defer selectionMu.Unlock()
+
+	picked, ok := selectedTvShow[userId]
+	if !ok {
+		return 0, false
+	}
+	return picked.Id, true
+}
+
+// seasonDetails returns the season shown in the episode keyboard, fetching it again when another season was opened
+// since or the opened one expired
+func (h *TVHandler) seasonDetails(userId, showId int64, seasonNum int) (*tv.Season, error) {
+	selectionMu.Lock()
+	opened, ok := selectedSeason[userId]
+	selectionMu.Unlock()
+	if ok && opened.showId == showId && opened.SeasonNumber == int32(seasonNum) && time.Now().Before(opened.expires) {
+		return opened.Season, nil
+	}
+
+	tmdbCtx, cancel := h.app.TMDBContext()
+	season, err := tv.GetSeason(tmdbCtx, h.app, int(showId), seasonNum, userId)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	selectionMu.Lock()
+	selectedSeason[userId] = openSeason{Season: season, showId: showId, expires: time.Now().Add(selectionTTL)}
+	selectionMu.Unlock()
+	return season, nil
+}
+
+// watchedInSeason returns the watched episodes of one season. Seasons of shows marked watched before episode
+// watches existed count as fully watched.
+func (h *TVHandler) watchedInSeason(ctx context.Context, userId, showId int64, season *tv.Season) (map[int32]bool, error) {
+	watchedEpisodes, err := h.app.Repository.Episodes.GetWatchedEpisodes(ctx, userId, showId)
+	if err != nil {
+		return nil, err
+	}
+
+	watched := make(map[int32]bool)
+	for _, episode := range watchedEpisodes {
+		if episode.SeasonNumber == season.SeasonNumber {
+			watched[episode.EpisodeNumber] = true
+		}
+	}
+
+	if len(watchedEpisodes) == 0 {
+		watchedSeasons, err := h.app.Repository.TVShows.GetWatchedSeasons(ctx, showId, userId)
+		if err != nil {
+			return nil, err
+		}
+		if season.SeasonNumber <= watchedSeasons {
+			for _, episode := range season.Episodes {
+				watched[episode.EpisodeNumber] = true
+			}
+		}
+	}
+
+	return watched, nil
+}
+
+// episodesKeyboard renders the episodes of a season as toggles
+func episodesKeyboard(showId int64, season *tv.Season, watched map[int32]bool) (string, *telebot.ReplyMarkup) {
+	btn := &telebot.ReplyMarkup{}
+	var btnRows []telebot.Row
+	var row []telebot.Btn
+
+	for _, episode := range season.Episodes {
+		label := strconv.Itoa(int(episode.EpisodeNumber))
+		if watched[episode.EpisodeNumber] {
+			label = "✅ " + label
+		}
+
+		row = append(row, btn.Data(label, "",
+			fmt.Sprintf("tv|toggle_episode|%v-%v-%v", showId, season.SeasonNumber, episode.EpisodeNumber)))
+		if len(row) == episodesPerRow {
+			btnRows = append(btnRows, btn.Row(row...))
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		btnRows = append(btnRows, btn.Row(row...))
+	}
+
+	seasonRow := btn.Row(btn.Data("✅ Mark whole season", "", fmt.Sprintf("tv|watch_season|%v-%v", showId, season.SeasonNumber)))
+	if len(watched) > 0 {
+		seasonRow = append(seasonRow, btn.Data("⭐ Rate season", "", fmt.Sprintf("tv|rate|%v-%v", showId, season.SeasonNumber)))
+	}
+	btnRows = append(btnRows,
+		seasonRow,
+		btn.Row(btn.Data("⬅️ Back to seasons", "", fmt.Sprintf("tv|select_seasons|%v", showId))),
+	)
+	btn.Inline(btnRows...)
+
+	text := fmt.Sprintf("*%v*: %v of %v episodes watched\nTap an episode to mark or unmark it",
+		season.Name, len(watched), len(season.Episodes))
+	return text, btn
+}
+
+// parseEpisodeData splits callback data like <show_api_id>-<season_number>[-<episode_number>]
+func parseEpisodeData(data string, parts int) (int64, []int, error) {
+	fields := strings.Split(data, "-")
+	if len(fields) != parts {
+		return 0, nil, fmt.Errorf("expected %d parts, got %d", parts, len(fields))
+	}
+
+	showId, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	numbers := make([]int, 0, parts-1)
+	for _, field := range fields[1:] {
+		number, err := strconv.Atoi(field)
+		if err != nil {
+			return 0, nil, err
+		}
+		numbers = append(numbers, number)
+	}
+
+	return showId, numbers, nil
+}
+
+func (h *TVHandler) handleSeasonEpisodes(ctx telebot.Context, data string) error {
+	const op = "tv.handleSeasonEpisodes"
+	h.app.Logger.Info(op, ctx, "Showing episodes of season", "data", data)
+
+	showId, numbers, err := parseEpisodeData(data, 2)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed season data", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidSeason})
+	}
+
+	userId := ctx.Sender().ID
+	season, err := h.seasonDetails(userId, showId, numbers[0])
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error fetching season from TMDB", "tv_id", showId, "season", numbers[0], "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	watched, err := h.watchedInSeason(ctxDb, userId, showId, season)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error fetching watched episodes", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	text, btn := episodesKeyboard(showId, season, watched)
+	if _, err = ctx.Bot().Send(ctx.Chat(), text, btn, telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send episode keyboard", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "Episode keyboard displayed successfully",
+		"tv_id", showId, "season", season.SeasonNumber, "watched", len(watched))
+	return ctx.Respond()
+}
+
+func (h *TVHandler) handleToggleEpisode(ctx telebot.Context, data string) error {
+	const op = "tv.handleToggleEpisode"
+	h.app.Logger.Info(op, ctx, "Toggling episode watch", "data", data)
+
+	showId, numbers, err := parseEpisodeData(data, 3)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed episode data", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidEpisode})
+	}
+	seasonNum, episodeNum := numbers[0], numbers[1]
+
+	userId := ctx.Sender().ID
+	season, err := h.seasonDetails(userId, showId, seasonNum)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error fetching season from TMDB", "tv_id", showId, "season", seasonNum, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	var episode *tv.Episode
+	for i := range season.Episodes {
+		if season.Episodes[i].EpisodeNumber == int32(episodeNum) {
+			episode = &season.Episodes[i]
+			break
+		}
+	}
+	if episode == nil {
+		h.app.Logger.Warning(op, ctx, "Episode not found in season", "tv_id", showId, "season", seasonNum, "episode", episodeNum)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidEpisode})
+	}
+
+	var marked bool
+	err = h.updateEpisodeWatches(ctx, showId, func(ctxDb context.Context, repos *repository.ReposTx) error {
+		var err error
+		marked, err = repos.Episodes.MarkEpisodeWatched(ctxDb, database.MarkEpisodeWatchedParams{
+			UserID:        userId,
+			ShowApiID:     showId,
+			SeasonNumber:  int32(seasonNum),
+			EpisodeNumber: int32(episodeNum),
+			Runtime:       episode.Runtime,
+		})
+		if err != nil || marked {
+			return err
+		}
+
+		// The episode was watched already, so the tap takes it back
+		_, err = repos.Episodes.UnmarkEpisodeWatched(ctxDb, userId, showId, int32(seasonNum), int32(episodeNum))
+		return err
+	})
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to toggle episode watch", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.refreshEpisodesKeyboard(ctx, showId, season)
+
+	h.app.Logger.Info(op, ctx, "Episode watch toggled",
+		"tv_id", showId, "season", seasonNum, "episode", episodeNum, "watched", marked)
+	if marked {
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.EpisodeMarkedWatched})
+	}
+	return ctx.Respond(&telebot.CallbackResponse{Text: messages.EpisodeUnmarked})
+}
+
+func (h *TVHandler) handleWatchSeason(ctx telebot.Context, data string) error {
+	const op = "tv.handleWatchSeason"
+	h.app.Logger.Info(op, ctx, "Marking whole season as watched", "data", data)
+
+	showId, numbers, err := parseEpisodeData(data, 2)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed season data", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidSeason})
+	}
+
+	userId := ctx.Sender().ID
+	season, err := h.seasonDetails(userId, showId, numbers[0])
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error fetching season from TMDB", "tv_id", showId, "season", numbers[0], "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	err = h.updateEpisodeWatches(ctx, showId, func(ctxDb context.Context, repos *repository.ReposTx) error {
+		return recordSeasons(ctxDb, repos, userId, showId, []*tv.Season{season})
+	})
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to mark season as watched", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.refreshEpisodesKeyboard(ctx, showId, season)
+
+	h.app.Logger.Info(op, ctx, "Season marked as watched", "tv_id", showId, "season", season.SeasonNumber)
+	return ctx.Respond(&telebot.CallbackResponse{Text: messages.SeasonMarkedWatched})
+}
+
+// updateEpisodeWatches applies a change to the episode watches of a show and syncs the show totals in one transaction
+func (h *TVHandler) updateEpisodeWatches(ctx telebot.Context, showId int64, change func(context.Context, *repository.ReposTx) error) error {
+	const op = "tv.updateEpisodeWatches"
+	userId := ctx.Sender().ID
+
+	tvShow, err := h.showDetails(userId, showId)
+	if err != nil {
+		return fmt.Errorf("error fetching show from TMDB: %w", err)
+	}
+
+	legacy, err := h.legacySeasons(userId, showId)
+	if err != nil {
+		return err
+	}
+
+	// The deadline only covers the transaction, everything from TMDB is fetched by now
+	ctxDb, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	h.app.Logger.Debug(op, ctx, "Starting database transaction")
+	tx, err := h.app.Repository.BeginTx(ctxDb)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctxDb)
+
+	if err = recordSeasons(ctxDb, tx.Repos, userId, showId, legacy); err != nil {
+		return err
+	}
+	if err = change(ctxDb, tx.Repos); err != nil {
+		return err
+	}
+
+	seasons, totals, err := syncShowTotals(ctxDb, tx.Repos, userId, tvShow)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctxDb); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	h.app.Logger.Debug(op, ctx, "Show totals synced",
+		"tv_id", showId, "seasons", seasons, "episodes", totals.Episodes, "runtime", totals.Runtime)
+	return nil
+}
+
+// refreshEpisodesKeyboard redraws the episode keyboard after a change, a stale keyboard is only logged
+func (h *TVHandler) refreshEpisodesKeyboard(ctx telebot.Context, showId int64, season *tv.Season) {
+	const op = "tv.refreshEpisodesKeyboard"
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	watched, err := h.watchedInSeason(ctxDb, ctx.Sender().ID, showId, season)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Failed to fetch watched episodes", "tv_id", showId, "error", err.Error())
+		return
+	}
+
+	text, btn := episodesKeyboard(showId, season, watched)
+	if _, err = ctx.Bot().Edit(ctx.Message(), text, btn, telebot.ModeMarkdown); err != nil && !strings.Contains(err.Error(), "message is not modified") {
+		h.app.Logger.Warning(op, ctx, "Failed to update episode keyboard", "tv_id", showId, "error", err.Error())
+	}
+}
+
+// handleRate asks for a rating of a show or, for a season number above 0, of one of its seasons
+func (h *TVHandler) handleRate(ctx telebot.Context, data string) error {
+	const op = "tv.handleRate"
+	h.app.Logger.Info(op, ctx, "Rating TV show", "data", data)
+
+	showId, numbers, err := parseEpisodeData(data, 2)
+	if err != nil || numbers[0] < 0 {
+		h.app.Logger.Warning(op, ctx, "Malformed season data", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidSeason})
+	}
+
+	tvShow, err := h.showDetails(ctx.Sender().ID, showId)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error fetching TV show from TMDB", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	if err = h.ratingHandler.PromptRating(ctx, constants.TVShowType, showId, int32(numbers[0]), tvShow.Name); err != nil {
+		return ctx.Send(messages.InternalError)
+	}
+
+	return ctx.Respond()
+}
@@ -0,0 +1,110 @@
+package tv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	"gopkg.in/telebot.v3"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// handleStatusMenu offers the watch statuses a tracked show can be moved to by hand
+func (h *TVHandler) handleStatusMenu(ctx telebot.Context, data string) error {
+	const op = "tv.handleStatusMenu"
+	h.app.Logger.Info(op, ctx, "Showing watch status menu", "tv_id", data)
+
+	showId, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to parse TV show ID", "tv_id", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	trackedShow, err := h.app.Repository.TVShows.GetUserTVShow(ctxDb, showId, ctx.Sender().ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.app.Logger.Info(op, ctx, "TV show is not in watched library", "tv_id", showId)
+			return ctx.Respond(&telebot.CallbackResponse{Text: messages.NotInLibrary})
+		}
+		h.app.Logger.Error(op, ctx, "Error fetching tracked show", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	tvShow, err := h.showDetails(ctx.Sender().ID, showId)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error fetching TV show from TMDB", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	btn := &telebot.ReplyMarkup{}
+	var rows []telebot.Row
+	for _, status := range utils.WatchStatuses {
+		if status == trackedShow.WatchStatus {
+			continue
+		}
+		rows = append(rows, btn.Row(btn.Data(utils.WatchStatusLabel(status), "", fmt.Sprintf("tv|set_status|%v-%v", showId, status))))
+	}
+	btn.Inline(rows...)
+
+	text := fmt.Sprintf(messages.WatchStatusPrompt, tvShow.Name, utils.WatchStatusLabel(trackedShow.WatchStatus))
+	if _, err = ctx.Bot().Send(ctx.Chat(), text, btn, telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send watch status menu", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	return ctx.Respond()
+}
+
+// handleSetStatus stores a watch status picked by hand, data looks like <show_api_id>-<watch_status>
+func (h *TVHandler) handleSetStatus(ctx telebot.Context, data string) error {
+	const op = "tv.handleSetStatus"
+	h.app.Logger.Info(op, ctx, "Changing watch status of TV show", "data", data)
+
+	idPart, status, found := strings.Cut(data, "-")
+	showId, err := strconv.ParseInt(idPart, 10, 64)
+	if !found || err != nil || !slices.Contains(utils.WatchStatuses, status) {
+		h.app.Logger.Warning(op, ctx, "Malformed watch status data", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidWatchStatus})
+	}
+
+	userId := ctx.Sender().ID
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	tracked, err := h.app.Repository.TVShows.TVShowExists(ctxDb, showId, userId)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error checking tracked show", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	if !tracked {
+		h.app.Logger.Info(op, ctx, "TV show is not in watched library", "tv_id", showId)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.NotInLibrary})
+	}
+
+	if err = h.app.Repository.TVShows.UpdateTVShowWatchStatus(ctxDb, showId, userId, status); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to update watch status", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	tvShow, err := h.showDetails(userId, showId)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error fetching TV show from TMDB", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	if err = ctx.Edit(fmt.Sprintf(messages.WatchStatusSaved, tvShow.Name, utils.WatchStatusLabel(status)), telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to edit watch status menu", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "Watch status changed", "tv_id", showId, "watch_status", status)
+	return ctx.Respond()
+}
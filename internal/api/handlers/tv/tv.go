@@ -5,25 +5,42 @@ import (
 	"fmt"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/cache"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database/repository"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/search"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/tv"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/paginators"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
 	"gopkg.in/telebot.v3"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 var (
-	tvCache        = make(map[int64]*cache.Item)
-	pagePointer    = make(map[int64]*int)
-	maxPage        = make(map[int64]int)
-	tvCount        = make(map[int64]int)
-	selectedTvShow = make(map[int64]*tv.TV)
+	tvCache     = make(map[int64]*cache.Item)
+	pagePointer = make(map[int64]*int)
+	maxPage     = make(map[int64]int)
+	tvCount     = make(map[int64]int)
 )
 
+// Shows picked in the season keyboard and seasons opened in the episode keyboard, callbacks of many users read and
+// write them at once
+var (
+	selectionMu    sync.Mutex
+	selectedTvShow = make(map[int64]pickedShow)
+	selectedSeason = make(map[int64]openSeason)
+)
+
+// selectionTTL is how long a picked show or an opened season is reused before TMDB is asked again, so show totals
+// derived from it follow new episodes
+const selectionTTL = 30 * time.Minute
+
+// snoozeDuration is how long a new season alert stays quiet after "Remind me later"
+const snoozeDuration = 3 * 24 * time.Hour
+
 func (h *TVHandler) SearchTV(ctx telebot.Context) error {
 	const op = "tv.SearchTV"
 	h.app.Logger.Info(op, ctx, "TV show search command received")
@@ -46,7 +63,9 @@ func (h *TVHandler) SearchTV(ctx telebot.Context) error {
 		return ctx.Send(messages.InternalError)
 	}
 
-	tvData, err := search.SearchTV(h.app, searchQuery, userId)
+	tmdbCtx, cancel := h.app.TMDBContext()
+	tvData, err := search.SearchTV(tmdbCtx, h.app, searchQuery, userId)
+	cancel()
 	if err != nil {
 		h.app.Logger.Error(op, ctx, "Failed to search TV shows", "error", err.Error())
 		return ctx.Send(messages.InternalError)
@@ -99,13 +118,15 @@ func (h *TVHandler) handleTVDetails(ctx telebot.Context, data string) error {
 		return ctx.Send(messages.InternalError)
 	}
 
-	tvData, err := tv.GetTV(h.app, parsedId, ctx.Sender().ID)
+	tmdbCtx, cancel := h.app.TMDBContext()
+	tvData, err := tv.GetTV(tmdbCtx, h.app, parsedId, ctx.Sender().ID)
+	cancel()
 	if err != nil {
 		h.app.Logger.Error(op, ctx, "Failed to get TV show data from TMDB", "tv_id", parsedId, "error", err.Error())
 		return ctx.Send(messages.InternalError)
 	}
 
-	err = tv.ShowTV(h.app, ctx, tvData, true)
+	err = tv.ShowTV(h.app, ctx, tvData, "tv|back_to_pagination|")
 	if err != nil {
 		h.app.Logger.Error(op, ctx, "Failed to show TV show details", "tv_id", parsedId, "error", err.Error())
 		return ctx.Send(messages.InternalError)
@@ -143,7 +164,9 @@ func (h *TVHandler) handleSelectSeasons(ctx telebot.Context, tvId string) error
 	}
 
 	h.app.Logger.Debug(op, ctx, "Retrieving TV show data from TMDB", "tv_id", TVId)
-	tvShow, err := tv.GetTV(h.app, TVId, ctx.Sender().ID)
+	tmdbCtx, cancel := h.app.TMDBContext()
+	tvShow, err := tv.GetTV(tmdbCtx, h.app, TVId, ctx.Sender().ID)
+	cancel()
 	if err != nil {
 		h.app.Logger.Error(op, ctx, "Error fetching TV show from TMDB", "tv_id", TVId, "error", err.Error())
 		return ctx.Send(messages.InternalError)
@@ -154,24 +177,48 @@ func (h *TVHandler) handleSelectSeasons(ctx telebot.Context, tvId string) error
 			"tv_id", TVId, "name", tvShow.Name, "watched_seasons", watchedSeasons)
 	}
 
-	selectedTvShow[userId] = tvShow
+	pickShow(userId, tvShow)
+
+	watchedEpisodes, err := h.app.Repository.Episodes.GetWatchedEpisodes(ctxDb, userId, int64(TVId))
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error fetching watched episodes", "tv_id", TVId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	episodeCounts := make(map[int32]int32, len(tvShow.SeasonList))
+	for _, season := range tvShow.SeasonList {
+		episodeCounts[season.SeasonNumber] = season.EpisodeCount
+	}
+	watchedCounts := make(map[int32]int32)
+	for _, episode := range watchedEpisodes {
+		watchedCounts[episode.SeasonNumber]++
+	}
 
 	btn := &telebot.ReplyMarkup{}
 	var btnRows []telebot.Row
 
-	for i := int32(1); i <= selectedTvShow[userId].Seasons; i++ {
+	for i := int32(1); i <= tvShow.Seasons; i++ {
 		emoji := getSeasonEmoji(i)
 
 		if i <= int32(watchedSeasons) {
 			emoji = fmt.Sprintf("✅ %s", emoji)
+		} else if watchedCounts[i] > 0 {
+			emoji = fmt.Sprintf("▶️ %s (%d/%d)", emoji, watchedCounts[i], episodeCounts[i])
 		}
 
-		btnRows = append(btnRows, btn.Row(btn.Data(emoji, "", fmt.Sprintf("tv|watched|%v", i))))
+		btnRows = append(btnRows, btn.Row(
+			btn.Data(emoji, "", fmt.Sprintf("tv|watched|%v", i)),
+			btn.Data("📝 Episodes", "", fmt.Sprintf("tv|episodes|%v-%v", tvShow.Id, i)),
+		))
+	}
+
+	if len(watchedEpisodes) > 0 {
+		btnRows = append(btnRows, btn.Row(btn.Data("⭐ Rate show", "", fmt.Sprintf("tv|rate|%v-0", tvShow.Id))))
 	}
 
 	btn.Inline(btnRows...)
 
-	_, err = ctx.Bot().Send(ctx.Chat(), "How many seasons have you watched?", btn)
+	_, err = ctx.Bot().Send(ctx.Chat(), "How many seasons have you watched?\nPick an earlier season to lower your progress, use 📝 to mark single episodes", btn)
 	if err != nil {
 		h.app.Logger.Error(op, ctx, "Failed to send season selection keyboard", "error", err.Error())
 		return ctx.Send(messages.InternalError)
@@ -185,31 +232,28 @@ func (h *TVHandler) handleWatched(ctx telebot.Context, data string) error {
 	const op = "tv.handleWatched"
 	h.app.Logger.Info(op, ctx, "Processing TV show watch status update", "season_number", data)
 
-	// Use a more appropriate timeout for the transaction
-	ctxDb, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	h.app.Logger.Debug(op, ctx, "Starting database transaction")
-	tx, err := h.app.Repository.BeginTx(ctxDb)
-	if err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to begin transaction", "error", err.Error())
-		return ctx.Send(messages.InternalError)
-	}
-	defer tx.Rollback(ctxDb)
-
 	userId := ctx.Sender().ID
-	tvShow, ok := selectedTvShow[userId]
+	showId, ok := pickedShowId(userId)
 	if !ok {
 		h.app.Logger.Error(op, ctx, "No selected TV show found for user")
 		return ctx.Send(messages.InternalError)
 	}
 
+	tvShow, err := h.showDetails(userId, showId)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error fetching TV show from TMDB", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
 	seasonNum, err := strconv.Atoi(data)
 	if err != nil {
 		h.app.Logger.Error(op, ctx, "Failed to parse season number", "data", data, "error", err.Error())
 		return ctx.Send(messages.InvalidSeason)
 	}
 
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
 	h.app.Logger.Debug(op, ctx, "Checking if user has already watched any seasons",
 		"tv_id", tvShow.Id, "name", tvShow.Name)
 	watchedSeasons, err := h.app.Repository.TVShows.GetWatchedSeasons(ctxDb, tvShow.Id, userId)
@@ -218,137 +262,44 @@ func (h *TVHandler) handleWatched(ctx telebot.Context, data string) error {
 		return ctx.Send(messages.InternalError)
 	}
 
-	if int32(seasonNum) <= watchedSeasons {
-		h.app.Logger.Info(op, ctx, "User has already watched this season",
+	if int32(seasonNum) == watchedSeasons {
+		h.app.Logger.Info(op, ctx, "User is already at this season",
 			"season", seasonNum, "watched_up_to", watchedSeasons)
 		return ctx.Send(messages.WatchedSeason)
 	}
-
-	// Get existing show data if any seasons were previously watched
-	var existingEpisodes, existingRuntime int32
-	if watchedSeasons > 0 {
-		h.app.Logger.Debug(op, ctx, "Fetching existing TV show data", "tv_id", tvShow.Id)
-		existingShow, err := h.app.Repository.TVShows.GetUserTVShow(ctxDb, tvShow.Id, userId)
-		if err != nil {
-			h.app.Logger.Error(op, ctx, "Error fetching existing TV show data", "error", err.Error())
-			return ctx.Send(messages.InternalError)
-		}
-		existingEpisodes = existingShow.Episodes
-		existingRuntime = existingShow.Runtime
-	}
-
-	// Process seasons concurrently
-	type seasonResult struct {
-		Episodes int32
-		Runtime  int32
-		Error    error
-	}
-
-	numSeasonsToProcess := seasonNum - int(watchedSeasons)
-	resultChan := make(chan seasonResult, numSeasonsToProcess)
-
-	// Create a context with a reasonable timeout for all API calls
-	fetchCtx, fetchCancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer fetchCancel()
-
-	h.app.Logger.Debug(op, ctx, "Starting concurrent fetch of season data",
-		"seasons_to_process", numSeasonsToProcess)
-	// Start goroutines for each season
-	for i := int(watchedSeasons) + 1; i <= seasonNum; i++ {
-		go func(seasonIndex int) {
-			var result seasonResult
-
-			tvSeason, err := tv.GetSeason(h.app, int(tvShow.Id), seasonIndex, userId)
-			if err != nil {
-				result.Error = fmt.Errorf("error fetching season %d: %v", seasonIndex, err)
-				resultChan <- result
-				return
-			}
-
-			for _, episode := range tvSeason.Episodes {
-				result.Episodes++
-				result.Runtime += episode.Runtime
-			}
-
-			resultChan <- result
-		}(i)
-	}
-
-	// Collect results
-	h.app.Logger.Debug(op, ctx, "Collecting season data results")
-	var newEpisodes, newRuntime int32
-	for i := 0; i < numSeasonsToProcess; i++ {
-		select {
-		case result := <-resultChan:
-			if result.Error != nil {
-				h.app.Logger.Error(op, ctx, "Error while fetching season data", "error", result.Error.Error())
-				return ctx.Send(messages.InternalError)
-			}
-			newEpisodes += result.Episodes
-			newRuntime += result.Runtime
-		case <-fetchCtx.Done():
-			h.app.Logger.Error(op, ctx, "Timed out while fetching seasons")
-			return ctx.Send(messages.InternalError)
-		}
-	}
-
-	// Total episodes and runtime (existing + new)
-	totalEpisodes := existingEpisodes + newEpisodes
-	totalRuntime := existingRuntime + newRuntime
-
-	h.app.Logger.Debug(op, ctx, "Updating database with TV show data",
-		"seasons", seasonNum, "episodes", totalEpisodes, "runtime", totalRuntime)
-	// Use a single database operation - update or create
-	var dbErr error
-	if watchedSeasons > 0 {
-		dbErr = tx.Repos.TVShows.UpdateTVShow(ctxDb, database.UpdateTVShowParams{
-			ApiID:    tvShow.Id,
-			UserID:   userId,
-			Seasons:  int32(seasonNum),
-			Episodes: totalEpisodes,
-			Runtime:  totalRuntime,
-		})
-	} else {
-		dbErr = tx.Repos.TVShows.CreateTVShow(ctxDb, database.CreateTVShowParams{
-			UserID:   userId,
-			ApiID:    tvShow.Id,
-			Name:     tvShow.Name,
-			Seasons:  int32(seasonNum),
-			Episodes: newEpisodes,
-			Runtime:  newRuntime,
-			Status:   tvShow.Status,
-		})
+	if int32(seasonNum) < watchedSeasons {
+		return h.lowerSeasons(ctx, tvShow, int32(seasonNum))
 	}
 
-	if dbErr != nil {
-		h.app.Logger.Error(op, ctx, "Database operation failed", "error", dbErr.Error())
+	// Seasons up to the selected one are recorded episode by episode, partly watched ones get their missing episodes
+	h.app.Logger.Debug(op, ctx, "Fetching season data",
+		"from_season", watchedSeasons+1, "to_season", seasonNum)
+	seasons, err := h.fetchSeasons(userId, tvShow.Id, int(watchedSeasons)+1, seasonNum)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error while fetching season data", "error", err.Error())
 		return ctx.Send(messages.InternalError)
 	}
 
-	h.app.Logger.Debug(op, ctx, "Removing TV show from watchlist if present", "tv_id", tvShow.Id)
-	if err = tx.Repos.Watchlists.DeleteWatchlist(ctxDb, tvShow.Id, userId); err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to delete from watchlist", "error", err.Error())
+	err = h.updateEpisodeWatches(ctx, tvShow.Id, func(ctxDb context.Context, repos *repository.ReposTx) error {
+		return recordSeasons(ctxDb, repos, userId, tvShow.Id, seasons)
+	})
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to record watched seasons", "error", err.Error())
 		return ctx.Send(messages.InternalError)
 	}
 
-	h.app.Logger.Debug(op, ctx, "Committing transaction")
-	if err = tx.Commit(ctxDb); err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to commit transaction", "error", err.Error())
-		return ctx.Send(messages.InternalError)
-	}
+	ctxDb, cancel = context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
 
-	var episodesCount, runtimeCount int32
-	if watchedSeasons > 0 {
-		episodesCount = totalEpisodes
-		runtimeCount = totalRuntime
-	} else {
-		episodesCount = newEpisodes
-		runtimeCount = newRuntime
+	trackedShow, err := h.app.Repository.TVShows.GetUserTVShow(ctxDb, tvShow.Id, userId)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error fetching updated TV show data", "error", err.Error())
+		return ctx.Send(messages.InternalError)
 	}
 
 	message := fmt.Sprintf(
-		"The TV Show added as watched with below data:\nName: %v\nSeasons: %v\nEpisodes: %v\nRuntime: %v minutes",
-		tvShow.Name, seasonNum, episodesCount, runtimeCount,
+		"The TV Show added as watched with below data:\nName: %v\nSeasons: %v\nEpisodes: %v\nRuntime: %v minutes\nStatus: %v",
+		tvShow.Name, trackedShow.Seasons, trackedShow.Episodes, trackedShow.Runtime, utils.WatchStatusLabel(trackedShow.WatchStatus),
 	)
 
 	if _, err = ctx.Bot().Send(ctx.Chat(), message, telebot.ModeMarkdown); err != nil {
@@ -356,11 +307,103 @@ func (h *TVHandler) handleWatched(ctx telebot.Context, data string) error {
 		return ctx.Send(messages.InternalError)
 	}
 
+	// A finished show is rated as a whole, otherwise the season just completed is
+	var ratedSeason int32
+	if trackedShow.Seasons < tvShow.Seasons {
+		ratedSeason = int32(seasonNum)
+	}
+	if err = h.ratingHandler.PromptRating(ctx, constants.TVShowType, tvShow.Id, ratedSeason, tvShow.Name); err != nil {
+		h.app.Logger.Warning(op, ctx, "Failed to prompt for a rating", "tv_id", tvShow.Id, "error", err.Error())
+	}
+
 	h.app.Logger.Info(op, ctx, "TV show watch status updated successfully",
-		"name", tvShow.Name, "seasons", seasonNum, "episodes", episodesCount, "runtime", runtimeCount)
+		"name", tvShow.Name, "seasons", trackedShow.Seasons, "episodes", trackedShow.Episodes, "runtime", trackedShow.Runtime)
 	return nil
 }
 
+func (h *TVHandler) handleEpisodeWatched(ctx telebot.Context, data string) error {
+	const op = "tv.handleEpisodeWatched"
+	h.app.Logger.Info(op, ctx, "Marking reminded episode as watched", "data", data)
+
+	// data format: <show_api_id>-<season_number>-<episode_number>
+	parts := strings.Split(data, "-")
+	if len(parts) != 3 {
+		h.app.Logger.Warning(op, ctx, "Malformed episode data", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidEpisode})
+	}
+
+	showId, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to parse TV show ID", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidEpisode})
+	}
+	seasonNum, err := strconv.Atoi(parts[1])
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to parse season number", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidEpisode})
+	}
+	episodeNum, err := strconv.Atoi(parts[2])
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to parse episode number", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidEpisode})
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	var runtime int32
+	schedule, err := h.app.Repository.Episodes.GetEpisodeSchedule(ctxDb, showId, int32(seasonNum), int32(episodeNum))
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error fetching episode schedule", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	if schedule.Runtime != nil {
+		runtime = *schedule.Runtime
+	}
+
+	userId := ctx.Sender().ID
+	var marked bool
+	err = h.updateEpisodeWatches(ctx, showId, func(ctxDb context.Context, repos *repository.ReposTx) error {
+		var err error
+		marked, err = repos.Episodes.MarkEpisodeReminderWatched(ctxDb, database.MarkEpisodeReminderWatchedParams{
+			UserID:        userId,
+			ShowApiID:     showId,
+			SeasonNumber:  int32(seasonNum),
+			EpisodeNumber: int32(episodeNum),
+		})
+		if err != nil || !marked {
+			return err
+		}
+
+		_, err = repos.Episodes.MarkEpisodeWatched(ctxDb, database.MarkEpisodeWatchedParams{
+			UserID:        userId,
+			ShowApiID:     showId,
+			SeasonNumber:  int32(seasonNum),
+			EpisodeNumber: int32(episodeNum),
+			Runtime:       runtime,
+		})
+		return err
+	})
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to mark reminded episode as watched", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	if !marked {
+		h.app.Logger.Info(op, ctx, "Episode already marked as watched",
+			"tv_id", showId, "season", seasonNum, "episode", episodeNum)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.EpisodeAlreadyWatched})
+	}
+
+	if _, err = ctx.Bot().EditReplyMarkup(ctx.Message(), nil); err != nil {
+		h.app.Logger.Warning(op, ctx, "Failed to remove reminder buttons", "error", err.Error())
+	}
+
+	h.app.Logger.Info(op, ctx, "Reminded episode marked as watched",
+		"tv_id", showId, "season", seasonNum, "episode", episodeNum)
+	return ctx.Respond(&telebot.CallbackResponse{Text: messages.EpisodeMarkedWatched})
+}
+
 func (h *TVHandler) handleWatchlist(ctx telebot.Context, tvId string) error {
 	const op = "tv.handleWatchlist"
 	h.app.Logger.Info(op, ctx, "Adding TV show to watchlist", "tv_id", tvId)
@@ -372,7 +415,9 @@ func (h *TVHandler) handleWatchlist(ctx telebot.Context, tvId string) error {
 	}
 
 	h.app.Logger.Debug(op, ctx, "Retrieving TV show data from TMDB", "tv_id", tvShowId)
-	tvShow, err := tv.GetTV(h.app, tvShowId, ctx.Sender().ID)
+	tmdbCtx, cancel := h.app.TMDBContext()
+	tvShow, err := tv.GetTV(tmdbCtx, h.app, tvShowId, ctx.Sender().ID)
+	cancel()
 	if err != nil {
 		h.app.Logger.Error(op, ctx, "Failed to get TV show data", "error", err.Error())
 		return ctx.Send(messages.InternalError)
@@ -384,6 +429,7 @@ func (h *TVHandler) handleWatchlist(ctx telebot.Context, tvId string) error {
 		Type:      constants.TVShowType,
 		Title:     tvShow.Name,
 		Image:     &tvShow.PosterPath,
+		Runtime:   tv.EpisodeRuntime(tvShow),
 	}
 
 	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
@@ -405,6 +451,57 @@ func (h *TVHandler) handleWatchlist(ctx telebot.Context, tvId string) error {
 	return nil
 }
 
+func (h *TVHandler) handleSnooze(ctx telebot.Context, tvId string) error {
+	const op = "tv.handleSnooze"
+	h.app.Logger.Info(op, ctx, "Snoozing new season alert", "tv_id", tvId)
+
+	showId, err := strconv.ParseInt(tvId, 10, 64)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to parse TV show ID", "tv_id", tvId, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	snoozed, err := h.app.Repository.TVShows.SnoozeShowNotification(ctxDb, ctx.Sender().ID, showId, time.Now().Add(snoozeDuration))
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to snooze alert", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	if !snoozed {
+		h.app.Logger.Warning(op, ctx, "No alert state found to snooze", "tv_id", showId)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.NothingToSnooze})
+	}
+
+	h.app.Logger.Info(op, ctx, "New season alert snoozed", "tv_id", showId, "duration", snoozeDuration.String())
+	return ctx.Respond(&telebot.CallbackResponse{Text: messages.AlertSnoozed})
+}
+
+// handleUnwatchlist removes a show from the watchlist, it backs the button of watchlist alerts
+func (h *TVHandler) handleUnwatchlist(ctx telebot.Context, tvId string) error {
+	const op = "tv.handleUnwatchlist"
+	h.app.Logger.Info(op, ctx, "Removing TV show from watchlist", "tv_id", tvId)
+
+	showId, err := strconv.ParseInt(tvId, 10, 64)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to parse TV show ID", "tv_id", tvId, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	if err = h.app.Repository.Watchlists.DeleteWatchlist(ctxDb, showId, ctx.Sender().ID, constants.TVShowType); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to delete from watchlist", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "TV show removed from watchlist", "tv_id", showId)
+	return ctx.Respond(&telebot.CallbackResponse{Text: messages.RemovedFromWatchlist})
+}
+
 func (h *TVHandler) handleBackToPagination(ctx telebot.Context) error {
 	const op = "tv.handleBackToPagination"
 	h.app.Logger.Info(op, ctx, "Returning to paginated search results")
@@ -530,6 +627,42 @@ func (h *TVHandler) TVCallback(ctx telebot.Context) error {
 	case "watchlist":
 		return h.handleWatchlist(ctx, data)
 
+	case "watchlisted":
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.AlreadyWatchlisted})
+
+	case "episode_watched":
+		return h.handleEpisodeWatched(ctx, data)
+
+	case "episodes":
+		return h.handleSeasonEpisodes(ctx, data)
+
+	case "toggle_episode":
+		return h.handleToggleEpisode(ctx, data)
+
+	case "watch_season":
+		return h.handleWatchSeason(ctx, data)
+
+	case "rate":
+		return h.handleRate(ctx, data)
+
+	case "status_menu":
+		return h.handleStatusMenu(ctx, data)
+
+	case "set_status":
+		return h.handleSetStatus(ctx, data)
+
+	case "unwatch":
+		return h.handleUnwatch(ctx, data)
+
+	case "undo":
+		return h.handleUndo(ctx, data)
+
+	case "snooze":
+		return h.handleSnooze(ctx, data)
+
+	case "unwatchlist":
+		return h.handleUnwatchlist(ctx, data)
+
 	case "back_to_pagination":
 		return h.handleBackToPagination(ctx)
 
@@ -3,14 +3,31 @@ package tv
 import (
 	"github.com/erkinov-wtf/movie-manager-bot/internal/api/interfaces"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/tv"
+	"time"
 )
 
 type TVHandler struct {
-	app *app.App
+	app           *app.App
+	ratingHandler interfaces.RatingInterface
 }
 
-func NewTVHandler(app *app.App) interfaces.TVInterface {
+func NewTVHandler(app *app.App, ratingHandler interfaces.RatingInterface) interfaces.TVInterface {
 	return &TVHandler{
-		app: app,
+		app:           app,
+		ratingHandler: ratingHandler,
 	}
 }
+
+// pickedShow is the show of a user's season keyboard
+type pickedShow struct {
+	*tv.TV
+	expires time.Time
+}
+
+// openSeason is the season shown in a user's episode keyboard
+type openSeason struct {
+	*tv.Season
+	showId  int64
+	expires time.Time
+}
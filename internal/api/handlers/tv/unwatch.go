@@ -0,0 +1,180 @@
+package tv
+
+import (
+	"context"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/cache"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database/repository"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/tv"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
+	"gopkg.in/telebot.v3"
+	"strconv"
+	"time"
+)
+
+// handleUnwatch removes a show with its watched episodes and ratings from the watched library, it can be undone for a while
+func (h *TVHandler) handleUnwatch(ctx telebot.Context, data string) error {
+	const op = "tv.handleUnwatch"
+	h.app.Logger.Info(op, ctx, "Removing TV show from watched library", "tv_id", data)
+
+	showId, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to parse TV show ID", "tv_id", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	userId := ctx.Sender().ID
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	tracked, err := h.app.Repository.TVShows.TVShowExists(ctxDb, showId, userId)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error checking tracked show", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	if !tracked {
+		h.app.Logger.Info(op, ctx, "TV show is not in watched library", "tv_id", showId)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.NotInLibrary})
+	}
+
+	tvShow, err := h.showDetails(userId, showId)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Error fetching TV show from TMDB", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	snapshot := cache.UndoSnapshot{Title: tvShow.Name}
+	err = h.updateEpisodeWatches(ctx, showId, func(ctxDb context.Context, repos *repository.ReposTx) error {
+		var err error
+		// Every season number is above -1, specials included
+		if snapshot.Episodes, err = repos.Episodes.DeleteEpisodeWatchesAfter(ctxDb, userId, showId, -1); err != nil {
+			return fmt.Errorf("error deleting episode watches: %w", err)
+		}
+		if snapshot.Ratings, err = repos.Ratings.DeleteTitleRatings(ctxDb, userId, showId, constants.TVShowType); err != nil {
+			return fmt.Errorf("error deleting ratings: %w", err)
+		}
+		if err = repos.TVShows.SoftDeleteTVShow(ctxDb, showId, userId); err != nil {
+			return fmt.Errorf("error deleting show: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to remove TV show", "tv_id", showId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Cache.UndoCache.Put(userId, constants.TVShowType, showId, snapshot)
+
+	_, err = ctx.Bot().Send(ctx.Chat(), fmt.Sprintf(messages.ShowUnwatched, tvShow.Name), undoKeyboard(showId), telebot.ModeMarkdown)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send confirmation message", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "TV show removed from watched library",
+		"tv_id", showId, "name", tvShow.Name, "episodes", len(snapshot.Episodes), "ratings", len(snapshot.Ratings))
+	return ctx.Respond()
+}
+
+// lowerSeasons takes back the watched episodes of the seasons after the given one, it can be undone for a while
+func (h *TVHandler) lowerSeasons(ctx telebot.Context, tvShow *tv.TV, seasonNum int32) error {
+	const op = "tv.lowerSeasons"
+	h.app.Logger.Info(op, ctx, "Lowering watched seasons", "tv_id", tvShow.Id, "season", seasonNum)
+
+	userId := ctx.Sender().ID
+	snapshot := cache.UndoSnapshot{Title: tvShow.Name}
+	err := h.updateEpisodeWatches(ctx, tvShow.Id, func(ctxDb context.Context, repos *repository.ReposTx) error {
+		var err error
+		snapshot.Episodes, err = repos.Episodes.DeleteEpisodeWatchesAfter(ctxDb, userId, tvShow.Id, seasonNum)
+		return err
+	})
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to lower watched seasons", "tv_id", tvShow.Id, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Cache.UndoCache.Put(userId, constants.TVShowType, tvShow.Id, snapshot)
+
+	_, err = ctx.Bot().Send(ctx.Chat(), fmt.Sprintf(messages.SeasonsLowered, tvShow.Name, seasonNum), undoKeyboard(tvShow.Id), telebot.ModeMarkdown)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send confirmation message", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "Watched seasons lowered",
+		"tv_id", tvShow.Id, "season", seasonNum, "removed_episodes", len(snapshot.Episodes))
+	return nil
+}
+
+// handleUndo puts back the episodes and ratings taken by the latest removal or lowering of a show
+func (h *TVHandler) handleUndo(ctx telebot.Context, data string) error {
+	const op = "tv.handleUndo"
+	h.app.Logger.Info(op, ctx, "Restoring TV show progress", "tv_id", data)
+
+	showId, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to parse TV show ID", "tv_id", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	userId := ctx.Sender().ID
+	snapshot, ok := h.app.Cache.UndoCache.Take(userId, constants.TVShowType, showId)
+	if !ok {
+		h.app.Logger.Info(op, ctx, "Nothing to undo", "tv_id", showId)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.UndoExpired})
+	}
+
+	// The show totals, and the show itself after a removal, come back with the episodes
+	err = h.updateEpisodeWatches(ctx, showId, func(ctxDb context.Context, repos *repository.ReposTx) error {
+		for _, episode := range snapshot.Episodes {
+			err := repos.Episodes.RestoreEpisodeWatch(ctxDb, database.RestoreEpisodeWatchParams{
+				UserID:        userId,
+				ShowApiID:     showId,
+				SeasonNumber:  episode.SeasonNumber,
+				EpisodeNumber: episode.EpisodeNumber,
+				Runtime:       episode.Runtime,
+				WatchedAt:     episode.WatchedAt,
+			})
+			if err != nil {
+				return fmt.Errorf("error restoring S%02dE%02d: %w", episode.SeasonNumber, episode.EpisodeNumber, err)
+			}
+		}
+
+		for _, rating := range snapshot.Ratings {
+			err := repos.Ratings.RestoreRating(ctxDb, database.RestoreRatingParams{
+				UserID:       userId,
+				ApiID:        showId,
+				Type:         constants.TVShowType,
+				SeasonNumber: rating.SeasonNumber,
+				Rating:       rating.Rating,
+				Review:       rating.Review,
+			})
+			if err != nil {
+				return fmt.Errorf("error restoring rating: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to restore TV show progress", "tv_id", showId, "error", err.Error())
+		// The snapshot stays available so the user can try again
+		h.app.Cache.UndoCache.Put(userId, constants.TVShowType, showId, snapshot)
+		return ctx.Send(messages.InternalError)
+	}
+
+	if err = ctx.Edit(fmt.Sprintf(messages.TitleRestored, snapshot.Title), telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Warning(op, ctx, "Failed to update confirmation message", "error", err.Error())
+	}
+
+	h.app.Logger.Info(op, ctx, "TV show progress restored",
+		"tv_id", showId, "episodes", len(snapshot.Episodes), "ratings", len(snapshot.Ratings))
+	return ctx.Respond()
+}
+
+func undoKeyboard(showId int64) *telebot.ReplyMarkup {
+	btn := &telebot.ReplyMarkup{}
+	btn.Inline(btn.Row(btn.Data("↩️ Undo", "", fmt.Sprintf("tv|undo|%v", showId))))
+	return btn
+}
@@ -0,0 +1,353 @@
+package watchlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"github.com/jackc/pgx/v5"
+	"gopkg.in/telebot.v3"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// entryRef points at a watchlist entry in callback data as <type>-<api_id>
+type entryRef struct {
+	showType string
+	apiId    int64
+}
+
+func (r entryRef) String() string {
+	return fmt.Sprintf("%s-%d", r.showType, r.apiId)
+}
+
+// parseEntryRef reads an entry from data that looks like <type>-<api_id>[-<rest>] and returns the rest
+func parseEntryRef(data string) (entryRef, string, error) {
+	parts := strings.SplitN(data, "-", 3)
+	if len(parts) < 2 || (parts[0] != constants.MovieType && parts[0] != constants.TVShowType) {
+		return entryRef{}, "", fmt.Errorf("malformed entry data: %q", data)
+	}
+
+	apiId, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return entryRef{}, "", fmt.Errorf("malformed api id: %w", err)
+	}
+
+	var rest string
+	if len(parts) == 3 {
+		rest = parts[2]
+	}
+	return entryRef{showType: parts[0], apiId: apiId}, rest, nil
+}
+
+// entryEditor renders an entry with the buttons to change it, moving is offered in the manual order only since
+// any other order would hide the move
+func entryEditor(entry database.GetWatchlistEntryRow, ref entryRef, sortBy string) (string, *telebot.ReplyMarkup) {
+	note := messages.WatchlistNoNote
+	if entry.Note != nil {
+		note = utils.EscapeMarkdown(*entry.Note)
+	}
+	text := fmt.Sprintf(messages.WatchlistEntryEditor, entry.Title, utils.WatchlistPriorityLabel(entry.Priority), note)
+
+	btn := &telebot.ReplyMarkup{}
+	priorityRow := telebot.Row{}
+	for _, priority := range utils.WatchlistPriorities {
+		label := utils.WatchlistPriorityLabel(priority)
+		if priority == entry.Priority {
+			label = "✅ " + label
+		}
+		priorityRow = append(priorityRow, btn.Data(label, "", fmt.Sprintf("watchlist|priority|%s-%s", ref, priority)))
+	}
+
+	noteRow := btn.Row(btn.Data("🗒 Write note", "", fmt.Sprintf("watchlist|note|%s", ref)))
+	if entry.Note != nil {
+		noteRow = append(noteRow, btn.Data("🧹 Clear note", "", fmt.Sprintf("watchlist|clear_note|%s", ref)))
+	}
+
+	rows := []telebot.Row{priorityRow, noteRow}
+	if sortBy == constants.WatchlistSortManual {
+		rows = append(rows, btn.Row(
+			btn.Data("⬆️ Move up", "", fmt.Sprintf("watchlist|move|%s-up", ref)),
+			btn.Data("⬇️ Move down", "", fmt.Sprintf("watchlist|move|%s-down", ref)),
+		))
+	}
+	rows = append(rows, btn.Row(btn.Data("🔙 Back to watchlist", "", "watchlist|view|-")))
+	btn.Inline(rows...)
+
+	return text, btn
+}
+
+// editEntry renders the editor of an entry into the message the callback came from
+func (h *WatchlistHandler) editEntry(ctx telebot.Context, ref entryRef) error {
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	entry, err := h.app.Repository.Watchlists.GetWatchlistEntry(ctxDb, ctx.Sender().ID, ref.apiId, ref.showType)
+	if err != nil {
+		return err
+	}
+
+	text, btn := entryEditor(entry, ref, currentView(ctx.Sender().ID).sortBy)
+	if err = ctx.Edit(text, btn, telebot.ModeMarkdown); err != nil && !strings.Contains(err.Error(), "message is not modified") {
+		return fmt.Errorf("error editing entry editor: %w", err)
+	}
+	return nil
+}
+
+// respondEntryError answers a callback on an entry that failed to load or change
+func (h *WatchlistHandler) respondEntryError(op string, ctx telebot.Context, ref entryRef, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.app.Logger.Info(op, ctx, "Entry is not on watchlist", "type", ref.showType, "api_id", ref.apiId)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.NotOnWatchlist})
+	}
+	h.app.Logger.Error(op, ctx, "Failed to update watchlist entry", "type", ref.showType, "api_id", ref.apiId, "error", err.Error())
+	return ctx.Send(messages.InternalError)
+}
+
+// handleEdit opens the editor of an entry in place of the watchlist view, data looks like <type>-<api_id>
+func (h *WatchlistHandler) handleEdit(ctx telebot.Context, data string) error {
+	const op = "watchlist.handleEdit"
+	h.app.Logger.Info(op, ctx, "Opening watchlist entry editor", "data", data)
+
+	ref, _, err := parseEntryRef(data)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed entry data", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	if err = h.editEntry(ctx, ref); err != nil {
+		return h.respondEntryError(op, ctx, ref, err)
+	}
+	return ctx.Respond()
+}
+
+// handlePriority stores the priority of an entry, data looks like <type>-<api_id>-<priority>
+func (h *WatchlistHandler) handlePriority(ctx telebot.Context, data string) error {
+	const op = "watchlist.handlePriority"
+	h.app.Logger.Info(op, ctx, "Changing priority of watchlist entry", "data", data)
+
+	ref, priority, err := parseEntryRef(data)
+	if err != nil || !slices.Contains(utils.WatchlistPriorities, priority) {
+		h.app.Logger.Warning(op, ctx, "Malformed priority data", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidPriority})
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	found, err := h.app.Repository.Watchlists.UpdateWatchlistPriority(ctxDb, ctx.Sender().ID, ref.apiId, ref.showType, priority)
+	if err == nil && !found {
+		err = pgx.ErrNoRows
+	}
+	if err == nil {
+		err = h.editEntry(ctx, ref)
+	}
+	if err != nil {
+		return h.respondEntryError(op, ctx, ref, err)
+	}
+
+	h.app.Logger.Info(op, ctx, "Watchlist priority changed", "type", ref.showType, "api_id", ref.apiId, "priority", priority)
+	return ctx.Respond(&telebot.CallbackResponse{Text: fmt.Sprintf(messages.WatchlistPrioritySaved, utils.WatchlistPriorityLabel(priority))})
+}
+
+// handleNote asks for the note of an entry, the editor stays open above the prompt
+func (h *WatchlistHandler) handleNote(ctx telebot.Context, data string) error {
+	const op = "watchlist.handleNote"
+	h.app.Logger.Info(op, ctx, "Asking for watchlist note", "data", data)
+
+	ref, _, err := parseEntryRef(data)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed entry data", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	entry, err := h.app.Repository.Watchlists.GetWatchlistEntry(ctxDb, ctx.Sender().ID, ref.apiId, ref.showType)
+	if err != nil {
+		return h.respondEntryError(op, ctx, ref, err)
+	}
+
+	h.app.Cache.UserCache.SetNoteStart(ctx.Sender().ID, ref.showType, ref.apiId)
+
+	if _, err = ctx.Bot().Send(ctx.Chat(), fmt.Sprintf(messages.WatchlistNotePrompt, entry.Title, maxNoteLength), telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send note prompt", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	return ctx.Respond()
+}
+
+// HandleNoteInput stores the text the user sent as the note of an entry, see cache.NoteState
+func (h *WatchlistHandler) HandleNoteInput(ctx telebot.Context) error {
+	const op = "watchlist.HandleNoteInput"
+	userId := ctx.Sender().ID
+	h.app.Logger.Info(op, ctx, "Processing watchlist note input")
+
+	_, userCache := h.app.Cache.UserCache.Fetch(userId)
+	state := userCache.NoteState
+	ref := entryRef{showType: state.ShowType, apiId: state.ApiId}
+
+	note := strings.TrimSpace(ctx.Message().Text)
+	if note == "" {
+		h.app.Logger.Warning(op, ctx, "Empty note received")
+		return ctx.Send(messages.WatchlistNoteEmpty)
+	}
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		note = string([]rune(note)[:maxNoteLength])
+	}
+
+	h.app.Cache.UserCache.SetNoteDone(userId)
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	found, err := h.app.Repository.Watchlists.UpdateWatchlistNote(ctxDb, userId, ref.apiId, ref.showType, &note)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to save note", "type", ref.showType, "api_id", ref.apiId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	if !found {
+		h.app.Logger.Warning(op, ctx, "Note for a missing watchlist entry", "type", ref.showType, "api_id", ref.apiId)
+		return ctx.Send(messages.NotOnWatchlist)
+	}
+
+	entry, err := h.app.Repository.Watchlists.GetWatchlistEntry(ctxDb, userId, ref.apiId, ref.showType)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to fetch watchlist entry", "type", ref.showType, "api_id", ref.apiId, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	text, btn := entryEditor(entry, ref, currentView(userId).sortBy)
+	if _, err = ctx.Bot().Send(ctx.Chat(), messages.WatchlistNoteSaved+"\n\n"+text, btn, telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to send entry editor", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "Watchlist note saved", "type", ref.showType, "api_id", ref.apiId, "length", utf8.RuneCountInString(note))
+	return nil
+}
+
+func (h *WatchlistHandler) handleClearNote(ctx telebot.Context, data string) error {
+	const op = "watchlist.handleClearNote"
+	h.app.Logger.Info(op, ctx, "Clearing watchlist note", "data", data)
+
+	ref, _, err := parseEntryRef(data)
+	if err != nil {
+		h.app.Logger.Warning(op, ctx, "Malformed entry data", "data", data, "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	found, err := h.app.Repository.Watchlists.UpdateWatchlistNote(ctxDb, ctx.Sender().ID, ref.apiId, ref.showType, nil)
+	if err == nil && !found {
+		err = pgx.ErrNoRows
+	}
+	if err == nil {
+		err = h.editEntry(ctx, ref)
+	}
+	if err != nil {
+		return h.respondEntryError(op, ctx, ref, err)
+	}
+
+	h.app.Logger.Info(op, ctx, "Watchlist note cleared", "type", ref.showType, "api_id", ref.apiId)
+	return ctx.Respond(&telebot.CallbackResponse{Text: messages.WatchlistNoteCleared})
+}
+
+// handleMove swaps an entry with its neighbour in the manual order of the current view, data looks like
+// <type>-<api_id>-<up|down>
+func (h *WatchlistHandler) handleMove(ctx telebot.Context, data string) error {
+	const op = "watchlist.handleMove"
+	h.app.Logger.Info(op, ctx, "Moving watchlist entry", "data", data)
+
+	ref, direction, err := parseEntryRef(data)
+	if err != nil || (direction != "up" && direction != "down") {
+		h.app.Logger.Warning(op, ctx, "Malformed move data", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	moved, err := h.moveEntry(ctx.Sender().ID, currentView(ctx.Sender().ID), ref, direction == "up")
+	if err != nil {
+		return h.respondEntryError(op, ctx, ref, err)
+	}
+	if !moved {
+		h.app.Logger.Debug(op, ctx, "Entry is at the edge of the view", "type", ref.showType, "api_id", ref.apiId)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.WatchlistMoveEdge})
+	}
+
+	h.app.Logger.Info(op, ctx, "Watchlist entry moved", "type", ref.showType, "api_id", ref.apiId, "direction", direction)
+	return ctx.Respond(&telebot.CallbackResponse{Text: messages.WatchlistMoved})
+}
+
+// moveEntry puts an entry right before or after the next entry of the view in that direction. Entries hidden by the
+// filters of the view keep their places around them, and the whole watchlist is numbered again so positions that
+// ended up equal can't make a move a no-op.
+func (h *WatchlistHandler) moveEntry(userId int64, view watchlistView, ref entryRef, up bool) (bool, error) {
+	ctxDb, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	tx, err := h.app.Repository.BeginTx(ctxDb)
+	if err != nil {
+		return false, fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctxDb)
+
+	entries, err := tx.Repos.Watchlists.GetWatchlistEntries(ctxDb, userId, constants.AllType, constants.AllType, constants.WatchlistSortManual)
+	if err != nil {
+		return false, fmt.Errorf("error fetching watchlist: %w", err)
+	}
+
+	index := slices.IndexFunc(entries, func(e database.GetWatchlistEntriesRow) bool {
+		return e.Type == ref.showType && e.ShowApiID == ref.apiId
+	})
+	if index < 0 {
+		return false, pgx.ErrNoRows
+	}
+
+	inView := func(e database.GetWatchlistEntriesRow) bool {
+		return (view.showType == constants.AllType || e.Type == view.showType) &&
+			(view.priority == constants.AllType || e.Priority == view.priority)
+	}
+	step := 1
+	if up {
+		step = -1
+	}
+	neighbour := -1
+	for i := index + step; i >= 0 && i < len(entries); i += step {
+		if inView(entries[i]) {
+			neighbour = i
+			break
+		}
+	}
+	if neighbour < 0 {
+		return false, nil
+	}
+
+	// Taking the entry out shifts a neighbour below it one place up, so in both directions the entry lands on
+	// the old index of the neighbour
+	entry := entries[index]
+	order := slices.Delete(slices.Clone(entries), index, index+1)
+	order = slices.Insert(order, neighbour, entry)
+
+	for i, e := range order {
+		if e.Position == int32(i+1) {
+			continue
+		}
+		if err = tx.Repos.Watchlists.UpdateWatchlistPosition(ctxDb, e.ID, int32(i+1)); err != nil {
+			return false, fmt.Errorf("error updating position: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctxDb); err != nil {
+		return false, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return true, nil
+}
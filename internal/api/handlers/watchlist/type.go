@@ -15,4 +15,7 @@ func NewWatchlistHandler(app *app.App) interfaces.WatchlistInterface {
 	}
 }
 
-const itemsPerPage = 3
+const (
+	itemsPerPage  = 3
+	maxNoteLength = 200
+)
@@ -0,0 +1,230 @@
+package watchlist
+
+import (
+	"context"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/paginators"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"gopkg.in/telebot.v3"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// watchlistView is how a user looks at the watchlist right now, the entry editor returns to it
+type watchlistView struct {
+	showType string
+	priority string
+	sortBy   string
+	page     int
+}
+
+var (
+	viewsMu sync.Mutex
+	views   = make(map[int64]watchlistView)
+)
+
+var watchlistTypes = []string{constants.AllType, constants.MovieType, constants.TVShowType}
+
+// currentView returns the view of a user, everything is shown in the manual order until they pick something else
+func currentView(userId int64) watchlistView {
+	viewsMu.Lock()
+	defer viewsMu.Unlock()
+
+	if view, ok := views[userId]; ok {
+		return view
+	}
+	return watchlistView{
+		showType: constants.AllType,
+		priority: constants.AllType,
+		sortBy:   constants.WatchlistSortManual,
+		page:     1,
+	}
+}
+
+func saveView(userId int64, view watchlistView) {
+	viewsMu.Lock()
+	defer viewsMu.Unlock()
+	views[userId] = view
+}
+
+// renderView fetches one page of the view, the page is moved back in range when the watchlist got shorter
+func (h *WatchlistHandler) renderView(userId int64, view *watchlistView) (string, *telebot.ReplyMarkup, int, error) {
+	ctxDb, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	entries, err := h.app.Repository.Watchlists.GetWatchlistEntries(ctxDb, userId, view.showType, view.priority, view.sortBy)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("error fetching watchlist: %w", err)
+	}
+
+	totalItems := len(entries)
+	totalPages := (totalItems + itemsPerPage - 1) / itemsPerPage
+	if view.page > totalPages {
+		view.page = totalPages
+	}
+	if view.page < 1 {
+		view.page = 1
+	}
+
+	paginatedWatchlist := paginators.PaginateWatchlist(entries, view.page)
+	response, btn := paginators.GenerateWatchlistResponse(&paginatedWatchlist, view.page, totalPages, totalItems, view.showType, view.priority, view.sortBy)
+
+	return response, btn, totalItems, nil
+}
+
+// handleOpenWatchlist shows the first page of one type of the watchlist in the message of the type selection
+func (h *WatchlistHandler) handleOpenWatchlist(ctx telebot.Context, msgId, showType string) error {
+	const op = "watchlist.handleOpenWatchlist"
+	h.app.Logger.Info(op, ctx, "Fetching watchlist", "message_id", msgId, "type", showType)
+
+	// Buttons on a list page carry no message ID, they open the view in the message they are on
+	msg := ctx.Message()
+	if msgID, err := strconv.Atoi(msgId); err == nil {
+		msg = &telebot.Message{ID: msgID, Chat: ctx.Chat()}
+	}
+
+	view := currentView(ctx.Sender().ID)
+	view.showType = showType
+	view.page = 1
+
+	response, btn, totalItems, err := h.renderView(ctx.Sender().ID, &view)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to retrieve watchlist", "type", showType, "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	saveView(ctx.Sender().ID, view)
+
+	if totalItems == 0 && view.showType == constants.AllType && view.priority == constants.AllType {
+		h.app.Logger.Info(op, ctx, "No items found in watchlist")
+		if _, err = ctx.Bot().Edit(msg, messages.NoWatchlistData, telebot.ModeMarkdown); err != nil {
+			h.app.Logger.Error(op, ctx, "Failed to edit message for empty watchlist", "error", err.Error())
+			return ctx.Send(messages.InternalError)
+		}
+		return nil
+	}
+
+	if _, err = ctx.Bot().Edit(msg, response, btn, telebot.ModeMarkdown); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to edit message with watchlist", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "Watchlist displayed successfully", "type", showType, "items_count", totalItems)
+	return nil
+}
+
+// handleViewChange cycles the order, the priority filter or the type filter of the view and goes back to its
+// first page, data is the type of the view the button was on
+func (h *WatchlistHandler) handleViewChange(ctx telebot.Context, action, data string) error {
+	const op = "watchlist.handleViewChange"
+	h.app.Logger.Info(op, ctx, "Changing watchlist view", "action", action, "type", data)
+
+	if !slices.Contains(watchlistTypes, data) {
+		h.app.Logger.Warning(op, ctx, "Malformed watchlist type", "type", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	view := currentView(ctx.Sender().ID)
+	view.showType = data
+	view.page = 1
+
+	switch action {
+	case "sort":
+		view.sortBy = utils.NextInCycle(utils.WatchlistSorts, view.sortBy)
+	case "filter":
+		view.priority = utils.NextInCycle(append([]string{constants.AllType}, utils.WatchlistPriorities...), view.priority)
+	case "type":
+		view.showType = utils.NextInCycle(watchlistTypes, view.showType)
+	}
+
+	if err := h.editView(ctx, view); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to update watchlist view", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "Watchlist view changed",
+		"type", view.showType, "priority", view.priority, "sort", view.sortBy)
+	return ctx.Respond()
+}
+
+// handlePage moves the view a page forward or back, data looks like <type>-<page>
+func (h *WatchlistHandler) handlePage(ctx telebot.Context, action, data string) error {
+	const op = "watchlist.handlePage"
+
+	paginationData := strings.Split(data, "-")
+	if len(paginationData) != 2 {
+		h.app.Logger.Warning(op, ctx, "Malformed pagination data", "data", data)
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	currentPage, err := strconv.Atoi(paginationData[1])
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Invalid page number", "page", paginationData[1], "error", err.Error())
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidPageNumber})
+	}
+
+	if !slices.Contains(watchlistTypes, paginationData[0]) {
+		h.app.Logger.Warning(op, ctx, "Malformed watchlist type", "type", paginationData[0])
+		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
+	}
+
+	view := currentView(ctx.Sender().ID)
+	view.showType = paginationData[0]
+	if action == "next" {
+		view.page = currentPage + 1
+	} else {
+		view.page = currentPage - 1
+	}
+
+	h.app.Logger.Debug(op, ctx, "Updating page pointer", "action", action, "current_page", currentPage)
+	response, btn, _, err := h.renderView(ctx.Sender().ID, &view)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to fetch watchlists", "error", err.Error())
+		return ctx.Send(messages.WatchlistCheckError)
+	}
+	saveView(ctx.Sender().ID, view)
+
+	_, err = ctx.Bot().Edit(ctx.Message(), response, btn, telebot.ModeMarkdown)
+	if err != nil {
+		if strings.Contains(err.Error(), "message is not modified") {
+			h.app.Logger.Debug(op, ctx, "No changes detected in message")
+			return ctx.Respond(&telebot.CallbackResponse{Text: messages.NoChanges})
+		}
+		h.app.Logger.Error(op, ctx, "Failed to edit message with updated page", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+
+	h.app.Logger.Info(op, ctx, "Page updated successfully", "current_page", view.page, "type", view.showType)
+	return ctx.Respond(&telebot.CallbackResponse{Text: messages.PageUpdated})
+}
+
+// handleBackToView shows the view again in the message of the entry editor
+func (h *WatchlistHandler) handleBackToView(ctx telebot.Context) error {
+	const op = "watchlist.handleBackToView"
+	h.app.Logger.Info(op, ctx, "Returning to watchlist view")
+
+	if err := h.editView(ctx, currentView(ctx.Sender().ID)); err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to show watchlist view", "error", err.Error())
+		return ctx.Send(messages.InternalError)
+	}
+	return ctx.Respond()
+}
+
+// editView renders the view into the message the callback came from and remembers it
+func (h *WatchlistHandler) editView(ctx telebot.Context, view watchlistView) error {
+	response, btn, _, err := h.renderView(ctx.Sender().ID, &view)
+	if err != nil {
+		return err
+	}
+	saveView(ctx.Sender().ID, view)
+
+	_, err = ctx.Bot().Edit(ctx.Message(), response, btn, telebot.ModeMarkdown)
+	if err != nil && !strings.Contains(err.Error(), "message is not modified") {
+		return fmt.Errorf("error editing watchlist message: %w", err)
+	}
+	return nil
+}
@@ -1,17 +1,14 @@
 package watchlist
 
 import (
-	"context"
 	"fmt"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/movie"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/tv"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
-	"github.com/erkinov-wtf/movie-manager-bot/pkg/paginators"
 	"gopkg.in/telebot.v3"
 	"strconv"
 	"strings"
-	"time"
 )
 
 func (h *WatchlistHandler) WatchlistInfo(ctx telebot.Context) error {
@@ -29,6 +26,7 @@ func (h *WatchlistHandler) WatchlistInfo(ctx telebot.Context) error {
 		btn.Row(btn.Data("📺 TV Shows Watchlist", "", fmt.Sprintf("watchlist|tv|%d", msg.ID))),
 		btn.Row(btn.Data("🎥 Movies Watchlist", "", fmt.Sprintf("watchlist|movie|%d", msg.ID))),
 		btn.Row(btn.Data("🍿 Whole Watchlist", "", fmt.Sprintf("watchlist|full|%d", msg.ID))),
+		btn.Row(btn.Data("📂 My Lists", "", "list|overview|-")),
 	}
 
 	btn.Inline(btnRows...)
@@ -43,142 +41,6 @@ func (h *WatchlistHandler) WatchlistInfo(ctx telebot.Context) error {
 	return nil
 }
 
-func (h *WatchlistHandler) handleTVWatchlist(ctx telebot.Context, msgId string) error {
-	const op = "watchlist.handleTVWatchlist"
-	h.app.Logger.Info(op, ctx, "Fetching TV show watchlist", "message_id", msgId)
-
-	msgID, _ := strconv.Atoi(msgId)
-	msg := &telebot.Message{ID: msgID, Chat: ctx.Chat()}
-
-	ctxDb, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
-	h.app.Logger.Debug(op, ctx, "Retrieving TV watchlist from database")
-	watchlists, err := h.app.Repository.Watchlists.GetUserWatchlistsWithType(ctxDb, ctx.Sender().ID, constants.TVShowType)
-	if err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to retrieve TV watchlists", "error", err.Error())
-		return ctx.Send(messages.InternalError)
-	}
-
-	if len(watchlists) == 0 {
-		h.app.Logger.Info(op, ctx, "No TV shows found in watchlist")
-		_, err = ctx.Bot().Edit(msg, messages.NoWatchlistData, telebot.ModeMarkdown)
-		if err != nil {
-			h.app.Logger.Error(op, ctx, "Failed to edit message for empty watchlist", "error", err.Error())
-			return ctx.Send(messages.InternalError)
-		}
-		return nil
-	}
-
-	currentPage := 1
-	totalItems := len(watchlists)
-	totalPages := (totalItems + itemsPerPage - 1) / itemsPerPage
-
-	h.app.Logger.Debug(op, ctx, "Generating TV watchlist pagination",
-		"items_count", totalItems, "total_pages", totalPages)
-	paginatedWatchlist := paginators.PaginateWatchlistWithType(watchlists, currentPage)
-	response, btn := paginators.GenerateWatchlistWithTypeResponse(&paginatedWatchlist, currentPage, totalPages, totalItems, constants.TVShowType)
-
-	_, err = ctx.Bot().Edit(msg, response, btn, telebot.ModeMarkdown)
-	if err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to edit message with TV watchlist", "error", err.Error())
-		return ctx.Send(messages.InternalError)
-	}
-
-	h.app.Logger.Info(op, ctx, "TV watchlist displayed successfully", "items_count", totalItems)
-	return nil
-}
-
-func (h *WatchlistHandler) handleMovieWatchlist(ctx telebot.Context, msgId string) error {
-	const op = "watchlist.handleMovieWatchlist"
-	h.app.Logger.Info(op, ctx, "Fetching movie watchlist", "message_id", msgId)
-
-	msgID, _ := strconv.Atoi(msgId)
-	msg := &telebot.Message{ID: msgID, Chat: ctx.Chat()}
-
-	ctxDb, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
-	h.app.Logger.Debug(op, ctx, "Retrieving movie watchlist from database")
-	watchlists, err := h.app.Repository.Watchlists.GetUserWatchlistsWithType(ctxDb, ctx.Sender().ID, constants.MovieType)
-	if err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to retrieve movie watchlists", "error", err.Error())
-		return ctx.Send(messages.InternalError)
-	}
-
-	if len(watchlists) == 0 {
-		h.app.Logger.Info(op, ctx, "No movies found in watchlist")
-		_, err = ctx.Bot().Edit(msg, messages.NoWatchlistData, telebot.ModeMarkdown)
-		if err != nil {
-			h.app.Logger.Error(op, ctx, "Failed to edit message for empty watchlist", "error", err.Error())
-			return ctx.Send(messages.InternalError)
-		}
-		return nil
-	}
-
-	currentPage := 1
-	totalItems := len(watchlists)
-	totalPages := (totalItems + itemsPerPage - 1) / itemsPerPage
-
-	paginatedWatchlist := paginators.PaginateWatchlistWithType(watchlists, currentPage)
-	response, btn := paginators.GenerateWatchlistWithTypeResponse(&paginatedWatchlist, currentPage, totalPages, totalItems, constants.MovieType)
-
-	_, err = ctx.Bot().Edit(msg, response, btn, telebot.ModeMarkdown)
-	if err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to edit message with movie watchlist", "error", err.Error())
-		return ctx.Send(messages.InternalError)
-	}
-
-	h.app.Logger.Info(op, ctx, "Movie watchlist displayed successfully", "items_count", totalItems)
-	return nil
-}
-
-func (h *WatchlistHandler) handleFullWatchlist(ctx telebot.Context, msgId string) error {
-	const op = "watchlist.handleFullWatchlist"
-	h.app.Logger.Info(op, ctx, "Fetching full watchlist", "message_id", msgId)
-
-	msgID, _ := strconv.Atoi(msgId)
-	msg := &telebot.Message{ID: msgID, Chat: ctx.Chat()}
-
-	ctxDb, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
-	h.app.Logger.Debug(op, ctx, "Retrieving complete watchlist from database")
-	watchlists, err := h.app.Repository.Watchlists.GetUserWatchlists(ctxDb, ctx.Sender().ID)
-	if err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to retrieve watchlists", "error", err.Error())
-		return ctx.Send(messages.InternalError)
-	}
-
-	if len(watchlists) == 0 {
-		h.app.Logger.Info(op, ctx, "No items found in watchlist")
-		_, err = ctx.Bot().Edit(msg, messages.NoWatchlistData, telebot.ModeMarkdown)
-		if err != nil {
-			h.app.Logger.Error(op, ctx, "Failed to edit message for empty watchlist", "error", err.Error())
-			return ctx.Send(messages.InternalError)
-		}
-		return nil
-	}
-
-	currentPage := 1
-	totalItems := len(watchlists)
-	totalPages := (totalItems + itemsPerPage - 1) / itemsPerPage
-
-	h.app.Logger.Debug(op, ctx, "Generating full watchlist pagination",
-		"items_count", totalItems, "total_pages", totalPages)
-	paginatedWatchlist := paginators.PaginateWatchlist(watchlists, currentPage)
-	response, btn := paginators.GenerateWatchlistResponse(&paginatedWatchlist, currentPage, totalPages, totalItems, constants.AllType)
-
-	_, err = ctx.Bot().Edit(msg, response, btn, telebot.ModeMarkdown)
-	if err != nil {
-		h.app.Logger.Error(op, ctx, "Failed to edit message with full watchlist", "error", err.Error())
-		return ctx.Send(messages.InternalError)
-	}
-
-	h.app.Logger.Info(op, ctx, "Full watchlist displayed successfully", "items_count", totalItems)
-	return nil
-}
-
 func (h *WatchlistHandler) handleWatchlistInfo(ctx telebot.Context, data string) error {
 	const op = "watchlist.handleWatchlistInfo"
 	h.app.Logger.Info(op, ctx, "Fetching watchlist item details", "data", data)
@@ -201,14 +63,16 @@ func (h *WatchlistHandler) handleWatchlistInfo(ctx telebot.Context, data string)
 
 	if movieType == constants.MovieType {
 		h.app.Logger.Debug(op, ctx, "Retrieving movie details", "movie_id", parsedId)
-		movieData, err := movie.GetMovie(h.app, parsedId, ctx.Sender().ID)
+		tmdbCtx, cancel := h.app.TMDBContext()
+		movieData, err := movie.GetMovie(tmdbCtx, h.app, parsedId, ctx.Sender().ID)
+		cancel()
 		if err != nil {
 			h.app.Logger.Error(op, ctx, "Failed to get movie data", "movie_id", parsedId, "error", err.Error())
 			return ctx.Send(messages.InternalError)
 		}
 
 		h.app.Logger.Debug(op, ctx, "Displaying movie details", "title", movieData.Title)
-		err = movie.ShowMovie(h.app, ctx, movieData, false)
+		err = movie.ShowMovie(h.app, ctx, movieData, fmt.Sprintf("watchlist|back_to_pagination|%s", constants.MovieType))
 		if err != nil {
 			h.app.Logger.Error(op, ctx, "Failed to show movie details", "movie_id", parsedId, "error", err.Error())
 			return ctx.Send(messages.InternalError)
@@ -218,14 +82,16 @@ func (h *WatchlistHandler) handleWatchlistInfo(ctx telebot.Context, data string)
 		return ctx.Respond(&telebot.CallbackResponse{Text: messages.MovieSelected})
 	} else {
 		h.app.Logger.Debug(op, ctx, "Retrieving TV show details", "tv_id", parsedId)
-		tvData, err := tv.GetTV(h.app, parsedId, ctx.Sender().ID)
+		tmdbCtx, cancel := h.app.TMDBContext()
+		tvData, err := tv.GetTV(tmdbCtx, h.app, parsedId, ctx.Sender().ID)
+		cancel()
 		if err != nil {
 			h.app.Logger.Error(op, ctx, "Failed to get TV show data", "tv_id", parsedId, "error", err.Error())
 			return err
 		}
 
 		h.app.Logger.Debug(op, ctx, "Displaying TV show details", "name", tvData.Name)
-		err = tv.ShowTV(h.app, ctx, tvData, false)
+		err = tv.ShowTV(h.app, ctx, tvData, fmt.Sprintf("watchlist|back_to_pagination|%s", constants.TVShowType))
 		if err != nil {
 			h.app.Logger.Error(op, ctx, "Failed to show TV show details", "tv_id", parsedId, "error", err.Error())
 			return err
@@ -236,18 +102,11 @@ func (h *WatchlistHandler) handleWatchlistInfo(ctx telebot.Context, data string)
 	}
 }
 
+// handleBackToPagination replaces a detail card with the watchlist view it was opened from
 func (h *WatchlistHandler) handleBackToPagination(ctx telebot.Context, showType string) error {
 	const op = "watchlist.handleBackToPagination"
 	h.app.Logger.Info(op, ctx, "Returning to paginated watchlist", "type", showType)
 
-	const (
-		timeout     = 2 * time.Second
-		currentPage = 1
-	)
-
-	ctxDb, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-
 	// Delete the movie/show details message before any database operations
 	h.app.Logger.Debug(op, ctx, "Deleting current message")
 	if err := ctx.Delete(); err != nil {
@@ -255,48 +114,22 @@ func (h *WatchlistHandler) handleBackToPagination(ctx telebot.Context, showType
 		return ctx.Send(messages.WatchlistCheckError)
 	}
 
-	var response string
-	var btn *telebot.ReplyMarkup
-
-	if showType == constants.AllType {
-		// Handle all type watchlists
-		h.app.Logger.Debug(op, ctx, "Retrieving complete watchlist")
-		watchlists, err := h.app.Repository.Watchlists.GetUserWatchlists(ctxDb, ctx.Sender().ID)
-		if err != nil {
-			h.app.Logger.Error(op, ctx, "Failed to get watchlists", "error", err.Error())
-			return ctx.Send(messages.WatchlistCheckError)
-		}
-
-		totalItems := len(watchlists)
-		totalPages := (totalItems + itemsPerPage - 1) / itemsPerPage
-
-		// Generate pagination and response
-		h.app.Logger.Debug(op, ctx, "Generating full watchlist response",
-			"items_count", totalItems, "pages", totalPages)
-		paginatedWatchlist := paginators.PaginateWatchlist(watchlists, currentPage)
-		response, btn = paginators.GenerateWatchlistResponse(&paginatedWatchlist, currentPage, totalPages, totalItems, showType)
-	} else {
-		// Handle type-specific watchlists
-		h.app.Logger.Debug(op, ctx, "Retrieving type-specific watchlist", "type", showType)
-		watchlists, err := h.app.Repository.Watchlists.GetUserWatchlistsWithType(ctxDb, ctx.Sender().ID, showType)
-		if err != nil {
-			h.app.Logger.Error(op, ctx, "Failed to get watchlists by type", "type", showType, "error", err.Error())
-			return ctx.Send(messages.WatchlistCheckError)
-		}
-
-		totalItems := len(watchlists)
-		totalPages := (totalItems + itemsPerPage - 1) / itemsPerPage
+	// The card is opened from any type of view, its button only knows the type of the title
+	view := currentView(ctx.Sender().ID)
+	if view.showType != constants.AllType {
+		view.showType = showType
+	}
 
-		// Generate pagination and response
-		h.app.Logger.Debug(op, ctx, "Generating type-specific watchlist response",
-			"items_count", totalItems, "pages", totalPages, "type", showType)
-		paginatedWatchlist := paginators.PaginateWatchlistWithType(watchlists, currentPage)
-		response, btn = paginators.GenerateWatchlistWithTypeResponse(&paginatedWatchlist, currentPage, totalPages, totalItems, showType)
+	response, btn, _, err := h.renderView(ctx.Sender().ID, &view)
+	if err != nil {
+		h.app.Logger.Error(op, ctx, "Failed to get watchlists", "error", err.Error())
+		return ctx.Send(messages.WatchlistCheckError)
 	}
+	saveView(ctx.Sender().ID, view)
 
 	// Send new message with watchlist
 	h.app.Logger.Debug(op, ctx, "Sending watchlist message")
-	if _, err := ctx.Bot().Send(ctx.Chat(), response, btn, telebot.ModeMarkdown); err != nil {
+	if _, err = ctx.Bot().Send(ctx.Chat(), response, btn, telebot.ModeMarkdown); err != nil {
 		h.app.Logger.Error(op, ctx, "Failed to send watchlist", "error", err.Error())
 		return ctx.Send(messages.WatchlistCheckError)
 	}
@@ -329,107 +162,40 @@ func (h *WatchlistHandler) WatchlistCallback(ctx telebot.Context) error {
 
 	switch action {
 	case "tv":
-		return h.handleTVWatchlist(ctx, data)
+		return h.handleOpenWatchlist(ctx, data, constants.TVShowType)
 
 	case "movie":
-		return h.handleMovieWatchlist(ctx, data)
+		return h.handleOpenWatchlist(ctx, data, constants.MovieType)
 
 	case "full":
-		return h.handleFullWatchlist(ctx, data)
+		return h.handleOpenWatchlist(ctx, data, constants.AllType)
 
 	case "info":
 		return h.handleWatchlistInfo(ctx, data)
 
 	case "next", "prev":
-		paginationData := strings.Split(data, "-")
-		if len(paginationData) != 2 {
-			h.app.Logger.Warning(op, ctx, "Malformed pagination data", "data", data)
-			return ctx.Respond(&telebot.CallbackResponse{Text: messages.MalformedData})
-		}
+		return h.handlePage(ctx, action, data)
 
-		watchlistType := paginationData[0]
-		currentPage, err := strconv.Atoi(paginationData[1])
-		if err != nil {
-			h.app.Logger.Error(op, ctx, "Invalid page number", "page", paginationData[1], "error", err.Error())
-			return ctx.Respond(&telebot.CallbackResponse{Text: messages.InvalidPageNumber})
-		}
+	case "sort", "filter", "type":
+		return h.handleViewChange(ctx, action, data)
 
-		// Create context with timeout for database operations
-		ctxDb, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
-
-		var totalItems int
-		var totalPages int
-		var response string
-		var btn *telebot.ReplyMarkup
-
-		// Handle pagination based on watchlist type
-		if watchlistType == constants.AllType {
-			// Fetch all watchlists using repository
-			h.app.Logger.Debug(op, ctx, "Fetching complete watchlist for pagination")
-			watchlists, err := h.app.Repository.Watchlists.GetUserWatchlists(ctxDb, ctx.Sender().ID)
-			if err != nil {
-				h.app.Logger.Error(op, ctx, "Failed to fetch watchlists", "error", err.Error())
-				return ctx.Send(messages.WatchlistCheckError)
-			}
-
-			// Calculate pagination metrics
-			totalItems = len(watchlists)
-			totalPages = (totalItems + itemsPerPage - 1) / itemsPerPage
-
-			// Update current page based on action
-			h.app.Logger.Debug(op, ctx, "Updating page pointer",
-				"action", action, "current_page", currentPage, "total_pages", totalPages)
-			if action == "next" && currentPage < totalPages {
-				currentPage++
-			} else if action == "prev" && currentPage > 1 {
-				currentPage--
-			}
-
-			// Generate paginated response
-			paginatedWatchlist := paginators.PaginateWatchlist(watchlists, currentPage)
-			response, btn = paginators.GenerateWatchlistResponse(&paginatedWatchlist, currentPage, totalPages, totalItems, watchlistType)
-		} else {
-			// Fetch type-specific watchlists using repository
-			h.app.Logger.Debug(op, ctx, "Fetching type-specific watchlist for pagination", "type", watchlistType)
-			watchlists, err := h.app.Repository.Watchlists.GetUserWatchlistsWithType(ctxDb, ctx.Sender().ID, watchlistType)
-			if err != nil {
-				h.app.Logger.Error(op, ctx, "Failed to fetch watchlists with type", "type", watchlistType, "error", err.Error())
-				return ctx.Send(messages.WatchlistCheckError)
-			}
-
-			// Calculate pagination metrics
-			totalItems = len(watchlists)
-			totalPages = (totalItems + itemsPerPage - 1) / itemsPerPage
-
-			// Update current page based on action
-			h.app.Logger.Debug(op, ctx, "Updating page pointer",
-				"action", action, "current_page", currentPage, "total_pages", totalPages)
-			if action == "next" && currentPage < totalPages {
-				currentPage++
-			} else if action == "prev" && currentPage > 1 {
-				currentPage--
-			}
-
-			// Generate paginated response
-			paginatedWatchlist := paginators.PaginateWatchlistWithType(watchlists, currentPage)
-			response, btn = paginators.GenerateWatchlistWithTypeResponse(&paginatedWatchlist, currentPage, totalPages, totalItems, watchlistType)
-		}
+	case "view":
+		return h.handleBackToView(ctx)
 
-		// Update the message with new pagination
-		h.app.Logger.Debug(op, ctx, "Updating message with new page", "new_page", currentPage)
-		_, err = ctx.Bot().Edit(ctx.Message(), response, btn, telebot.ModeMarkdown)
-		if err != nil {
-			if strings.Contains(err.Error(), "message is not modified") {
-				h.app.Logger.Debug(op, ctx, "No changes detected in message")
-				return ctx.Respond(&telebot.CallbackResponse{Text: messages.NoChanges})
-			}
-			h.app.Logger.Error(op, ctx, "Failed to edit message with updated page", "error", err.Error())
-			return ctx.Send(messages.InternalError)
-		}
+	case "edit":
+		return h.handleEdit(ctx, data)
+
+	case "priority":
+		return h.handlePriority(ctx, data)
+
+	case "note":
+		return h.handleNote(ctx, data)
+
+	case "clear_note":
+		return h.handleClearNote(ctx, data)
 
-		h.app.Logger.Info(op, ctx, "Page updated successfully", "current_page", currentPage, "type", watchlistType)
-		return ctx.Respond(&telebot.CallbackResponse{Text: messages.PageUpdated})
+	case "move":
+		return h.handleMove(ctx, data)
 
 	case "back_to_pagination":
 		return h.handleBackToPagination(ctx, data)
@@ -0,0 +1,8 @@
+package interfaces
+
+import "gopkg.in/telebot.v3"
+
+type AdminInterface interface {
+	Worker(context telebot.Context) error
+	WorkerCallback(context telebot.Context) error
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "gopkg.in/telebot.v3"
+
+type ListInterface interface {
+	Lists(context telebot.Context) error
+	ListCallback(context telebot.Context) error
+	HandleListNameInput(context telebot.Context) error
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "gopkg.in/telebot.v3"
+
+type RatingInterface interface {
+	PromptRating(context telebot.Context, showType string, apiId int64, season int32, title string) error
+	RatingCallback(context telebot.Context) error
+	HandleReviewInput(context telebot.Context) error
+}
@@ -0,0 +1,8 @@
+package interfaces
+
+import "gopkg.in/telebot.v3"
+
+type SettingsInterface interface {
+	Settings(context telebot.Context) error
+	SettingsCallback(context telebot.Context) error
+}
@@ -5,4 +5,5 @@ import "gopkg.in/telebot.v3"
 type WatchlistInterface interface {
 	WatchlistInfo(context telebot.Context) error
 	WatchlistCallback(context telebot.Context) error
+	HandleNoteInput(context telebot.Context) error
 }
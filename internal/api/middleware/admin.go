@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
+	"gopkg.in/telebot.v3"
+	"slices"
+)
+
+func IsAdmin(c telebot.Context, app *app.App) bool {
+	return slices.Contains(app.Cfg.General.AdminIDs, c.Sender().ID)
+}
+
+func RequireAdmin(next telebot.HandlerFunc, app *app.App) telebot.HandlerFunc {
+	return func(c telebot.Context) error {
+		const op = "middleware.RequireAdmin"
+
+		if !IsAdmin(c, app) {
+			app.Logger.Warning(op, c, "Admin command used by non-admin user", "user_id", c.Sender().ID)
+			if c.Callback() != nil {
+				return c.Respond(&telebot.CallbackResponse{Text: messages.AdminOnly})
+			}
+			return c.Send(messages.AdminOnly)
+		}
+
+		app.Logger.Debug(op, c, "User is an admin, proceeding with request")
+		return next(c)
+	}
+}
@@ -1,14 +1,19 @@
 package api
 
 import (
+	"github.com/erkinov-wtf/movie-manager-bot/internal/api/handlers/admin"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/api/handlers/defaults"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/api/handlers/info"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/api/handlers/list"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/api/handlers/movie"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/api/handlers/rating"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/api/handlers/settings"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/api/handlers/tv"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/api/handlers/watchlist"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/api/interfaces"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/keyboards"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/workers"
 )
 
 type Resolver struct {
@@ -17,13 +22,18 @@ type Resolver struct {
 	TVHandler        interfaces.TVInterface
 	InfoHandler      interfaces.InfoInterface
 	WatchlistHandler interfaces.WatchlistInterface
+	SettingsHandler  interfaces.SettingsInterface
+	AdminHandler     interfaces.AdminInterface
+	RatingHandler    interfaces.RatingInterface
+	ListHandler      interfaces.ListInterface
 
 	KeyboardFactory *keyboards.KeyboardFactory
 }
 
-func NewResolver(app *app.App) *Resolver {
-	movieHandler := movie.NewMovieHandler(app)
-	tvHandler := tv.NewTVHandler(app)
+func NewResolver(app *app.App, scheduler *workers.Scheduler) *Resolver {
+	ratingHandler := rating.NewRatingHandler(app)
+	movieHandler := movie.NewMovieHandler(app, ratingHandler)
+	tvHandler := tv.NewTVHandler(app, ratingHandler)
 	infoHandler := info.NewInfoHandler(app)
 	watchlistHandler := watchlist.NewWatchlistHandler(app)
 	keys := keyboards.NewKeyboardFactory(app, watchlistHandler, infoHandler)
@@ -34,6 +44,10 @@ func NewResolver(app *app.App) *Resolver {
 		TVHandler:        tvHandler,
 		InfoHandler:      infoHandler,
 		WatchlistHandler: watchlistHandler,
+		SettingsHandler:  settings.NewSettingsHandler(app),
+		AdminHandler:     admin.NewAdminHandler(app, scheduler),
+		RatingHandler:    ratingHandler,
+		ListHandler:      list.NewListHandler(app),
 		KeyboardFactory:  keys,
 	}
 }
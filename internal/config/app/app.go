@@ -1,15 +1,22 @@
 package app
 
 import (
+	"context"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/config"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/cache"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database/repository"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/encryption"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils/logger"
+	"time"
 )
 
+// TMDBTimeout bounds a TMDB request made while a user waits for an answer, retries included
+const TMDBTimeout = 20 * time.Second
+
 type App struct {
+	// Ctx is cancelled when the bot shuts down, requests made on behalf of users derive from it
+	Ctx        context.Context
 	Cfg        *config.Config
 	Repository *repository.Manager
 	TMDBClient *tmdb.Client
@@ -18,8 +25,9 @@ type App struct {
 	Logger     *logger.Logger
 }
 
-func NewApp(cfg *config.Config, repos *repository.Manager, client *tmdb.Client, cache *cache.Manager, encryptor *encryption.KeyEncryptor, logger *logger.Logger) *App {
+func NewApp(ctx context.Context, cfg *config.Config, repos *repository.Manager, client *tmdb.Client, cache *cache.Manager, encryptor *encryption.KeyEncryptor, logger *logger.Logger) *App {
 	return &App{
+		Ctx:        ctx,
 		Cfg:        cfg,
 		Repository: repos,
 		TMDBClient: client,
@@ -28,3 +36,9 @@ func NewApp(cfg *config.Config, repos *repository.Manager, client *tmdb.Client,
 		Logger:     logger,
 	}
 }
+
+// TMDBContext returns a context for a TMDB request of a handler. It ends after TMDBTimeout or when the bot shuts
+// down, whichever comes first.
+func (a *App) TMDBContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(a.Ctx, TMDBTimeout)
+}
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"github.com/ilyakaznacheev/cleanenv"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 )
 
 type Config struct {
@@ -17,10 +20,23 @@ type Config struct {
 }
 
 type General struct {
-	BotToken        string `yaml:"bot_token"`
-	SecretKey       string `yaml:"secret_key"`
-	WorkerPeriod    int    `yaml:"worker_period"`
-	WorkerRateLimit int    `yaml:"worker_rate_limit"`
+	BotToken              string `yaml:"bot_token"`
+	SecretKey             string `yaml:"secret_key"`
+	WorkerPeriod          int    `yaml:"worker_period"`
+	WorkerRateLimit       int    `yaml:"worker_rate_limit"`
+	EpisodeReminderPeriod int    `yaml:"episode_reminder_period"`
+	MovieReleasePeriod    int    `yaml:"movie_release_period"`
+	AvailabilityPeriod    int    `yaml:"availability_period"`
+	NotificationPoll      int    `yaml:"notification_poll_interval"`
+	NotificationAttempts  int    `yaml:"notification_max_attempts"`
+	WorkerInstance        string `yaml:"worker_instance"`
+	TaskRetentionDays     int    `yaml:"task_retention_days"`
+	TMDBKeyRateLimit      int    `yaml:"tmdb_key_rate_limit"`
+	ShutdownTimeout       int    `yaml:"shutdown_timeout"`
+	// AdminIDs are the Telegram IDs allowed to use admin commands
+	AdminIDs []int64 `yaml:"admin_ids"`
+	// JobSchedules overrides the period of a background job with a cron expression, keyed by job name
+	JobSchedules map[string]string `yaml:"job_schedules"`
 }
 
 type Database struct {
@@ -43,6 +59,8 @@ type Endpoints struct {
 			Movie  string `yaml:"movie"`
 			TV     string `yaml:"tv"`
 		} `yaml:"search"`
+		// WatchProviders is appended to a movie or TV resource, and prefixes the provider lists of a region
+		WatchProviders string `yaml:"watch_providers"`
 	} `yaml:"resources"`
 }
 
@@ -66,11 +84,38 @@ func MustLoad() *Config {
 
 	updateCredentials(&Cfg)
 
+	if err := validate(&Cfg); err != nil {
+		log.Fatalf("invalid config: %s", err.Error())
+	}
+
 	log.Println("Configurations loaded")
 
 	return &Cfg
 }
 
+// validate rejects settings the bot cannot run with. A period of 0 would make a job run again right away, and a
+// ticker can't be built without a positive interval at all.
+func validate(cfg *Config) error {
+	intervals := []struct {
+		key   string
+		value int
+	}{
+		{"worker_period", cfg.General.WorkerPeriod},
+		{"episode_reminder_period", cfg.General.EpisodeReminderPeriod},
+		{"movie_release_period", cfg.General.MovieReleasePeriod},
+		{"availability_period", cfg.General.AvailabilityPeriod},
+		{"notification_poll_interval", cfg.General.NotificationPoll},
+	}
+
+	for _, interval := range intervals {
+		if interval.value <= 0 {
+			return fmt.Errorf("general.%s must be positive, got %d", interval.key, interval.value)
+		}
+	}
+
+	return nil
+}
+
 func updateCredentials(cfg *Config) {
 	if env := os.Getenv("ENV"); env != "" {
 		cfg.Env = env
@@ -81,6 +126,12 @@ func updateCredentials(cfg *Config) {
 	if secretKey := os.Getenv("SECRET_KEY"); secretKey != "" {
 		cfg.General.SecretKey = secretKey
 	}
+	if workerInstance := os.Getenv("WORKER_INSTANCE"); workerInstance != "" {
+		cfg.General.WorkerInstance = workerInstance
+	}
+	if adminIds := os.Getenv("ADMIN_IDS"); adminIds != "" {
+		cfg.General.AdminIDs = parseIds(adminIds)
+	}
 	if dbHost := os.Getenv("DB_HOST"); dbHost != "" {
 		cfg.Database.Host = dbHost
 	}
@@ -103,3 +154,17 @@ func updateCredentials(cfg *Config) {
 		cfg.Betterstack.Token = betterstackToken
 	}
 }
+
+// parseIds reads a comma separated list of Telegram IDs, entries that are not numbers are skipped
+func parseIds(value string) []int64 {
+	var ids []int64
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			log.Printf("skipping invalid admin id: %q", part)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -34,6 +34,18 @@ func SetupDefaultRoutes(bot *telebot.Bot, resolver *api.Resolver, app *appCfg.Ap
 			app.Logger.Info(handlerOp, context, "Handling API token input")
 			return resolver.DefaultHandler.HandleTextInput(context)
 
+		case userCache.ReviewState.IsReviewWaiting:
+			app.Logger.Info(handlerOp, context, "Handling review input")
+			return resolver.RatingHandler.HandleReviewInput(context)
+
+		case userCache.ListState.IsNameWaiting:
+			app.Logger.Info(handlerOp, context, "Handling list name input")
+			return resolver.ListHandler.HandleListNameInput(context)
+
+		case userCache.NoteState.IsNoteWaiting:
+			app.Logger.Info(handlerOp, context, "Handling watchlist note input")
+			return resolver.WatchlistHandler.HandleNoteInput(context)
+
 		case userCache.SearchState.IsSearchWaiting:
 			app.Logger.Info(handlerOp, context, "Handling search reply")
 			return resolver.DefaultHandler.HandleReplySearch(context)
@@ -77,6 +89,21 @@ func SetupWatchlistRoutes(bot *telebot.Bot, container *api.Resolver, app *appCfg
 	bot.Handle("/w", middleware.RequireTMDBToken(container.WatchlistHandler.WatchlistInfo, app))
 }
 
+func SetupListRoutes(bot *telebot.Bot, container *api.Resolver, app *appCfg.App) {
+	const op = "routes.SetupListRoutes"
+	bot.Handle("/lists", middleware.RequireTMDBToken(container.ListHandler.Lists, app))
+}
+
+func SetupSettingsRoutes(bot *telebot.Bot, container *api.Resolver, app *appCfg.App) {
+	const op = "routes.SetupSettingsRoutes"
+	bot.Handle("/settings", middleware.RequireRegistration(container.SettingsHandler.Settings, app))
+}
+
+func SetupAdminRoutes(bot *telebot.Bot, container *api.Resolver, app *appCfg.App) {
+	const op = "routes.SetupAdminRoutes"
+	bot.Handle("/worker", middleware.RequireAdmin(container.AdminHandler.Worker, app))
+}
+
 func handleCallback(container *api.Resolver, app *appCfg.App) func(c telebot.Context) error {
 	return func(c telebot.Context) error {
 		const op = "routes.handleCallback"
@@ -104,6 +131,22 @@ func handleCallback(container *api.Resolver, app *appCfg.App) func(c telebot.Con
 			app.Logger.Debug(op, c, "Routing to watchlist callback handler")
 			return container.WatchlistHandler.WatchlistCallback(c)
 
+		case strings.HasPrefix(trimmed, "list|"):
+			app.Logger.Debug(op, c, "Routing to list callback handler")
+			return container.ListHandler.ListCallback(c)
+
+		case strings.HasPrefix(trimmed, "settings|"):
+			app.Logger.Debug(op, c, "Routing to settings callback handler")
+			return container.SettingsHandler.SettingsCallback(c)
+
+		case strings.HasPrefix(trimmed, "rating|"):
+			app.Logger.Debug(op, c, "Routing to rating callback handler")
+			return container.RatingHandler.RatingCallback(c)
+
+		case strings.HasPrefix(trimmed, "worker|"):
+			app.Logger.Debug(op, c, "Routing to worker callback handler")
+			return middleware.RequireAdmin(container.AdminHandler.WorkerCallback, app)(c)
+
 		default:
 			app.Logger.Warning(op, c, "Unknown callback type received", "callback_data", trimmed)
 			return c.Respond(&telebot.CallbackResponse{Text: "Unknown callback type"})
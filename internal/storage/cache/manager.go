@@ -11,6 +11,7 @@ type Manager struct {
 	TVShowCache map[int]*Item
 	UserCache   *UserCacheData
 	ImageCache  *Image
+	UndoCache   *Undo
 	mu          sync.RWMutex
 }
 
@@ -20,6 +21,7 @@ func NewCacheManager(repos *repository.Manager, encryptor *encryption.KeyEncrypt
 		TVShowCache: make(map[int]*Item),
 		UserCache:   NewUserCache(repos, encryptor),
 		ImageCache:  NewImageCache(),
+		UndoCache:   NewUndoCache(),
 	}
 }
 
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"sync"
+	"time"
+)
+
+// UndoWindow is how long a removal from the watched library can be taken back
+const UndoWindow = 10 * time.Minute
+
+// UndoSnapshot holds what a removal took from a user's library, enough to put it back
+type UndoSnapshot struct {
+	Title       string
+	Runtime     int32
+	WatchEvents []database.DeleteWatchEventsRow
+	Episodes    []database.DeleteEpisodeWatchesAfterRow
+	Ratings     []database.DeleteTitleRatingsRow
+	ExpireTime  time.Time
+}
+
+type undoKey struct {
+	userId   int64
+	showType string
+	apiId    int64
+}
+
+// Undo keeps the latest removal of each title per user until the undo window closes
+type Undo struct {
+	mu    sync.Mutex
+	items map[undoKey]UndoSnapshot
+}
+
+func NewUndoCache() *Undo {
+	return &Undo{
+		items: make(map[undoKey]UndoSnapshot),
+	}
+}
+
+// Put stores the snapshot of a removal, replacing an earlier one of the same title
+func (u *Undo) Put(userId int64, showType string, apiId int64, snapshot UndoSnapshot) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	now := time.Now()
+	for key, item := range u.items {
+		if item.ExpireTime.Before(now) {
+			delete(u.items, key)
+		}
+	}
+
+	snapshot.ExpireTime = now.Add(UndoWindow)
+	u.items[undoKey{userId: userId, showType: showType, apiId: apiId}] = snapshot
+}
+
+// Take removes and returns the snapshot of a title, it reports false once the undo window has closed
+func (u *Undo) Take(userId int64, showType string, apiId int64) (UndoSnapshot, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	key := undoKey{userId: userId, showType: showType, apiId: apiId}
+	snapshot, found := u.items[key]
+	if !found {
+		return UndoSnapshot{}, false
+	}
+
+	delete(u.items, key)
+	return snapshot, snapshot.ExpireTime.After(time.Now())
+}
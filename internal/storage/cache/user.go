@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database/repository"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/encryption"
+	"github.com/google/uuid"
 	"log"
 	"sync"
 	"time"
@@ -14,6 +15,9 @@ type UserCacheItem struct {
 	ExpireTime  time.Time
 	ApiToken    ApiToken
 	SearchState SearchState
+	ReviewState ReviewState
+	ListState   ListState
+	NoteState   NoteState
 }
 
 type UserCacheData struct {
@@ -34,6 +38,27 @@ type SearchState struct {
 	IsTVShowSearch  bool
 }
 
+// ReviewState points at the rating the next text message of the user is a review of
+type ReviewState struct {
+	IsReviewWaiting bool
+	ApiId           int64
+	ShowType        string
+	SeasonNumber    int32
+}
+
+// ListState marks the next text message of the user as the name of a new list, or the new name of ListId
+type ListState struct {
+	IsNameWaiting bool
+	ListId        uuid.UUID
+}
+
+// NoteState points at the watchlist entry the next text message of the user is a note of
+type NoteState struct {
+	IsNoteWaiting bool
+	ApiId         int64
+	ShowType      string
+}
+
 func NewUserCache(repos *repository.Manager, keyEncryptor *encryption.KeyEncryptor) *UserCacheData {
 	userCache := UserCacheData{
 		items:     make(map[int64]UserCacheItem),
@@ -149,6 +174,9 @@ func (c *UserCacheData) SetSearchStartTrue(userId int64, isMovieSearch bool) {
 			IsMovieSearch:   isMovieSearch,
 			IsTVShowSearch:  !isMovieSearch,
 		}
+		userCache.ReviewState = ReviewState{}
+		userCache.ListState = ListState{}
+		userCache.NoteState = NoteState{}
 
 		c.items[userId] = userCache
 		log.Printf("Updated search state to TRUE for user Id %d", userId)
@@ -171,6 +199,104 @@ func (c *UserCacheData) SetSearchStartFalse(userId int64) {
 	}
 }
 
+// SetReviewStart makes the next text message of the user a review of the given rating, a pending search is dropped
+func (c *UserCacheData) SetReviewStart(userId int64, showType string, apiId int64, season int32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if userCache, found := c.items[userId]; found && time.Now().Before(userCache.ExpireTime) {
+		userCache.ReviewState = ReviewState{
+			IsReviewWaiting: true,
+			ApiId:           apiId,
+			ShowType:        showType,
+			SeasonNumber:    season,
+		}
+		userCache.SearchState = SearchState{}
+		userCache.ListState = ListState{}
+		userCache.NoteState = NoteState{}
+
+		c.items[userId] = userCache
+		log.Printf("Updated review state to TRUE for user Id %d", userId)
+	}
+}
+
+func (c *UserCacheData) SetReviewDone(userId int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if userCache, found := c.items[userId]; found {
+		userCache.ReviewState = ReviewState{}
+
+		c.items[userId] = userCache
+		log.Printf("Updated review state to FALSE for user Id %d", userId)
+	}
+}
+
+// SetListNameStart makes the next text message of the user a list name, uuid.Nil creates a new list and any other
+// id renames that list. A pending search or review is dropped.
+func (c *UserCacheData) SetListNameStart(userId int64, listId uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if userCache, found := c.items[userId]; found && time.Now().Before(userCache.ExpireTime) {
+		userCache.ListState = ListState{
+			IsNameWaiting: true,
+			ListId:        listId,
+		}
+		userCache.SearchState = SearchState{}
+		userCache.ReviewState = ReviewState{}
+		userCache.NoteState = NoteState{}
+
+		c.items[userId] = userCache
+		log.Printf("Updated list name state to TRUE for user Id %d", userId)
+	}
+}
+
+func (c *UserCacheData) SetListNameDone(userId int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if userCache, found := c.items[userId]; found {
+		userCache.ListState = ListState{}
+
+		c.items[userId] = userCache
+		log.Printf("Updated list name state to FALSE for user Id %d", userId)
+	}
+}
+
+// SetNoteStart makes the next text message of the user the note of a watchlist entry, other pending inputs are
+// dropped
+func (c *UserCacheData) SetNoteStart(userId int64, showType string, apiId int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if userCache, found := c.items[userId]; found && time.Now().Before(userCache.ExpireTime) {
+		userCache.NoteState = NoteState{
+			IsNoteWaiting: true,
+			ApiId:         apiId,
+			ShowType:      showType,
+		}
+		userCache.SearchState = SearchState{}
+		userCache.ReviewState = ReviewState{}
+		userCache.ListState = ListState{}
+
+		c.items[userId] = userCache
+		log.Printf("Updated note state to TRUE for user Id %d", userId)
+	}
+}
+
+func (c *UserCacheData) SetNoteDone(userId int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if userCache, found := c.items[userId]; found {
+		userCache.NoteState = NoteState{}
+
+		c.items[userId] = userCache
+		log.Printf("Updated note state to FALSE for user Id %d", userId)
+	}
+}
+
 // Clear removes all items from the cache
 func (c *UserCacheData) Clear() {
 	c.mu.Lock()
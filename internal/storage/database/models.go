@@ -9,6 +9,42 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// Stores air day reminders sent to users and whether they watched the episode
+type EpisodeReminder struct {
+	ID            uuid.UUID          `json:"id"`
+	UserID        int64              `json:"user_id"`
+	ShowApiID     int64              `json:"show_api_id"`
+	SeasonNumber  int32              `json:"season_number"`
+	EpisodeNumber int32              `json:"episode_number"`
+	SentAt        pgtype.Timestamptz `json:"sent_at"`
+	WatchedAt     pgtype.Timestamptz `json:"watched_at"`
+}
+
+// Stores air dates of episodes for shows tracked by users
+type EpisodeSchedule struct {
+	ID            uuid.UUID          `json:"id"`
+	ShowApiID     int64              `json:"show_api_id"`
+	ShowName      string             `json:"show_name"`
+	SeasonNumber  int32              `json:"season_number"`
+	EpisodeNumber int32              `json:"episode_number"`
+	EpisodeName   *string            `json:"episode_name"`
+	AirDate       pgtype.Date        `json:"air_date"`
+	Runtime       *int32             `json:"runtime"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Stores every episode a user has watched, the totals of tv_shows are derived from it
+type EpisodeWatch struct {
+	ID            uuid.UUID          `json:"id"`
+	UserID        int64              `json:"user_id"`
+	ShowApiID     int64              `json:"show_api_id"`
+	SeasonNumber  int32              `json:"season_number"`
+	EpisodeNumber int32              `json:"episode_number"`
+	Runtime       int32              `json:"runtime"`
+	WatchedAt     pgtype.Timestamptz `json:"watched_at"`
+}
+
 // Stores movie information tracked by users
 type Movie struct {
 	ID        uuid.UUID          `json:"id"`
@@ -21,19 +57,75 @@ type Movie struct {
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
 }
 
+// Outbox of worker messages with their delivery status
+type Notification struct {
+	ID            uuid.UUID          `json:"id"`
+	UserID        int64              `json:"user_id"`
+	Kind          string             `json:"kind"`
+	ShowApiID     *int64             `json:"show_api_id"`
+	Text          string             `json:"text"`
+	ImagePath     *string            `json:"image_path"`
+	ReplyMarkup   []byte             `json:"reply_markup"`
+	Status        string             `json:"status"`
+	Attempts      int32              `json:"attempts"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+	LastError     *string            `json:"last_error"`
+	SentAt        pgtype.Timestamptz `json:"sent_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	ShowName      *string            `json:"show_name"`
+	PosterSent    bool               `json:"poster_sent"`
+}
+
+// Stores ratings and short reviews of watched titles, season 0 rates the whole title
+type Rating struct {
+	ID           uuid.UUID          `json:"id"`
+	UserID       int64              `json:"user_id"`
+	ApiID        int64              `json:"api_id"`
+	Type         string             `json:"type"`
+	SeasonNumber int32              `json:"season_number"`
+	Rating       int16              `json:"rating"`
+	Review       *string            `json:"review"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Per-show next check times derived from the TMDB status and the next air date
+type ShowCheckSchedule struct {
+	ShowApiID     int64              `json:"show_api_id"`
+	ShowStatus    string             `json:"show_status"`
+	NextCheckAt   pgtype.Timestamptz `json:"next_check_at"`
+	LastCheckedAt pgtype.Timestamptz `json:"last_checked_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Stores the last season count each user was notified about and snoozed alerts
+type ShowNotificationState struct {
+	ID                  uuid.UUID          `json:"id"`
+	UserID              int64              `json:"user_id"`
+	ShowApiID           int64              `json:"show_api_id"`
+	LastNotifiedSeasons int32              `json:"last_notified_seasons"`
+	SnoozedUntil        pgtype.Timestamptz `json:"snoozed_until"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+}
+
 // Stores TV show information tracked by users
 type TvShow struct {
-	ID        uuid.UUID          `json:"id"`
-	UserID    int64              `json:"user_id"`
-	ApiID     int64              `json:"api_id"`
-	Name      string             `json:"name"`
-	Seasons   int32              `json:"seasons"`
-	Episodes  int32              `json:"episodes"`
-	Runtime   int32              `json:"runtime"`
-	Status    string             `json:"status"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	ID                   uuid.UUID          `json:"id"`
+	UserID               int64              `json:"user_id"`
+	ApiID                int64              `json:"api_id"`
+	Name                 string             `json:"name"`
+	Seasons              int32              `json:"seasons"`
+	Episodes             int32              `json:"episodes"`
+	Runtime              int32              `json:"runtime"`
+	Status               string             `json:"status"`
+	WatchStatus          string             `json:"watch_status"`
+	WatchStatusUpdatedAt pgtype.Timestamptz `json:"watch_status_updated_at"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt            pgtype.Timestamptz `json:"deleted_at"`
 }
 
 // Stores user information for authentication and preferences
@@ -49,17 +141,85 @@ type User struct {
 	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
 }
 
-// Stores shows and movies users want to watch
-type Watchlist struct {
+// Stores custom lists users create besides their watchlist
+type UserList struct {
 	ID        uuid.UUID          `json:"id"`
 	UserID    int64              `json:"user_id"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Stores the movies and shows on custom lists
+type UserListItem struct {
+	ID        uuid.UUID          `json:"id"`
+	ListID    uuid.UUID          `json:"list_id"`
 	ShowApiID int64              `json:"show_api_id"`
 	Type      string             `json:"type"`
 	Title     string             `json:"title"`
 	Image     *string            `json:"image"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+// Stores timezone, quiet hours, digest mode and notification toggles of users
+type UserSetting struct {
+	ID                     uuid.UUID          `json:"id"`
+	UserID                 int64              `json:"user_id"`
+	Timezone               string             `json:"timezone"`
+	QuietHoursStart        *int32             `json:"quiet_hours_start"`
+	QuietHoursEnd          *int32             `json:"quiet_hours_end"`
+	NotifyNewSeasons       bool               `json:"notify_new_seasons"`
+	NotifyStatusChanges    bool               `json:"notify_status_changes"`
+	NotifyEpisodeReminders bool               `json:"notify_episode_reminders"`
+	NotifyReleases         bool               `json:"notify_releases"`
+	NotifyDigest           bool               `json:"notify_digest"`
+	DigestMode             string             `json:"digest_mode"`
+	Region                 string             `json:"region"`
+	SubscribedProviders    []int32            `json:"subscribed_providers"`
+	NotifyAvailability     bool               `json:"notify_availability"`
+	CreatedAt              pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt              pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Stores every dated watch of a title, rewatches included
+type WatchEvent struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    int64              `json:"user_id"`
+	ApiID     int64              `json:"api_id"`
+	Type      string             `json:"type"`
+	Runtime   int32              `json:"runtime"`
+	WatchedOn pgtype.Date        `json:"watched_on"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+// Stores shows and movies users want to watch
+type Watchlist struct {
+	ID                 uuid.UUID          `json:"id"`
+	UserID             int64              `json:"user_id"`
+	ShowApiID          int64              `json:"show_api_id"`
+	Type               string             `json:"type"`
+	Title              string             `json:"title"`
+	Image              *string            `json:"image"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt          pgtype.Timestamptz `json:"deleted_at"`
+	ReleaseDate        pgtype.Date        `json:"release_date"`
+	ReleaseStatus      *string            `json:"release_status"`
+	AiredSeasons       *int32             `json:"aired_seasons"`
+	AvailableProviders []int32            `json:"available_providers"`
+	Priority           string             `json:"priority"`
+	Note               *string            `json:"note"`
+	Position           int32              `json:"position"`
+	Runtime            *int32             `json:"runtime"`
+}
+
+// Leases electing the single bot instance that runs each worker type
+type WorkerLease struct {
+	WorkerType string             `json:"worker_type"`
+	HolderID   string             `json:"holder_id"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
 }
 
 type WorkerPerformance struct {
@@ -72,10 +232,11 @@ type WorkerPerformance struct {
 	UpdatesFound      int32              `json:"updates_found"`
 	Error             *string            `json:"error"`
 	TotalTasks        int64              `json:"total_tasks"`
-	AvgTaskDurationMs float64            `json:"avg_task_duration_ms"`
-	MaxTaskDurationMs interface{}        `json:"max_task_duration_ms"`
+	AvgTaskDurationMs pgtype.Numeric     `json:"avg_task_duration_ms"`
+	MaxTaskDurationMs int64              `json:"max_task_duration_ms"`
 	ErrorTasks        int64              `json:"error_tasks"`
 	LastTaskTime      interface{}        `json:"last_task_time"`
+	Paused            bool               `json:"paused"`
 }
 
 type WorkerState struct {
@@ -88,22 +249,43 @@ type WorkerState struct {
 	Error         *string            `json:"error"`
 	ShowsChecked  int32              `json:"shows_checked"`
 	UpdatesFound  int32              `json:"updates_found"`
+	CycleCursor   *int64             `json:"cycle_cursor"`
+	Paused        bool               `json:"paused"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
 }
 
 type WorkerTask struct {
-	ID           uuid.UUID          `json:"id"`
-	WorkerID     string             `json:"worker_id"`
-	TaskType     string             `json:"task_type"`
-	Status       string             `json:"status"`
-	StartTime    pgtype.Timestamptz `json:"start_time"`
-	EndTime      pgtype.Timestamptz `json:"end_time"`
-	DurationMs   *int64             `json:"duration_ms"`
-	Error        *string            `json:"error"`
-	ShowID       *int64             `json:"show_id"`
-	UserID       *int64             `json:"user_id"`
-	ShowsChecked *int32             `json:"shows_checked"`
-	UpdatesFound *int32             `json:"updates_found"`
-	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	ID            uuid.UUID          `json:"id"`
+	WorkerID      string             `json:"worker_id"`
+	TaskType      string             `json:"task_type"`
+	Status        string             `json:"status"`
+	StartTime     pgtype.Timestamptz `json:"start_time"`
+	EndTime       pgtype.Timestamptz `json:"end_time"`
+	DurationMs    *int64             `json:"duration_ms"`
+	Error         *string            `json:"error"`
+	ShowID        *int64             `json:"show_id"`
+	UserID        *int64             `json:"user_id"`
+	ShowsChecked  *int32             `json:"shows_checked"`
+	UpdatesFound  *int32             `json:"updates_found"`
+	ApiCallsSaved *int32             `json:"api_calls_saved"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+// Daily aggregates of worker tasks removed by the retention job
+type WorkerTaskDailyStat struct {
+	WorkerID        string             `json:"worker_id"`
+	TaskType        string             `json:"task_type"`
+	Day             pgtype.Date        `json:"day"`
+	TaskCount       int32              `json:"task_count"`
+	ErrorCount      int32              `json:"error_count"`
+	TimedCount      int32              `json:"timed_count"`
+	TotalDurationMs int64              `json:"total_duration_ms"`
+	MaxDurationMs   *int64             `json:"max_duration_ms"`
+	ShowsChecked    int32              `json:"shows_checked"`
+	UpdatesFound    int32              `json:"updates_found"`
+	ApiCallsSaved   int32              `json:"api_calls_saved"`
+	LastTaskTime    pgtype.Timestamptz `json:"last_task_time"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
 }
@@ -12,6 +12,99 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const abortRunningWorkerTasks = `-- name: AbortRunningWorkerTasks :execrows
+UPDATE worker_tasks
+SET status   = 'aborted',
+    end_time = NOW(),
+    error    = $2
+WHERE status = 'running'
+  AND worker_id IN (SELECT worker_id FROM worker_states WHERE worker_type = $1)
+`
+
+type AbortRunningWorkerTasksParams struct {
+	WorkerType string  `json:"worker_type"`
+	Error      *string `json:"error"`
+}
+
+func (q *Queries) AbortRunningWorkerTasks(ctx context.Context, arg AbortRunningWorkerTasksParams) (int64, error) {
+	result, err := q.db.Exec(ctx, abortRunningWorkerTasks, arg.WorkerType, arg.Error)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const acquireWorkerLease = `-- name: AcquireWorkerLease :execrows
+INSERT INTO worker_leases (worker_type, holder_id, expires_at)
+VALUES ($1, $2, $3) ON CONFLICT (worker_type) DO
+UPDATE SET
+    holder_id = EXCLUDED.holder_id,
+    expires_at = EXCLUDED.expires_at
+WHERE worker_leases.holder_id = EXCLUDED.holder_id
+   OR worker_leases.expires_at < NOW()
+`
+
+type AcquireWorkerLeaseParams struct {
+	WorkerType string             `json:"worker_type"`
+	HolderID   string             `json:"holder_id"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) AcquireWorkerLease(ctx context.Context, arg AcquireWorkerLeaseParams) (int64, error) {
+	result, err := q.db.Exec(ctx, acquireWorkerLease, arg.WorkerType, arg.HolderID, arg.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const addListItem = `-- name: AddListItem :exec
+INSERT INTO user_list_items (list_id, show_api_id, type, title, image)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (list_id, show_api_id, type) DO NOTHING
+`
+
+type AddListItemParams struct {
+	ListID    uuid.UUID `json:"list_id"`
+	ShowApiID int64     `json:"show_api_id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Image     *string   `json:"image"`
+}
+
+func (q *Queries) AddListItem(ctx context.Context, arg AddListItemParams) error {
+	_, err := q.db.Exec(ctx, addListItem,
+		arg.ListID,
+		arg.ShowApiID,
+		arg.Type,
+		arg.Title,
+		arg.Image,
+	)
+	return err
+}
+
+const createEpisodeReminder = `-- name: CreateEpisodeReminder :exec
+INSERT INTO episode_reminders (user_id, show_api_id, season_number, episode_number)
+VALUES ($1, $2, $3, $4) ON CONFLICT (user_id, show_api_id, season_number, episode_number) DO NOTHING
+`
+
+type CreateEpisodeReminderParams struct {
+	UserID        int64 `json:"user_id"`
+	ShowApiID     int64 `json:"show_api_id"`
+	SeasonNumber  int32 `json:"season_number"`
+	EpisodeNumber int32 `json:"episode_number"`
+}
+
+func (q *Queries) CreateEpisodeReminder(ctx context.Context, arg CreateEpisodeReminderParams) error {
+	_, err := q.db.Exec(ctx, createEpisodeReminder,
+		arg.UserID,
+		arg.ShowApiID,
+		arg.SeasonNumber,
+		arg.EpisodeNumber,
+	)
+	return err
+}
+
 const createMovie = `-- name: CreateMovie :exec
 INSERT INTO movies (user_id, api_id, title, runtime)
 VALUES ($1, $2, $3, $4)
@@ -88,18 +181,61 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) error {
 	return err
 }
 
-const createWatchlist = `-- name: CreateWatchlist :exec
+const createUserList = `-- name: CreateUserList :one
+INSERT INTO user_lists (user_id, name)
+VALUES ($1, $2) RETURNING id
+`
+
+type CreateUserListParams struct {
+	UserID int64  `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+func (q *Queries) CreateUserList(ctx context.Context, arg CreateUserListParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createUserList, arg.UserID, arg.Name)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
 
-INSERT INTO watchlists (user_id, show_api_id, type, title, image)
+const createWatchEvent = `-- name: CreateWatchEvent :exec
+INSERT INTO watch_events (user_id, api_id, type, runtime, watched_on)
 VALUES ($1, $2, $3, $4, $5)
 `
 
+type CreateWatchEventParams struct {
+	UserID    int64       `json:"user_id"`
+	ApiID     int64       `json:"api_id"`
+	Type      string      `json:"type"`
+	Runtime   int32       `json:"runtime"`
+	WatchedOn pgtype.Date `json:"watched_on"`
+}
+
+func (q *Queries) CreateWatchEvent(ctx context.Context, arg CreateWatchEventParams) error {
+	_, err := q.db.Exec(ctx, createWatchEvent,
+		arg.UserID,
+		arg.ApiID,
+		arg.Type,
+		arg.Runtime,
+		arg.WatchedOn,
+	)
+	return err
+}
+
+const createWatchlist = `-- name: CreateWatchlist :exec
+
+INSERT INTO watchlists (user_id, show_api_id, type, title, image, runtime, position)
+VALUES ($1, $2, $3, $4, $5, $6,
+        (SELECT COALESCE(MAX(position), 0) + 1 FROM watchlists WHERE user_id = $1 AND deleted_at IS NULL))
+`
+
 type CreateWatchlistParams struct {
 	UserID    int64   `json:"user_id"`
 	ShowApiID int64   `json:"show_api_id"`
 	Type      string  `json:"type"`
 	Title     string  `json:"title"`
 	Image     *string `json:"image"`
+	Runtime   *int32  `json:"runtime"`
 }
 
 // Watchlists Table
@@ -110,6 +246,7 @@ func (q *Queries) CreateWatchlist(ctx context.Context, arg CreateWatchlistParams
 		arg.Type,
 		arg.Title,
 		arg.Image,
+		arg.Runtime,
 	)
 	return err
 }
@@ -155,71 +292,57 @@ func (q *Queries) CreateWorkerTask(ctx context.Context, arg CreateWorkerTaskPara
 	return id, err
 }
 
-const deleteWatchlist = `-- name: DeleteWatchlist :exec
-UPDATE watchlists
-SET deleted_at = NOW()
-WHERE show_api_id = $1
-  AND user_id = $2
-  AND deleted_at IS NULL
+const deferNotification = `-- name: DeferNotification :exec
+UPDATE notifications
+SET next_attempt_at = $2
+WHERE id = $1
 `
 
-type DeleteWatchlistParams struct {
-	ShowApiID int64 `json:"show_api_id"`
-	UserID    int64 `json:"user_id"`
+type DeferNotificationParams struct {
+	ID            uuid.UUID          `json:"id"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
 }
 
-func (q *Queries) DeleteWatchlist(ctx context.Context, arg DeleteWatchlistParams) error {
-	_, err := q.db.Exec(ctx, deleteWatchlist, arg.ShowApiID, arg.UserID)
+func (q *Queries) DeferNotification(ctx context.Context, arg DeferNotificationParams) error {
+	_, err := q.db.Exec(ctx, deferNotification, arg.ID, arg.NextAttemptAt)
 	return err
 }
 
-const getRecentTasks = `-- name: GetRecentTasks :many
-SELECT id,
-       worker_id,
-       task_type,
-       status,
-       start_time,
-       end_time,
-       duration_ms,
-       error,
-       show_id,
-       user_id,
-       shows_checked,
-       updates_found,
-       created_at
-FROM worker_tasks
-WHERE worker_id = $1
-ORDER BY created_at DESC LIMIT $2
+const deleteEpisodeWatchesAfter = `-- name: DeleteEpisodeWatchesAfter :many
+DELETE
+FROM episode_watches
+WHERE user_id = $1
+  AND show_api_id = $2
+  AND season_number > $3 RETURNING season_number, episode_number, runtime, watched_at
 `
 
-type GetRecentTasksParams struct {
-	WorkerID string `json:"worker_id"`
-	Limit    int32  `json:"limit"`
+type DeleteEpisodeWatchesAfterParams struct {
+	UserID       int64 `json:"user_id"`
+	ShowApiID    int64 `json:"show_api_id"`
+	SeasonNumber int32 `json:"season_number"`
 }
 
-func (q *Queries) GetRecentTasks(ctx context.Context, arg GetRecentTasksParams) ([]WorkerTask, error) {
-	rows, err := q.db.Query(ctx, getRecentTasks, arg.WorkerID, arg.Limit)
+type DeleteEpisodeWatchesAfterRow struct {
+	SeasonNumber  int32              `json:"season_number"`
+	EpisodeNumber int32              `json:"episode_number"`
+	Runtime       int32              `json:"runtime"`
+	WatchedAt     pgtype.Timestamptz `json:"watched_at"`
+}
+
+func (q *Queries) DeleteEpisodeWatchesAfter(ctx context.Context, arg DeleteEpisodeWatchesAfterParams) ([]DeleteEpisodeWatchesAfterRow, error) {
+	rows, err := q.db.Query(ctx, deleteEpisodeWatchesAfter, arg.UserID, arg.ShowApiID, arg.SeasonNumber)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkerTask
+	var items []DeleteEpisodeWatchesAfterRow
 	for rows.Next() {
-		var i WorkerTask
+		var i DeleteEpisodeWatchesAfterRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.WorkerID,
-			&i.TaskType,
-			&i.Status,
-			&i.StartTime,
-			&i.EndTime,
-			&i.DurationMs,
-			&i.Error,
-			&i.ShowID,
-			&i.UserID,
-			&i.ShowsChecked,
-			&i.UpdatesFound,
-			&i.CreatedAt,
+			&i.SeasonNumber,
+			&i.EpisodeNumber,
+			&i.Runtime,
+			&i.WatchedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -231,66 +354,36 @@ func (q *Queries) GetRecentTasks(ctx context.Context, arg GetRecentTasksParams)
 	return items, nil
 }
 
-const getUser = `-- name: GetUser :one
-
-SELECT id, tg_id, first_name, last_name, username, language, tmdb_api_key, created_at, updated_at
-FROM users
-WHERE tg_id = $1 LIMIT 1
+const deleteTitleRatings = `-- name: DeleteTitleRatings :many
+DELETE
+FROM ratings
+WHERE user_id = $1
+  AND api_id = $2
+  AND type = $3 RETURNING season_number, rating, review
 `
 
-// Users Table
-func (q *Queries) GetUser(ctx context.Context, tgID int64) (User, error) {
-	row := q.db.QueryRow(ctx, getUser, tgID)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.TgID,
-		&i.FirstName,
-		&i.LastName,
-		&i.Username,
-		&i.Language,
-		&i.TmdbApiKey,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+type DeleteTitleRatingsParams struct {
+	UserID int64  `json:"user_id"`
+	ApiID  int64  `json:"api_id"`
+	Type   string `json:"type"`
 }
 
-const getUserMovies = `-- name: GetUserMovies :many
-
-SELECT id, api_id, title, runtime, created_at, updated_at
-FROM movies
-WHERE user_id = $1
-  AND deleted_at IS NULL
-`
-
-type GetUserMoviesRow struct {
-	ID        uuid.UUID          `json:"id"`
-	ApiID     int64              `json:"api_id"`
-	Title     string             `json:"title"`
-	Runtime   int32              `json:"runtime"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+type DeleteTitleRatingsRow struct {
+	SeasonNumber int32   `json:"season_number"`
+	Rating       int16   `json:"rating"`
+	Review       *string `json:"review"`
 }
 
-// Movies Table
-func (q *Queries) GetUserMovies(ctx context.Context, userID int64) ([]GetUserMoviesRow, error) {
-	rows, err := q.db.Query(ctx, getUserMovies, userID)
+func (q *Queries) DeleteTitleRatings(ctx context.Context, arg DeleteTitleRatingsParams) ([]DeleteTitleRatingsRow, error) {
+	rows, err := q.db.Query(ctx, deleteTitleRatings, arg.UserID, arg.ApiID, arg.Type)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUserMoviesRow
+	var items []DeleteTitleRatingsRow
 	for rows.Next() {
-		var i GetUserMoviesRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.ApiID,
-			&i.Title,
-			&i.Runtime,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
+		var i DeleteTitleRatingsRow
+		if err := rows.Scan(&i.SeasonNumber, &i.Rating, &i.Review); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -301,116 +394,56 @@ func (q *Queries) GetUserMovies(ctx context.Context, userID int64) ([]GetUserMov
 	return items, nil
 }
 
-const getUserTMDBKey = `-- name: GetUserTMDBKey :one
-SELECT tmdb_api_key
-FROM users
-WHERE tg_id = $1 LIMIT 1
-`
-
-func (q *Queries) GetUserTMDBKey(ctx context.Context, tgID int64) (*string, error) {
-	row := q.db.QueryRow(ctx, getUserTMDBKey, tgID)
-	var tmdb_api_key *string
-	err := row.Scan(&tmdb_api_key)
-	return tmdb_api_key, err
-}
-
-const getUserTVShow = `-- name: GetUserTVShow :one
-SELECT id,
-       api_id,
-       name,
-       seasons,
-       episodes,
-       runtime,
-       status,
-       created_at,
-       updated_at
-FROM tv_shows
-WHERE api_id = $1 AND user_id = $2 AND deleted_at IS NULL
+const deleteUserList = `-- name: DeleteUserList :execrows
+DELETE
+FROM user_lists
+WHERE id = $1
+  AND user_id = $2
 `
 
-type GetUserTVShowParams struct {
-	ApiID  int64 `json:"api_id"`
-	UserID int64 `json:"user_id"`
-}
-
-type GetUserTVShowRow struct {
-	ID        uuid.UUID          `json:"id"`
-	ApiID     int64              `json:"api_id"`
-	Name      string             `json:"name"`
-	Seasons   int32              `json:"seasons"`
-	Episodes  int32              `json:"episodes"`
-	Runtime   int32              `json:"runtime"`
-	Status    string             `json:"status"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+type DeleteUserListParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID int64     `json:"user_id"`
 }
 
-func (q *Queries) GetUserTVShow(ctx context.Context, arg GetUserTVShowParams) (GetUserTVShowRow, error) {
-	row := q.db.QueryRow(ctx, getUserTVShow, arg.ApiID, arg.UserID)
-	var i GetUserTVShowRow
-	err := row.Scan(
-		&i.ID,
-		&i.ApiID,
-		&i.Name,
-		&i.Seasons,
-		&i.Episodes,
-		&i.Runtime,
-		&i.Status,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+func (q *Queries) DeleteUserList(ctx context.Context, arg DeleteUserListParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUserList, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const getUserTVShows = `-- name: GetUserTVShows :many
-
-SELECT id,
-       api_id,
-       name,
-       seasons,
-       episodes,
-       runtime,
-       status,
-       created_at,
-       updated_at
-FROM tv_shows
+const deleteWatchEvents = `-- name: DeleteWatchEvents :many
+DELETE
+FROM watch_events
 WHERE user_id = $1
-  AND deleted_at IS NULL
+  AND api_id = $2
+  AND type = $3 RETURNING runtime, watched_on, created_at
 `
 
-type GetUserTVShowsRow struct {
-	ID        uuid.UUID          `json:"id"`
-	ApiID     int64              `json:"api_id"`
-	Name      string             `json:"name"`
-	Seasons   int32              `json:"seasons"`
-	Episodes  int32              `json:"episodes"`
+type DeleteWatchEventsParams struct {
+	UserID int64  `json:"user_id"`
+	ApiID  int64  `json:"api_id"`
+	Type   string `json:"type"`
+}
+
+type DeleteWatchEventsRow struct {
 	Runtime   int32              `json:"runtime"`
-	Status    string             `json:"status"`
+	WatchedOn pgtype.Date        `json:"watched_on"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
 
-// TV Shows Table
-func (q *Queries) GetUserTVShows(ctx context.Context, userID int64) ([]GetUserTVShowsRow, error) {
-	rows, err := q.db.Query(ctx, getUserTVShows, userID)
+func (q *Queries) DeleteWatchEvents(ctx context.Context, arg DeleteWatchEventsParams) ([]DeleteWatchEventsRow, error) {
+	rows, err := q.db.Query(ctx, deleteWatchEvents, arg.UserID, arg.ApiID, arg.Type)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUserTVShowsRow
+	var items []DeleteWatchEventsRow
 	for rows.Next() {
-		var i GetUserTVShowsRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.ApiID,
-			&i.Name,
-			&i.Seasons,
-			&i.Episodes,
-			&i.Runtime,
-			&i.Status,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
+		var i DeleteWatchEventsRow
+		if err := rows.Scan(&i.Runtime, &i.WatchedOn, &i.CreatedAt); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -421,87 +454,101 @@ func (q *Queries) GetUserTVShows(ctx context.Context, userID int64) ([]GetUserTV
 	return items, nil
 }
 
-const getUserWatchlist = `-- name: GetUserWatchlist :one
-SELECT id,
-       user_id,
-       show_api_id,
-       type,
-       title,
-       image,
-       created_at,
-       updated_at
-FROM watchlists
+const deleteWatchlist = `-- name: DeleteWatchlist :exec
+UPDATE watchlists
+SET deleted_at = NOW()
 WHERE show_api_id = $1
   AND user_id = $2
-  AND deleted_at IS NULL LIMIT 1
+  AND type = $3
+  AND deleted_at IS NULL
 `
 
-type GetUserWatchlistParams struct {
-	ShowApiID int64 `json:"show_api_id"`
-	UserID    int64 `json:"user_id"`
+type DeleteWatchlistParams struct {
+	ShowApiID int64  `json:"show_api_id"`
+	UserID    int64  `json:"user_id"`
+	Type      string `json:"type"`
 }
 
-type GetUserWatchlistRow struct {
-	ID        uuid.UUID          `json:"id"`
-	UserID    int64              `json:"user_id"`
-	ShowApiID int64              `json:"show_api_id"`
-	Type      string             `json:"type"`
-	Title     string             `json:"title"`
-	Image     *string            `json:"image"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+func (q *Queries) DeleteWatchlist(ctx context.Context, arg DeleteWatchlistParams) error {
+	_, err := q.db.Exec(ctx, deleteWatchlist, arg.ShowApiID, arg.UserID, arg.Type)
+	return err
 }
 
-func (q *Queries) GetUserWatchlist(ctx context.Context, arg GetUserWatchlistParams) (GetUserWatchlistRow, error) {
-	row := q.db.QueryRow(ctx, getUserWatchlist, arg.ShowApiID, arg.UserID)
-	var i GetUserWatchlistRow
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.ShowApiID,
-		&i.Type,
-		&i.Title,
-		&i.Image,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+const enqueueNotification = `-- name: EnqueueNotification :one
+
+INSERT INTO notifications (user_id, kind, show_api_id, text, image_path, reply_markup)
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING id
+`
+
+type EnqueueNotificationParams struct {
+	UserID      int64   `json:"user_id"`
+	Kind        string  `json:"kind"`
+	ShowApiID   *int64  `json:"show_api_id"`
+	Text        string  `json:"text"`
+	ImagePath   *string `json:"image_path"`
+	ReplyMarkup []byte  `json:"reply_markup"`
+}
+
+// Notifications
+func (q *Queries) EnqueueNotification(ctx context.Context, arg EnqueueNotificationParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, enqueueNotification,
+		arg.UserID,
+		arg.Kind,
+		arg.ShowApiID,
+		arg.Text,
+		arg.ImagePath,
+		arg.ReplyMarkup,
 	)
-	return i, err
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
 }
 
-const getUserWatchlists = `-- name: GetUserWatchlists :many
-SELECT id, show_api_id, type, title, image, created_at, updated_at
-FROM watchlists
-WHERE user_id = $1
-  AND deleted_at IS NULL
+const getDueEpisodeReminders = `-- name: GetDueEpisodeReminders :many
+SELECT s.show_api_id,
+       s.show_name,
+       s.season_number,
+       s.episode_number,
+       s.episode_name,
+       s.air_date,
+       t.user_id
+FROM episode_schedules s
+         JOIN tv_shows t ON t.api_id = s.show_api_id AND t.deleted_at IS NULL AND t.watch_status <> 'DROPPED'
+         LEFT JOIN episode_reminders r ON r.user_id = t.user_id
+    AND r.show_api_id = s.show_api_id
+    AND r.season_number = s.season_number
+    AND r.episode_number = s.episode_number
+WHERE s.air_date = $1
+  AND r.id IS NULL
 `
 
-type GetUserWatchlistsRow struct {
-	ID        uuid.UUID          `json:"id"`
-	ShowApiID int64              `json:"show_api_id"`
-	Type      string             `json:"type"`
-	Title     string             `json:"title"`
-	Image     *string            `json:"image"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+type GetDueEpisodeRemindersRow struct {
+	ShowApiID     int64       `json:"show_api_id"`
+	ShowName      string      `json:"show_name"`
+	SeasonNumber  int32       `json:"season_number"`
+	EpisodeNumber int32       `json:"episode_number"`
+	EpisodeName   *string     `json:"episode_name"`
+	AirDate       pgtype.Date `json:"air_date"`
+	UserID        int64       `json:"user_id"`
 }
 
-func (q *Queries) GetUserWatchlists(ctx context.Context, userID int64) ([]GetUserWatchlistsRow, error) {
-	rows, err := q.db.Query(ctx, getUserWatchlists, userID)
+func (q *Queries) GetDueEpisodeReminders(ctx context.Context, airDate pgtype.Date) ([]GetDueEpisodeRemindersRow, error) {
+	rows, err := q.db.Query(ctx, getDueEpisodeReminders, airDate)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUserWatchlistsRow
+	var items []GetDueEpisodeRemindersRow
 	for rows.Next() {
-		var i GetUserWatchlistsRow
+		var i GetDueEpisodeRemindersRow
 		if err := rows.Scan(
-			&i.ID,
 			&i.ShowApiID,
-			&i.Type,
-			&i.Title,
-			&i.Image,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.ShowName,
+			&i.SeasonNumber,
+			&i.EpisodeNumber,
+			&i.EpisodeName,
+			&i.AirDate,
+			&i.UserID,
 		); err != nil {
 			return nil, err
 		}
@@ -513,46 +560,45 @@ func (q *Queries) GetUserWatchlists(ctx context.Context, userID int64) ([]GetUse
 	return items, nil
 }
 
-const getUserWatchlistsWithType = `-- name: GetUserWatchlistsWithType :many
-SELECT id, show_api_id, type, title, image, created_at, updated_at
-FROM watchlists
-WHERE user_id = $1
-  AND type = $2
-  AND deleted_at IS NULL
+const getDueNotifications = `-- name: GetDueNotifications :many
+SELECT id, user_id, kind, show_api_id, text, image_path, reply_markup, attempts, poster_sent
+FROM notifications
+WHERE status = 'pending'
+  AND next_attempt_at <= NOW()
+ORDER BY next_attempt_at LIMIT $1
 `
 
-type GetUserWatchlistsWithTypeParams struct {
-	UserID int64  `json:"user_id"`
-	Type   string `json:"type"`
-}
-
-type GetUserWatchlistsWithTypeRow struct {
-	ID        uuid.UUID          `json:"id"`
-	ShowApiID int64              `json:"show_api_id"`
-	Type      string             `json:"type"`
-	Title     string             `json:"title"`
-	Image     *string            `json:"image"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+type GetDueNotificationsRow struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      int64     `json:"user_id"`
+	Kind        string    `json:"kind"`
+	ShowApiID   *int64    `json:"show_api_id"`
+	Text        string    `json:"text"`
+	ImagePath   *string   `json:"image_path"`
+	ReplyMarkup []byte    `json:"reply_markup"`
+	Attempts    int32     `json:"attempts"`
+	PosterSent  bool      `json:"poster_sent"`
 }
 
-func (q *Queries) GetUserWatchlistsWithType(ctx context.Context, arg GetUserWatchlistsWithTypeParams) ([]GetUserWatchlistsWithTypeRow, error) {
-	rows, err := q.db.Query(ctx, getUserWatchlistsWithType, arg.UserID, arg.Type)
+func (q *Queries) GetDueNotifications(ctx context.Context, limit int32) ([]GetDueNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, getDueNotifications, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUserWatchlistsWithTypeRow
+	var items []GetDueNotificationsRow
 	for rows.Next() {
-		var i GetUserWatchlistsWithTypeRow
+		var i GetDueNotificationsRow
 		if err := rows.Scan(
 			&i.ID,
+			&i.UserID,
+			&i.Kind,
 			&i.ShowApiID,
-			&i.Type,
-			&i.Title,
-			&i.Image,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.Text,
+			&i.ImagePath,
+			&i.ReplyMarkup,
+			&i.Attempts,
+			&i.PosterSent,
 		); err != nil {
 			return nil, err
 		}
@@ -564,40 +610,153 @@ func (q *Queries) GetUserWatchlistsWithType(ctx context.Context, arg GetUserWatc
 	return items, nil
 }
 
-const getUsers = `-- name: GetUsers :many
-SELECT id, tg_id, first_name, last_name, username, language, created_at, updated_at
-FROM users
+const getEpisodeSchedule = `-- name: GetEpisodeSchedule :one
+SELECT id,
+       show_api_id,
+       show_name,
+       season_number,
+       episode_number,
+       episode_name,
+       air_date,
+       runtime,
+       created_at,
+       updated_at
+FROM episode_schedules
+WHERE show_api_id = $1
+  AND season_number = $2
+  AND episode_number = $3
 `
 
-type GetUsersRow struct {
-	ID        uuid.UUID          `json:"id"`
-	TgID      int64              `json:"tg_id"`
-	FirstName *string            `json:"first_name"`
-	LastName  *string            `json:"last_name"`
-	Username  *string            `json:"username"`
-	Language  string             `json:"language"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+type GetEpisodeScheduleParams struct {
+	ShowApiID     int64 `json:"show_api_id"`
+	SeasonNumber  int32 `json:"season_number"`
+	EpisodeNumber int32 `json:"episode_number"`
 }
 
-func (q *Queries) GetUsers(ctx context.Context) ([]GetUsersRow, error) {
-	rows, err := q.db.Query(ctx, getUsers)
+func (q *Queries) GetEpisodeSchedule(ctx context.Context, arg GetEpisodeScheduleParams) (EpisodeSchedule, error) {
+	row := q.db.QueryRow(ctx, getEpisodeSchedule, arg.ShowApiID, arg.SeasonNumber, arg.EpisodeNumber)
+	var i EpisodeSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.ShowApiID,
+		&i.ShowName,
+		&i.SeasonNumber,
+		&i.EpisodeNumber,
+		&i.EpisodeName,
+		&i.AirDate,
+		&i.Runtime,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getEpisodeWatchTotals = `-- name: GetEpisodeWatchTotals :one
+SELECT COUNT(*)::INT                AS episodes,
+       COALESCE(SUM(runtime), 0)::INT AS runtime
+FROM episode_watches
+WHERE user_id = $1
+  AND show_api_id = $2
+`
+
+type GetEpisodeWatchTotalsParams struct {
+	UserID    int64 `json:"user_id"`
+	ShowApiID int64 `json:"show_api_id"`
+}
+
+type GetEpisodeWatchTotalsRow struct {
+	Episodes int32 `json:"episodes"`
+	Runtime  int32 `json:"runtime"`
+}
+
+func (q *Queries) GetEpisodeWatchTotals(ctx context.Context, arg GetEpisodeWatchTotalsParams) (GetEpisodeWatchTotalsRow, error) {
+	row := q.db.QueryRow(ctx, getEpisodeWatchTotals, arg.UserID, arg.ShowApiID)
+	var i GetEpisodeWatchTotalsRow
+	err := row.Scan(&i.Episodes, &i.Runtime)
+	return i, err
+}
+
+const getHeldNotificationUsers = `-- name: GetHeldNotificationUsers :many
+SELECT user_id, MIN(created_at)::TIMESTAMPTZ AS oldest_at
+FROM notifications
+WHERE status = 'held'
+GROUP BY user_id
+`
+
+type GetHeldNotificationUsersRow struct {
+	UserID   int64              `json:"user_id"`
+	OldestAt pgtype.Timestamptz `json:"oldest_at"`
+}
+
+func (q *Queries) GetHeldNotificationUsers(ctx context.Context) ([]GetHeldNotificationUsersRow, error) {
+	rows, err := q.db.Query(ctx, getHeldNotificationUsers)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUsersRow
+	var items []GetHeldNotificationUsersRow
 	for rows.Next() {
-		var i GetUsersRow
+		var i GetHeldNotificationUsersRow
+		if err := rows.Scan(&i.UserID, &i.OldestAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHeldNotifications = `-- name: GetHeldNotifications :many
+SELECT n.id,
+       n.kind,
+       n.show_api_id,
+       n.text,
+       COALESCE(n.show_name,
+                (SELECT t.name
+                 FROM tv_shows t
+                 WHERE t.user_id = n.user_id
+                   AND t.api_id = n.show_api_id
+                 ORDER BY t.deleted_at DESC NULLS FIRST LIMIT 1),
+                (SELECT w.title
+                 FROM watchlists w
+                 WHERE w.user_id = n.user_id
+                   AND w.show_api_id = n.show_api_id
+                   AND w.type = 'TV_SHOW'
+                 ORDER BY w.deleted_at DESC NULLS FIRST LIMIT 1),
+                (SELECT e.show_name
+                 FROM episode_schedules e
+                 WHERE e.show_api_id = n.show_api_id LIMIT 1), '')::TEXT AS show_name
+FROM notifications n
+WHERE n.user_id = $1
+  AND n.status = 'held'
+ORDER BY n.created_at
+`
+
+type GetHeldNotificationsRow struct {
+	ID        uuid.UUID `json:"id"`
+	Kind      string    `json:"kind"`
+	ShowApiID *int64    `json:"show_api_id"`
+	Text      string    `json:"text"`
+	ShowName  string    `json:"show_name"`
+}
+
+func (q *Queries) GetHeldNotifications(ctx context.Context, userID int64) ([]GetHeldNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, getHeldNotifications, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetHeldNotificationsRow
+	for rows.Next() {
+		var i GetHeldNotificationsRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.TgID,
-			&i.FirstName,
-			&i.LastName,
-			&i.Username,
-			&i.Language,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.Kind,
+			&i.ShowApiID,
+			&i.Text,
+			&i.ShowName,
 		); err != nil {
 			return nil, err
 		}
@@ -609,28 +768,26 @@ func (q *Queries) GetUsers(ctx context.Context) ([]GetUsersRow, error) {
 	return items, nil
 }
 
-const getWatchedSeasons = `-- name: GetWatchedSeasons :one
-SELECT seasons
-FROM tv_shows
-WHERE api_id = $1
-  AND user_id = $2
-  AND deleted_at IS NULL
+const getLastScheduledEpisode = `-- name: GetLastScheduledEpisode :one
+SELECT COALESCE(MAX(episode_number), 0)::INT AS episode_number
+FROM episode_schedules
+WHERE show_api_id = $1
+  AND season_number = $2
 `
 
-type GetWatchedSeasonsParams struct {
-	ApiID  int64 `json:"api_id"`
-	UserID int64 `json:"user_id"`
+type GetLastScheduledEpisodeParams struct {
+	ShowApiID    int64 `json:"show_api_id"`
+	SeasonNumber int32 `json:"season_number"`
 }
 
-func (q *Queries) GetWatchedSeasons(ctx context.Context, arg GetWatchedSeasonsParams) (int32, error) {
-	row := q.db.QueryRow(ctx, getWatchedSeasons, arg.ApiID, arg.UserID)
-	var seasons int32
-	err := row.Scan(&seasons)
-	return seasons, err
+func (q *Queries) GetLastScheduledEpisode(ctx context.Context, arg GetLastScheduledEpisodeParams) (int32, error) {
+	row := q.db.QueryRow(ctx, getLastScheduledEpisode, arg.ShowApiID, arg.SeasonNumber)
+	var episode_number int32
+	err := row.Scan(&episode_number)
+	return episode_number, err
 }
 
-const getWorkerState = `-- name: GetWorkerState :one
-
+const getLatestWorkerState = `-- name: GetLatestWorkerState :one
 SELECT id,
        worker_id,
        worker_type,
@@ -640,16 +797,17 @@ SELECT id,
        error,
        shows_checked,
        updates_found,
+       cycle_cursor,
+       paused,
        created_at,
        updated_at
 FROM worker_states
-WHERE worker_id = $1
+WHERE worker_type = $1
 ORDER BY updated_at DESC LIMIT 1
 `
 
-// Workers Related
-func (q *Queries) GetWorkerState(ctx context.Context, workerID string) (WorkerState, error) {
-	row := q.db.QueryRow(ctx, getWorkerState, workerID)
+func (q *Queries) GetLatestWorkerState(ctx context.Context, workerType string) (WorkerState, error) {
+	row := q.db.QueryRow(ctx, getLatestWorkerState, workerType)
 	var i WorkerState
 	err := row.Scan(
 		&i.ID,
@@ -661,65 +819,1801 @@ func (q *Queries) GetWorkerState(ctx context.Context, workerID string) (WorkerSt
 		&i.Error,
 		&i.ShowsChecked,
 		&i.UpdatesFound,
+		&i.CycleCursor,
+		&i.Paused,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getWorkerTask = `-- name: GetWorkerTask :one
-SELECT id,
-       worker_id,
-       task_type,
-       status,
-       start_time,
-       end_time,
-       duration_ms,
-       error,
-       show_id,
-       user_id,
-       shows_checked,
-       updates_found,
-       created_at
-FROM worker_tasks
-WHERE id = $1
+const getListItems = `-- name: GetListItems :many
+SELECT id, show_api_id, type, title, image, created_at
+FROM user_list_items
+WHERE list_id = $1
+ORDER BY created_at
+`
+
+type GetListItemsRow struct {
+	ID        uuid.UUID          `json:"id"`
+	ShowApiID int64              `json:"show_api_id"`
+	Type      string             `json:"type"`
+	Title     string             `json:"title"`
+	Image     *string            `json:"image"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) GetListItems(ctx context.Context, listID uuid.UUID) ([]GetListItemsRow, error) {
+	rows, err := q.db.Query(ctx, getListItems, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetListItemsRow
+	for rows.Next() {
+		var i GetListItemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShowApiID,
+			&i.Type,
+			&i.Title,
+			&i.Image,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getListsWithTitle = `-- name: GetListsWithTitle :many
+SELECT l.id,
+       l.name,
+       EXISTS(SELECT 1
+              FROM user_list_items i
+              WHERE i.list_id = l.id
+                AND i.show_api_id = $2
+                AND i.type = $3) AS has_title
+FROM user_lists l
+WHERE l.user_id = $1
+ORDER BY l.created_at
+`
+
+type GetListsWithTitleParams struct {
+	UserID    int64  `json:"user_id"`
+	ShowApiID int64  `json:"show_api_id"`
+	Type      string `json:"type"`
+}
+
+type GetListsWithTitleRow struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	HasTitle bool      `json:"has_title"`
+}
+
+func (q *Queries) GetListsWithTitle(ctx context.Context, arg GetListsWithTitleParams) ([]GetListsWithTitleRow, error) {
+	rows, err := q.db.Query(ctx, getListsWithTitle, arg.UserID, arg.ShowApiID, arg.Type)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetListsWithTitleRow
+	for rows.Next() {
+		var i GetListsWithTitleRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.HasTitle); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRatingSummary = `-- name: GetRatingSummary :one
+SELECT COUNT(*)::INT                      AS rated,
+       COALESCE(AVG(rating), 0)::FLOAT8 AS average
+FROM ratings
+WHERE user_id = $1
+  AND (type = $2 OR $2 = 'ALL')
+  AND season_number = 0
+`
+
+type GetRatingSummaryParams struct {
+	UserID int64  `json:"user_id"`
+	Type   string `json:"type"`
+}
+
+type GetRatingSummaryRow struct {
+	Rated   int32   `json:"rated"`
+	Average float64 `json:"average"`
+}
+
+func (q *Queries) GetRatingSummary(ctx context.Context, arg GetRatingSummaryParams) (GetRatingSummaryRow, error) {
+	row := q.db.QueryRow(ctx, getRatingSummary, arg.UserID, arg.Type)
+	var i GetRatingSummaryRow
+	err := row.Scan(&i.Rated, &i.Average)
+	return i, err
+}
+
+const getRecentTasks = `-- name: GetRecentTasks :many
+SELECT id,
+       worker_id,
+       task_type,
+       status,
+       start_time,
+       end_time,
+       duration_ms,
+       error,
+       show_id,
+       user_id,
+       shows_checked,
+       updates_found,
+       api_calls_saved,
+       created_at
+FROM worker_tasks
+WHERE worker_id = $1
+ORDER BY created_at DESC LIMIT $2
+`
+
+type GetRecentTasksParams struct {
+	WorkerID string `json:"worker_id"`
+	Limit    int32  `json:"limit"`
+}
+
+func (q *Queries) GetRecentTasks(ctx context.Context, arg GetRecentTasksParams) ([]WorkerTask, error) {
+	rows, err := q.db.Query(ctx, getRecentTasks, arg.WorkerID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkerTask
+	for rows.Next() {
+		var i WorkerTask
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkerID,
+			&i.TaskType,
+			&i.Status,
+			&i.StartTime,
+			&i.EndTime,
+			&i.DurationMs,
+			&i.Error,
+			&i.ShowID,
+			&i.UserID,
+			&i.ShowsChecked,
+			&i.UpdatesFound,
+			&i.ApiCallsSaved,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getShowCheckSchedules = `-- name: GetShowCheckSchedules :many
+SELECT show_api_id, next_check_at
+FROM show_check_schedules
+`
+
+type GetShowCheckSchedulesRow struct {
+	ShowApiID   int64              `json:"show_api_id"`
+	NextCheckAt pgtype.Timestamptz `json:"next_check_at"`
+}
+
+func (q *Queries) GetShowCheckSchedules(ctx context.Context) ([]GetShowCheckSchedulesRow, error) {
+	rows, err := q.db.Query(ctx, getShowCheckSchedules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetShowCheckSchedulesRow
+	for rows.Next() {
+		var i GetShowCheckSchedulesRow
+		if err := rows.Scan(&i.ShowApiID, &i.NextCheckAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getShowNotificationState = `-- name: GetShowNotificationState :one
+SELECT last_notified_seasons, snoozed_until
+FROM show_notification_states
+WHERE user_id = $1
+  AND show_api_id = $2
+`
+
+type GetShowNotificationStateParams struct {
+	UserID    int64 `json:"user_id"`
+	ShowApiID int64 `json:"show_api_id"`
+}
+
+type GetShowNotificationStateRow struct {
+	LastNotifiedSeasons int32              `json:"last_notified_seasons"`
+	SnoozedUntil        pgtype.Timestamptz `json:"snoozed_until"`
+}
+
+func (q *Queries) GetShowNotificationState(ctx context.Context, arg GetShowNotificationStateParams) (GetShowNotificationStateRow, error) {
+	row := q.db.QueryRow(ctx, getShowNotificationState, arg.UserID, arg.ShowApiID)
+	var i GetShowNotificationStateRow
+	err := row.Scan(&i.LastNotifiedSeasons, &i.SnoozedUntil)
+	return i, err
+}
+
+const getTitleRatings = `-- name: GetTitleRatings :many
+SELECT season_number, rating, review
+FROM ratings
+WHERE user_id = $1
+  AND api_id = $2
+  AND type = $3
+ORDER BY season_number
+`
+
+type GetTitleRatingsParams struct {
+	UserID int64  `json:"user_id"`
+	ApiID  int64  `json:"api_id"`
+	Type   string `json:"type"`
+}
+
+type GetTitleRatingsRow struct {
+	SeasonNumber int32   `json:"season_number"`
+	Rating       int16   `json:"rating"`
+	Review       *string `json:"review"`
+}
+
+func (q *Queries) GetTitleRatings(ctx context.Context, arg GetTitleRatingsParams) ([]GetTitleRatingsRow, error) {
+	rows, err := q.db.Query(ctx, getTitleRatings, arg.UserID, arg.ApiID, arg.Type)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTitleRatingsRow
+	for rows.Next() {
+		var i GetTitleRatingsRow
+		if err := rows.Scan(&i.SeasonNumber, &i.Rating, &i.Review); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTopRated = `-- name: GetTopRated :many
+SELECT r.api_id,
+       r.type,
+       r.rating,
+       COALESCE(m.title, t.name, '')::TEXT AS title
+FROM ratings r
+         LEFT JOIN movies m ON r.type = 'MOVIE' AND m.user_id = r.user_id AND m.api_id = r.api_id AND m.deleted_at IS NULL
+         LEFT JOIN tv_shows t ON r.type = 'TV_SHOW' AND t.user_id = r.user_id AND t.api_id = r.api_id AND t.deleted_at IS NULL
+WHERE r.user_id = $1
+  AND (r.type = $2 OR $2 = 'ALL')
+  AND r.season_number = 0
+ORDER BY r.rating DESC, r.updated_at DESC LIMIT $3
+`
+
+type GetTopRatedParams struct {
+	UserID int64  `json:"user_id"`
+	Type   string `json:"type"`
+	Limit  int32  `json:"limit"`
+}
+
+type GetTopRatedRow struct {
+	ApiID  int64  `json:"api_id"`
+	Type   string `json:"type"`
+	Rating int16  `json:"rating"`
+	Title  string `json:"title"`
+}
+
+func (q *Queries) GetTopRated(ctx context.Context, arg GetTopRatedParams) ([]GetTopRatedRow, error) {
+	rows, err := q.db.Query(ctx, getTopRated, arg.UserID, arg.Type, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTopRatedRow
+	for rows.Next() {
+		var i GetTopRatedRow
+		if err := rows.Scan(
+			&i.ApiID,
+			&i.Type,
+			&i.Rating,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUser = `-- name: GetUser :one
+
+SELECT id, tg_id, first_name, last_name, username, language, tmdb_api_key, created_at, updated_at
+FROM users
+WHERE tg_id = $1 LIMIT 1
+`
+
+// Users Table
+func (q *Queries) GetUser(ctx context.Context, tgID int64) (User, error) {
+	row := q.db.QueryRow(ctx, getUser, tgID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.TgID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Username,
+		&i.Language,
+		&i.TmdbApiKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserList = `-- name: GetUserList :one
+SELECT id, name, created_at
+FROM user_lists
+WHERE id = $1
+  AND user_id = $2
+`
+
+type GetUserListParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID int64     `json:"user_id"`
+}
+
+type GetUserListRow struct {
+	ID        uuid.UUID          `json:"id"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) GetUserList(ctx context.Context, arg GetUserListParams) (GetUserListRow, error) {
+	row := q.db.QueryRow(ctx, getUserList, arg.ID, arg.UserID)
+	var i GetUserListRow
+	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	return i, err
+}
+
+const getUserLists = `-- name: GetUserLists :many
+SELECT l.id,
+       l.name,
+       COUNT(i.id)::INT AS items
+FROM user_lists l
+         LEFT JOIN user_list_items i ON i.list_id = l.id
+WHERE l.user_id = $1
+GROUP BY l.id
+ORDER BY l.created_at
+`
+
+type GetUserListsRow struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Items int32     `json:"items"`
+}
+
+func (q *Queries) GetUserLists(ctx context.Context, userID int64) ([]GetUserListsRow, error) {
+	rows, err := q.db.Query(ctx, getUserLists, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserListsRow
+	for rows.Next() {
+		var i GetUserListsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Items); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserMovie = `-- name: GetUserMovie :one
+SELECT title, runtime
+FROM movies
+WHERE api_id = $1
+  AND user_id = $2
+  AND deleted_at IS NULL
+`
+
+type GetUserMovieParams struct {
+	ApiID  int64 `json:"api_id"`
+	UserID int64 `json:"user_id"`
+}
+
+type GetUserMovieRow struct {
+	Title   string `json:"title"`
+	Runtime int32  `json:"runtime"`
+}
+
+func (q *Queries) GetUserMovie(ctx context.Context, arg GetUserMovieParams) (GetUserMovieRow, error) {
+	row := q.db.QueryRow(ctx, getUserMovie, arg.ApiID, arg.UserID)
+	var i GetUserMovieRow
+	err := row.Scan(&i.Title, &i.Runtime)
+	return i, err
+}
+
+const getUserMovies = `-- name: GetUserMovies :many
+
+SELECT id, api_id, title, runtime, created_at, updated_at
+FROM movies
+WHERE user_id = $1
+  AND deleted_at IS NULL
+`
+
+type GetUserMoviesRow struct {
+	ID        uuid.UUID          `json:"id"`
+	ApiID     int64              `json:"api_id"`
+	Title     string             `json:"title"`
+	Runtime   int32              `json:"runtime"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Movies Table
+func (q *Queries) GetUserMovies(ctx context.Context, userID int64) ([]GetUserMoviesRow, error) {
+	rows, err := q.db.Query(ctx, getUserMovies, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserMoviesRow
+	for rows.Next() {
+		var i GetUserMoviesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ApiID,
+			&i.Title,
+			&i.Runtime,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserSettings = `-- name: GetUserSettings :one
+
+SELECT id,
+       user_id,
+       timezone,
+       quiet_hours_start,
+       quiet_hours_end,
+       notify_new_seasons,
+       notify_status_changes,
+       notify_episode_reminders,
+       notify_releases,
+       notify_digest,
+       digest_mode,
+       region,
+       subscribed_providers,
+       notify_availability,
+       created_at,
+       updated_at
+FROM user_settings
+WHERE user_id = $1
+`
+
+// User Settings
+func (q *Queries) GetUserSettings(ctx context.Context, userID int64) (UserSetting, error) {
+	row := q.db.QueryRow(ctx, getUserSettings, userID)
+	var i UserSetting
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Timezone,
+		&i.QuietHoursStart,
+		&i.QuietHoursEnd,
+		&i.NotifyNewSeasons,
+		&i.NotifyStatusChanges,
+		&i.NotifyEpisodeReminders,
+		&i.NotifyReleases,
+		&i.NotifyDigest,
+		&i.DigestMode,
+		&i.Region,
+		&i.SubscribedProviders,
+		&i.NotifyAvailability,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserTMDBKey = `-- name: GetUserTMDBKey :one
+SELECT tmdb_api_key
+FROM users
+WHERE tg_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserTMDBKey(ctx context.Context, tgID int64) (*string, error) {
+	row := q.db.QueryRow(ctx, getUserTMDBKey, tgID)
+	var tmdb_api_key *string
+	err := row.Scan(&tmdb_api_key)
+	return tmdb_api_key, err
+}
+
+const getUserTVShow = `-- name: GetUserTVShow :one
+SELECT id,
+       api_id,
+       name,
+       seasons,
+       episodes,
+       runtime,
+       status,
+       watch_status,
+       watch_status_updated_at,
+       created_at,
+       updated_at
+FROM tv_shows
+WHERE api_id = $1 AND user_id = $2 AND deleted_at IS NULL
+`
+
+type GetUserTVShowParams struct {
+	ApiID  int64 `json:"api_id"`
+	UserID int64 `json:"user_id"`
+}
+
+type GetUserTVShowRow struct {
+	ID                   uuid.UUID          `json:"id"`
+	ApiID                int64              `json:"api_id"`
+	Name                 string             `json:"name"`
+	Seasons              int32              `json:"seasons"`
+	Episodes             int32              `json:"episodes"`
+	Runtime              int32              `json:"runtime"`
+	Status               string             `json:"status"`
+	WatchStatus          string             `json:"watch_status"`
+	WatchStatusUpdatedAt pgtype.Timestamptz `json:"watch_status_updated_at"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) GetUserTVShow(ctx context.Context, arg GetUserTVShowParams) (GetUserTVShowRow, error) {
+	row := q.db.QueryRow(ctx, getUserTVShow, arg.ApiID, arg.UserID)
+	var i GetUserTVShowRow
+	err := row.Scan(
+		&i.ID,
+		&i.ApiID,
+		&i.Name,
+		&i.Seasons,
+		&i.Episodes,
+		&i.Runtime,
+		&i.Status,
+		&i.WatchStatus,
+		&i.WatchStatusUpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserTVShows = `-- name: GetUserTVShows :many
+
+SELECT id,
+       api_id,
+       name,
+       seasons,
+       episodes,
+       runtime,
+       status,
+       watch_status,
+       watch_status_updated_at,
+       created_at,
+       updated_at
+FROM tv_shows
+WHERE user_id = $1
+  AND deleted_at IS NULL
+`
+
+type GetUserTVShowsRow struct {
+	ID                   uuid.UUID          `json:"id"`
+	ApiID                int64              `json:"api_id"`
+	Name                 string             `json:"name"`
+	Seasons              int32              `json:"seasons"`
+	Episodes             int32              `json:"episodes"`
+	Runtime              int32              `json:"runtime"`
+	Status               string             `json:"status"`
+	WatchStatus          string             `json:"watch_status"`
+	WatchStatusUpdatedAt pgtype.Timestamptz `json:"watch_status_updated_at"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+}
+
+// TV Shows Table
+func (q *Queries) GetUserTVShows(ctx context.Context, userID int64) ([]GetUserTVShowsRow, error) {
+	rows, err := q.db.Query(ctx, getUserTVShows, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserTVShowsRow
+	for rows.Next() {
+		var i GetUserTVShowsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ApiID,
+			&i.Name,
+			&i.Seasons,
+			&i.Episodes,
+			&i.Runtime,
+			&i.Status,
+			&i.WatchStatus,
+			&i.WatchStatusUpdatedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserWatchlist = `-- name: GetUserWatchlist :one
+SELECT id,
+       user_id,
+       show_api_id,
+       type,
+       title,
+       image,
+       created_at,
+       updated_at
+FROM watchlists
+WHERE show_api_id = $1
+  AND user_id = $2
+  AND deleted_at IS NULL LIMIT 1
+`
+
+type GetUserWatchlistParams struct {
+	ShowApiID int64 `json:"show_api_id"`
+	UserID    int64 `json:"user_id"`
+}
+
+type GetUserWatchlistRow struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    int64              `json:"user_id"`
+	ShowApiID int64              `json:"show_api_id"`
+	Type      string             `json:"type"`
+	Title     string             `json:"title"`
+	Image     *string            `json:"image"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) GetUserWatchlist(ctx context.Context, arg GetUserWatchlistParams) (GetUserWatchlistRow, error) {
+	row := q.db.QueryRow(ctx, getUserWatchlist, arg.ShowApiID, arg.UserID)
+	var i GetUserWatchlistRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ShowApiID,
+		&i.Type,
+		&i.Title,
+		&i.Image,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserWatchlists = `-- name: GetUserWatchlists :many
+SELECT id, show_api_id, type, title, image, created_at, updated_at
+FROM watchlists
+WHERE user_id = $1
+  AND deleted_at IS NULL
+`
+
+type GetUserWatchlistsRow struct {
+	ID        uuid.UUID          `json:"id"`
+	ShowApiID int64              `json:"show_api_id"`
+	Type      string             `json:"type"`
+	Title     string             `json:"title"`
+	Image     *string            `json:"image"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) GetUserWatchlists(ctx context.Context, userID int64) ([]GetUserWatchlistsRow, error) {
+	rows, err := q.db.Query(ctx, getUserWatchlists, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserWatchlistsRow
+	for rows.Next() {
+		var i GetUserWatchlistsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShowApiID,
+			&i.Type,
+			&i.Title,
+			&i.Image,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsers = `-- name: GetUsers :many
+SELECT id, tg_id, first_name, last_name, username, language, created_at, updated_at
+FROM users
+`
+
+type GetUsersRow struct {
+	ID        uuid.UUID          `json:"id"`
+	TgID      int64              `json:"tg_id"`
+	FirstName *string            `json:"first_name"`
+	LastName  *string            `json:"last_name"`
+	Username  *string            `json:"username"`
+	Language  string             `json:"language"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) GetUsers(ctx context.Context) ([]GetUsersRow, error) {
+	rows, err := q.db.Query(ctx, getUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsersRow
+	for rows.Next() {
+		var i GetUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TgID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Username,
+			&i.Language,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWatchEvents = `-- name: GetWatchEvents :many
+SELECT id, runtime, watched_on, created_at
+FROM watch_events
+WHERE user_id = $1
+  AND api_id = $2
+  AND type = $3
+ORDER BY watched_on DESC, created_at DESC
+`
+
+type GetWatchEventsParams struct {
+	UserID int64  `json:"user_id"`
+	ApiID  int64  `json:"api_id"`
+	Type   string `json:"type"`
+}
+
+type GetWatchEventsRow struct {
+	ID        uuid.UUID          `json:"id"`
+	Runtime   int32              `json:"runtime"`
+	WatchedOn pgtype.Date        `json:"watched_on"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) GetWatchEvents(ctx context.Context, arg GetWatchEventsParams) ([]GetWatchEventsRow, error) {
+	rows, err := q.db.Query(ctx, getWatchEvents, arg.UserID, arg.ApiID, arg.Type)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWatchEventsRow
+	for rows.Next() {
+		var i GetWatchEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Runtime,
+			&i.WatchedOn,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWatchTotals = `-- name: GetWatchTotals :one
+SELECT COUNT(DISTINCT api_id)::INT    AS titles,
+       COUNT(*)::INT                  AS watches,
+       COALESCE(SUM(runtime), 0)::INT AS runtime
+FROM watch_events
+WHERE user_id = $1
+  AND type = $2
+`
+
+type GetWatchTotalsParams struct {
+	UserID int64  `json:"user_id"`
+	Type   string `json:"type"`
+}
+
+type GetWatchTotalsRow struct {
+	Titles  int32 `json:"titles"`
+	Watches int32 `json:"watches"`
+	Runtime int32 `json:"runtime"`
+}
+
+func (q *Queries) GetWatchTotals(ctx context.Context, arg GetWatchTotalsParams) (GetWatchTotalsRow, error) {
+	row := q.db.QueryRow(ctx, getWatchTotals, arg.UserID, arg.Type)
+	var i GetWatchTotalsRow
+	err := row.Scan(&i.Titles, &i.Watches, &i.Runtime)
+	return i, err
+}
+
+const getWatchedEpisodes = `-- name: GetWatchedEpisodes :many
+SELECT season_number, episode_number
+FROM episode_watches
+WHERE user_id = $1
+  AND show_api_id = $2
+ORDER BY season_number, episode_number
+`
+
+type GetWatchedEpisodesParams struct {
+	UserID    int64 `json:"user_id"`
+	ShowApiID int64 `json:"show_api_id"`
+}
+
+type GetWatchedEpisodesRow struct {
+	SeasonNumber  int32 `json:"season_number"`
+	EpisodeNumber int32 `json:"episode_number"`
+}
+
+func (q *Queries) GetWatchedEpisodes(ctx context.Context, arg GetWatchedEpisodesParams) ([]GetWatchedEpisodesRow, error) {
+	rows, err := q.db.Query(ctx, getWatchedEpisodes, arg.UserID, arg.ShowApiID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWatchedEpisodesRow
+	for rows.Next() {
+		var i GetWatchedEpisodesRow
+		if err := rows.Scan(&i.SeasonNumber, &i.EpisodeNumber); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWatchedSeasons = `-- name: GetWatchedSeasons :one
+SELECT seasons
+FROM tv_shows
+WHERE api_id = $1
+  AND user_id = $2
+  AND deleted_at IS NULL
+`
+
+type GetWatchedSeasonsParams struct {
+	ApiID  int64 `json:"api_id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) GetWatchedSeasons(ctx context.Context, arg GetWatchedSeasonsParams) (int32, error) {
+	row := q.db.QueryRow(ctx, getWatchedSeasons, arg.ApiID, arg.UserID)
+	var seasons int32
+	err := row.Scan(&seasons)
+	return seasons, err
+}
+
+const getWatchlistAvailability = `-- name: GetWatchlistAvailability :many
+SELECT w.id,
+       w.user_id,
+       w.show_api_id,
+       w.type,
+       w.title,
+       w.available_providers,
+       COALESCE(s.region, 'US')::TEXT                    AS region,
+       COALESCE(s.subscribed_providers, '{}')::INT[]     AS subscribed_providers
+FROM watchlists w
+         LEFT JOIN user_settings s ON s.user_id = w.user_id
+WHERE w.deleted_at IS NULL
+ORDER BY w.type, w.show_api_id
+`
+
+type GetWatchlistAvailabilityRow struct {
+	ID                  uuid.UUID `json:"id"`
+	UserID              int64     `json:"user_id"`
+	ShowApiID           int64     `json:"show_api_id"`
+	Type                string    `json:"type"`
+	Title               string    `json:"title"`
+	AvailableProviders  []int32   `json:"available_providers"`
+	Region              string    `json:"region"`
+	SubscribedProviders []int32   `json:"subscribed_providers"`
+}
+
+func (q *Queries) GetWatchlistAvailability(ctx context.Context) ([]GetWatchlistAvailabilityRow, error) {
+	rows, err := q.db.Query(ctx, getWatchlistAvailability)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWatchlistAvailabilityRow
+	for rows.Next() {
+		var i GetWatchlistAvailabilityRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ShowApiID,
+			&i.Type,
+			&i.Title,
+			&i.AvailableProviders,
+			&i.Region,
+			&i.SubscribedProviders,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWatchlistEntries = `-- name: GetWatchlistEntries :many
+SELECT id,
+       show_api_id,
+       type,
+       title,
+       image,
+       priority,
+       note,
+       position,
+       release_date,
+       runtime,
+       created_at
+FROM watchlists
+WHERE user_id = $1
+  AND (type = $2 OR $2 = 'ALL')
+  AND (priority = $3 OR $3 = 'ALL')
+  AND deleted_at IS NULL
+ORDER BY CASE WHEN $4::TEXT = 'PRIORITY' THEN CASE priority WHEN 'HIGH' THEN 0 WHEN 'NORMAL' THEN 1 ELSE 2 END END,
+         CASE WHEN $4::TEXT = 'ADDED' THEN created_at END DESC,
+         CASE WHEN $4::TEXT = 'TITLE' THEN LOWER(title) END,
+         CASE WHEN $4::TEXT = 'RELEASE' THEN release_date END NULLS LAST,
+         CASE WHEN $4::TEXT = 'RUNTIME' THEN runtime END NULLS LAST,
+         position
+`
+
+type GetWatchlistEntriesParams struct {
+	UserID   int64  `json:"user_id"`
+	Type     string `json:"type"`
+	Priority string `json:"priority"`
+	SortBy   string `json:"sort_by"`
+}
+
+type GetWatchlistEntriesRow struct {
+	ID          uuid.UUID          `json:"id"`
+	ShowApiID   int64              `json:"show_api_id"`
+	Type        string             `json:"type"`
+	Title       string             `json:"title"`
+	Image       *string            `json:"image"`
+	Priority    string             `json:"priority"`
+	Note        *string            `json:"note"`
+	Position    int32              `json:"position"`
+	ReleaseDate pgtype.Date        `json:"release_date"`
+	Runtime     *int32             `json:"runtime"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) GetWatchlistEntries(ctx context.Context, arg GetWatchlistEntriesParams) ([]GetWatchlistEntriesRow, error) {
+	rows, err := q.db.Query(ctx, getWatchlistEntries,
+		arg.UserID,
+		arg.Type,
+		arg.Priority,
+		arg.SortBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWatchlistEntriesRow
+	for rows.Next() {
+		var i GetWatchlistEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShowApiID,
+			&i.Type,
+			&i.Title,
+			&i.Image,
+			&i.Priority,
+			&i.Note,
+			&i.Position,
+			&i.ReleaseDate,
+			&i.Runtime,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWatchlistEntry = `-- name: GetWatchlistEntry :one
+SELECT id,
+       show_api_id,
+       type,
+       title,
+       image,
+       priority,
+       note,
+       position,
+       release_date,
+       runtime,
+       created_at
+FROM watchlists
+WHERE user_id = $1
+  AND show_api_id = $2
+  AND type = $3
+  AND deleted_at IS NULL
+`
+
+type GetWatchlistEntryParams struct {
+	UserID    int64  `json:"user_id"`
+	ShowApiID int64  `json:"show_api_id"`
+	Type      string `json:"type"`
+}
+
+type GetWatchlistEntryRow struct {
+	ID          uuid.UUID          `json:"id"`
+	ShowApiID   int64              `json:"show_api_id"`
+	Type        string             `json:"type"`
+	Title       string             `json:"title"`
+	Image       *string            `json:"image"`
+	Priority    string             `json:"priority"`
+	Note        *string            `json:"note"`
+	Position    int32              `json:"position"`
+	ReleaseDate pgtype.Date        `json:"release_date"`
+	Runtime     *int32             `json:"runtime"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) GetWatchlistEntry(ctx context.Context, arg GetWatchlistEntryParams) (GetWatchlistEntryRow, error) {
+	row := q.db.QueryRow(ctx, getWatchlistEntry, arg.UserID, arg.ShowApiID, arg.Type)
+	var i GetWatchlistEntryRow
+	err := row.Scan(
+		&i.ID,
+		&i.ShowApiID,
+		&i.Type,
+		&i.Title,
+		&i.Image,
+		&i.Priority,
+		&i.Note,
+		&i.Position,
+		&i.ReleaseDate,
+		&i.Runtime,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWatchlistsByType = `-- name: GetWatchlistsByType :many
+SELECT id, user_id, show_api_id, title, image, release_date, release_status, aired_seasons, runtime
+FROM watchlists
+WHERE type = $1
+  AND deleted_at IS NULL
+ORDER BY show_api_id
+`
+
+type GetWatchlistsByTypeRow struct {
+	ID            uuid.UUID   `json:"id"`
+	UserID        int64       `json:"user_id"`
+	ShowApiID     int64       `json:"show_api_id"`
+	Title         string      `json:"title"`
+	Image         *string     `json:"image"`
+	ReleaseDate   pgtype.Date `json:"release_date"`
+	ReleaseStatus *string     `json:"release_status"`
+	AiredSeasons  *int32      `json:"aired_seasons"`
+	Runtime       *int32      `json:"runtime"`
+}
+
+func (q *Queries) GetWatchlistsByType(ctx context.Context, type_ string) ([]GetWatchlistsByTypeRow, error) {
+	rows, err := q.db.Query(ctx, getWatchlistsByType, type_)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWatchlistsByTypeRow
+	for rows.Next() {
+		var i GetWatchlistsByTypeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ShowApiID,
+			&i.Title,
+			&i.Image,
+			&i.ReleaseDate,
+			&i.ReleaseStatus,
+			&i.AiredSeasons,
+			&i.Runtime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkerPerformance = `-- name: GetWorkerPerformance :many
+SELECT worker_id,
+       worker_type,
+       status,
+       last_check_time,
+       next_check_time,
+       shows_checked,
+       updates_found,
+       error,
+       total_tasks,
+       avg_task_duration_ms,
+       max_task_duration_ms,
+       error_tasks,
+       last_task_time,
+       paused
+FROM worker_performance
+ORDER BY worker_type, worker_id
+`
+
+func (q *Queries) GetWorkerPerformance(ctx context.Context) ([]WorkerPerformance, error) {
+	rows, err := q.db.Query(ctx, getWorkerPerformance)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkerPerformance
+	for rows.Next() {
+		var i WorkerPerformance
+		if err := rows.Scan(
+			&i.WorkerID,
+			&i.WorkerType,
+			&i.Status,
+			&i.LastCheckTime,
+			&i.NextCheckTime,
+			&i.ShowsChecked,
+			&i.UpdatesFound,
+			&i.Error,
+			&i.TotalTasks,
+			&i.AvgTaskDurationMs,
+			&i.MaxTaskDurationMs,
+			&i.ErrorTasks,
+			&i.LastTaskTime,
+			&i.Paused,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkerState = `-- name: GetWorkerState :one
+
+SELECT id,
+       worker_id,
+       worker_type,
+       status,
+       last_check_time,
+       next_check_time,
+       error,
+       shows_checked,
+       updates_found,
+       cycle_cursor,
+       paused,
+       created_at,
+       updated_at
+FROM worker_states
+WHERE worker_id = $1
+ORDER BY updated_at DESC LIMIT 1
+`
+
+// Workers Related
+func (q *Queries) GetWorkerState(ctx context.Context, workerID string) (WorkerState, error) {
+	row := q.db.QueryRow(ctx, getWorkerState, workerID)
+	var i WorkerState
+	err := row.Scan(
+		&i.ID,
+		&i.WorkerID,
+		&i.WorkerType,
+		&i.Status,
+		&i.LastCheckTime,
+		&i.NextCheckTime,
+		&i.Error,
+		&i.ShowsChecked,
+		&i.UpdatesFound,
+		&i.CycleCursor,
+		&i.Paused,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWorkerTask = `-- name: GetWorkerTask :one
+SELECT id,
+       worker_id,
+       task_type,
+       status,
+       start_time,
+       end_time,
+       duration_ms,
+       error,
+       show_id,
+       user_id,
+       shows_checked,
+       updates_found,
+       api_calls_saved,
+       created_at
+FROM worker_tasks
+WHERE id = $1
+`
+
+func (q *Queries) GetWorkerTask(ctx context.Context, id uuid.UUID) (WorkerTask, error) {
+	row := q.db.QueryRow(ctx, getWorkerTask, id)
+	var i WorkerTask
+	err := row.Scan(
+		&i.ID,
+		&i.WorkerID,
+		&i.TaskType,
+		&i.Status,
+		&i.StartTime,
+		&i.EndTime,
+		&i.DurationMs,
+		&i.Error,
+		&i.ShowID,
+		&i.UserID,
+		&i.ShowsChecked,
+		&i.UpdatesFound,
+		&i.ApiCallsSaved,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const holdNotification = `-- name: HoldNotification :exec
+INSERT INTO notifications (user_id, kind, show_api_id, text, show_name, status)
+VALUES ($1, $2, $3, $4, $5, 'held')
+`
+
+type HoldNotificationParams struct {
+	UserID    int64   `json:"user_id"`
+	Kind      string  `json:"kind"`
+	ShowApiID *int64  `json:"show_api_id"`
+	Text      string  `json:"text"`
+	ShowName  *string `json:"show_name"`
+}
+
+func (q *Queries) HoldNotification(ctx context.Context, arg HoldNotificationParams) error {
+	_, err := q.db.Exec(ctx, holdNotification,
+		arg.UserID,
+		arg.Kind,
+		arg.ShowApiID,
+		arg.Text,
+		arg.ShowName,
+	)
+	return err
+}
+
+const isWorkerTypePaused = `-- name: IsWorkerTypePaused :one
+SELECT COALESCE(BOOL_OR(paused), FALSE)::BOOLEAN AS paused
+FROM worker_states
+WHERE worker_type = $1
+`
+
+func (q *Queries) IsWorkerTypePaused(ctx context.Context, workerType string) (bool, error) {
+	row := q.db.QueryRow(ctx, isWorkerTypePaused, workerType)
+	var paused bool
+	err := row.Scan(&paused)
+	return paused, err
+}
+
+const markEpisodeReminderWatched = `-- name: MarkEpisodeReminderWatched :execrows
+UPDATE episode_reminders
+SET watched_at = NOW()
+WHERE user_id = $1
+  AND show_api_id = $2
+  AND season_number = $3
+  AND episode_number = $4
+  AND watched_at IS NULL
+`
+
+type MarkEpisodeReminderWatchedParams struct {
+	UserID        int64 `json:"user_id"`
+	ShowApiID     int64 `json:"show_api_id"`
+	SeasonNumber  int32 `json:"season_number"`
+	EpisodeNumber int32 `json:"episode_number"`
+}
+
+func (q *Queries) MarkEpisodeReminderWatched(ctx context.Context, arg MarkEpisodeReminderWatchedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markEpisodeReminderWatched,
+		arg.UserID,
+		arg.ShowApiID,
+		arg.SeasonNumber,
+		arg.EpisodeNumber,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markEpisodeWatched = `-- name: MarkEpisodeWatched :execrows
+INSERT INTO episode_watches (user_id, show_api_id, season_number, episode_number, runtime)
+VALUES ($1, $2, $3, $4, $5) ON CONFLICT (user_id, show_api_id, season_number, episode_number) DO NOTHING
+`
+
+type MarkEpisodeWatchedParams struct {
+	UserID        int64 `json:"user_id"`
+	ShowApiID     int64 `json:"show_api_id"`
+	SeasonNumber  int32 `json:"season_number"`
+	EpisodeNumber int32 `json:"episode_number"`
+	Runtime       int32 `json:"runtime"`
+}
+
+func (q *Queries) MarkEpisodeWatched(ctx context.Context, arg MarkEpisodeWatchedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markEpisodeWatched,
+		arg.UserID,
+		arg.ShowApiID,
+		arg.SeasonNumber,
+		arg.EpisodeNumber,
+		arg.Runtime,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markNotificationFailed = `-- name: MarkNotificationFailed :exec
+UPDATE notifications
+SET status     = 'failed',
+    attempts   = attempts + 1,
+    last_error = $2
+WHERE id = $1
+`
+
+type MarkNotificationFailedParams struct {
+	ID        uuid.UUID `json:"id"`
+	LastError *string   `json:"last_error"`
+}
+
+func (q *Queries) MarkNotificationFailed(ctx context.Context, arg MarkNotificationFailedParams) error {
+	_, err := q.db.Exec(ctx, markNotificationFailed, arg.ID, arg.LastError)
+	return err
+}
+
+const markNotificationPosterSent = `-- name: MarkNotificationPosterSent :exec
+UPDATE notifications
+SET poster_sent = TRUE
+WHERE id = $1
+`
+
+func (q *Queries) MarkNotificationPosterSent(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markNotificationPosterSent, id)
+	return err
+}
+
+const markNotificationRetry = `-- name: MarkNotificationRetry :exec
+UPDATE notifications
+SET attempts        = attempts + 1,
+    next_attempt_at = $2,
+    last_error      = $3
+WHERE id = $1
+`
+
+type MarkNotificationRetryParams struct {
+	ID            uuid.UUID          `json:"id"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+	LastError     *string            `json:"last_error"`
+}
+
+func (q *Queries) MarkNotificationRetry(ctx context.Context, arg MarkNotificationRetryParams) error {
+	_, err := q.db.Exec(ctx, markNotificationRetry, arg.ID, arg.NextAttemptAt, arg.LastError)
+	return err
+}
+
+const markNotificationSent = `-- name: MarkNotificationSent :exec
+UPDATE notifications
+SET status     = 'sent',
+    attempts   = attempts + 1,
+    last_error = NULL,
+    sent_at    = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkNotificationSent(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markNotificationSent, id)
+	return err
+}
+
+const markNotificationSkipped = `-- name: MarkNotificationSkipped :exec
+UPDATE notifications
+SET status     = 'skipped',
+    last_error = $2
+WHERE id = $1
+`
+
+type MarkNotificationSkippedParams struct {
+	ID        uuid.UUID `json:"id"`
+	LastError *string   `json:"last_error"`
+}
+
+func (q *Queries) MarkNotificationSkipped(ctx context.Context, arg MarkNotificationSkippedParams) error {
+	_, err := q.db.Exec(ctx, markNotificationSkipped, arg.ID, arg.LastError)
+	return err
+}
+
+const markNotificationsDigested = `-- name: MarkNotificationsDigested :exec
+UPDATE notifications
+SET status  = 'digested',
+    sent_at = NOW()
+WHERE id = ANY ($1::UUID[])
+`
+
+func (q *Queries) MarkNotificationsDigested(ctx context.Context, dollar_1 []uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markNotificationsDigested, dollar_1)
+	return err
+}
+
+const movieExists = `-- name: MovieExists :one
+SELECT EXISTS(SELECT 1 FROM movies WHERE api_id = $1 AND user_id = $2 AND deleted_at IS NULL)
+`
+
+type MovieExistsParams struct {
+	ApiID  int64 `json:"api_id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) MovieExists(ctx context.Context, arg MovieExistsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, movieExists, arg.ApiID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const releaseWorkerLease = `-- name: ReleaseWorkerLease :exec
+DELETE
+FROM worker_leases
+WHERE worker_type = $1
+  AND holder_id = $2
+`
+
+type ReleaseWorkerLeaseParams struct {
+	WorkerType string `json:"worker_type"`
+	HolderID   string `json:"holder_id"`
+}
+
+func (q *Queries) ReleaseWorkerLease(ctx context.Context, arg ReleaseWorkerLeaseParams) error {
+	_, err := q.db.Exec(ctx, releaseWorkerLease, arg.WorkerType, arg.HolderID)
+	return err
+}
+
+const removeListItem = `-- name: RemoveListItem :execrows
+DELETE
+FROM user_list_items
+WHERE list_id = $1
+  AND show_api_id = $2
+  AND type = $3
+`
+
+type RemoveListItemParams struct {
+	ListID    uuid.UUID `json:"list_id"`
+	ShowApiID int64     `json:"show_api_id"`
+	Type      string    `json:"type"`
+}
+
+func (q *Queries) RemoveListItem(ctx context.Context, arg RemoveListItemParams) (int64, error) {
+	result, err := q.db.Exec(ctx, removeListItem, arg.ListID, arg.ShowApiID, arg.Type)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const renameUserList = `-- name: RenameUserList :execrows
+UPDATE user_lists
+SET name = $3
+WHERE id = $1
+  AND user_id = $2
+`
+
+type RenameUserListParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID int64     `json:"user_id"`
+	Name   string    `json:"name"`
+}
+
+func (q *Queries) RenameUserList(ctx context.Context, arg RenameUserListParams) (int64, error) {
+	result, err := q.db.Exec(ctx, renameUserList, arg.ID, arg.UserID, arg.Name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const resetWatchlistProviders = `-- name: ResetWatchlistProviders :exec
+UPDATE watchlists
+SET available_providers = NULL
+WHERE user_id = $1
+`
+
+func (q *Queries) ResetWatchlistProviders(ctx context.Context, userID int64) error {
+	_, err := q.db.Exec(ctx, resetWatchlistProviders, userID)
+	return err
+}
+
+const restoreEpisodeWatch = `-- name: RestoreEpisodeWatch :exec
+INSERT INTO episode_watches (user_id, show_api_id, season_number, episode_number, runtime, watched_at)
+VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (user_id, show_api_id, season_number, episode_number) DO NOTHING
+`
+
+type RestoreEpisodeWatchParams struct {
+	UserID        int64              `json:"user_id"`
+	ShowApiID     int64              `json:"show_api_id"`
+	SeasonNumber  int32              `json:"season_number"`
+	EpisodeNumber int32              `json:"episode_number"`
+	Runtime       int32              `json:"runtime"`
+	WatchedAt     pgtype.Timestamptz `json:"watched_at"`
+}
+
+func (q *Queries) RestoreEpisodeWatch(ctx context.Context, arg RestoreEpisodeWatchParams) error {
+	_, err := q.db.Exec(ctx, restoreEpisodeWatch,
+		arg.UserID,
+		arg.ShowApiID,
+		arg.SeasonNumber,
+		arg.EpisodeNumber,
+		arg.Runtime,
+		arg.WatchedAt,
+	)
+	return err
+}
+
+const restoreRating = `-- name: RestoreRating :exec
+INSERT INTO ratings (user_id, api_id, type, season_number, rating, review)
+VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (user_id, api_id, type, season_number) DO NOTHING
+`
+
+type RestoreRatingParams struct {
+	UserID       int64   `json:"user_id"`
+	ApiID        int64   `json:"api_id"`
+	Type         string  `json:"type"`
+	SeasonNumber int32   `json:"season_number"`
+	Rating       int16   `json:"rating"`
+	Review       *string `json:"review"`
+}
+
+func (q *Queries) RestoreRating(ctx context.Context, arg RestoreRatingParams) error {
+	_, err := q.db.Exec(ctx, restoreRating,
+		arg.UserID,
+		arg.ApiID,
+		arg.Type,
+		arg.SeasonNumber,
+		arg.Rating,
+		arg.Review,
+	)
+	return err
+}
+
+const restoreWatchEvent = `-- name: RestoreWatchEvent :exec
+INSERT INTO watch_events (user_id, api_id, type, runtime, watched_on, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type RestoreWatchEventParams struct {
+	UserID    int64              `json:"user_id"`
+	ApiID     int64              `json:"api_id"`
+	Type      string             `json:"type"`
+	Runtime   int32              `json:"runtime"`
+	WatchedOn pgtype.Date        `json:"watched_on"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) RestoreWatchEvent(ctx context.Context, arg RestoreWatchEventParams) error {
+	_, err := q.db.Exec(ctx, restoreWatchEvent,
+		arg.UserID,
+		arg.ApiID,
+		arg.Type,
+		arg.Runtime,
+		arg.WatchedOn,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const rollupWorkerTasks = `-- name: RollupWorkerTasks :one
+WITH pruned AS (
+    DELETE FROM worker_tasks
+    WHERE id IN (SELECT id
+                 FROM worker_tasks
+                 WHERE status <> 'running'
+                   AND created_at < $1
+                 ORDER BY created_at LIMIT $2)
+    RETURNING worker_id, task_type, status, duration_ms, shows_checked, updates_found, api_calls_saved, created_at),
+     rolled AS (
+         INSERT INTO worker_task_daily_stats (worker_id, task_type, day, task_count, error_count, timed_count,
+                                              total_duration_ms, max_duration_ms, shows_checked, updates_found,
+                                              api_calls_saved, last_task_time)
+             SELECT worker_id,
+                    task_type,
+                    (created_at AT TIME ZONE 'UTC')::DATE,
+                    COUNT(*),
+                    COUNT(*) FILTER (WHERE status = 'error'),
+                    COUNT(duration_ms),
+                    COALESCE(SUM(duration_ms), 0),
+                    MAX(duration_ms),
+                    COALESCE(SUM(shows_checked), 0),
+                    COALESCE(SUM(updates_found), 0),
+                    COALESCE(SUM(api_calls_saved), 0),
+                    MAX(created_at)
+             FROM pruned
+             GROUP BY worker_id, task_type, (created_at AT TIME ZONE 'UTC')::DATE
+             ON CONFLICT (worker_id, task_type, day) DO UPDATE SET
+                 task_count = worker_task_daily_stats.task_count + EXCLUDED.task_count,
+                 error_count = worker_task_daily_stats.error_count + EXCLUDED.error_count,
+                 timed_count = worker_task_daily_stats.timed_count + EXCLUDED.timed_count,
+                 total_duration_ms = worker_task_daily_stats.total_duration_ms + EXCLUDED.total_duration_ms,
+                 max_duration_ms = GREATEST(worker_task_daily_stats.max_duration_ms, EXCLUDED.max_duration_ms),
+                 shows_checked = worker_task_daily_stats.shows_checked + EXCLUDED.shows_checked,
+                 updates_found = worker_task_daily_stats.updates_found + EXCLUDED.updates_found,
+                 api_calls_saved = worker_task_daily_stats.api_calls_saved + EXCLUDED.api_calls_saved,
+                 last_task_time = GREATEST(worker_task_daily_stats.last_task_time, EXCLUDED.last_task_time)
+             RETURNING 1)
+SELECT (SELECT COUNT(*) FROM pruned) AS pruned_tasks,
+       (SELECT COUNT(*) FROM rolled) AS stat_rows
+`
+
+type RollupWorkerTasksParams struct {
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	Limit     int32              `json:"limit"`
+}
+
+type RollupWorkerTasksRow struct {
+	PrunedTasks int64 `json:"pruned_tasks"`
+	StatRows    int64 `json:"stat_rows"`
+}
+
+func (q *Queries) RollupWorkerTasks(ctx context.Context, arg RollupWorkerTasksParams) (RollupWorkerTasksRow, error) {
+	row := q.db.QueryRow(ctx, rollupWorkerTasks, arg.CreatedAt, arg.Limit)
+	var i RollupWorkerTasksRow
+	err := row.Scan(&i.PrunedTasks, &i.StatRows)
+	return i, err
+}
+
+const setRatingReview = `-- name: SetRatingReview :execrows
+UPDATE ratings
+SET review = $5
+WHERE user_id = $1
+  AND api_id = $2
+  AND type = $3
+  AND season_number = $4
 `
 
-func (q *Queries) GetWorkerTask(ctx context.Context, id uuid.UUID) (WorkerTask, error) {
-	row := q.db.QueryRow(ctx, getWorkerTask, id)
-	var i WorkerTask
-	err := row.Scan(
-		&i.ID,
-		&i.WorkerID,
-		&i.TaskType,
-		&i.Status,
-		&i.StartTime,
-		&i.EndTime,
-		&i.DurationMs,
-		&i.Error,
-		&i.ShowID,
-		&i.UserID,
-		&i.ShowsChecked,
-		&i.UpdatesFound,
-		&i.CreatedAt,
+type SetRatingReviewParams struct {
+	UserID       int64   `json:"user_id"`
+	ApiID        int64   `json:"api_id"`
+	Type         string  `json:"type"`
+	SeasonNumber int32   `json:"season_number"`
+	Review       *string `json:"review"`
+}
+
+func (q *Queries) SetRatingReview(ctx context.Context, arg SetRatingReviewParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setRatingReview,
+		arg.UserID,
+		arg.ApiID,
+		arg.Type,
+		arg.SeasonNumber,
+		arg.Review,
 	)
-	return i, err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const movieExists = `-- name: MovieExists :one
-SELECT EXISTS(SELECT 1 FROM movies WHERE api_id = $1 AND user_id = $2 AND deleted_at IS NULL)
+const setWorkerPaused = `-- name: SetWorkerPaused :execrows
+UPDATE worker_states
+SET paused = $2
+WHERE worker_type = $1
 `
 
-type MovieExistsParams struct {
-	ApiID  int64 `json:"api_id"`
-	UserID int64 `json:"user_id"`
+type SetWorkerPausedParams struct {
+	WorkerType string `json:"worker_type"`
+	Paused     bool   `json:"paused"`
 }
 
-func (q *Queries) MovieExists(ctx context.Context, arg MovieExistsParams) (bool, error) {
-	row := q.db.QueryRow(ctx, movieExists, arg.ApiID, arg.UserID)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
+func (q *Queries) SetWorkerPaused(ctx context.Context, arg SetWorkerPausedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setWorkerPaused, arg.WorkerType, arg.Paused)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const snoozeShowNotification = `-- name: SnoozeShowNotification :execrows
+UPDATE show_notification_states
+SET snoozed_until = $3
+WHERE user_id = $1
+  AND show_api_id = $2
+`
+
+type SnoozeShowNotificationParams struct {
+	UserID       int64              `json:"user_id"`
+	ShowApiID    int64              `json:"show_api_id"`
+	SnoozedUntil pgtype.Timestamptz `json:"snoozed_until"`
+}
+
+func (q *Queries) SnoozeShowNotification(ctx context.Context, arg SnoozeShowNotificationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, snoozeShowNotification, arg.UserID, arg.ShowApiID, arg.SnoozedUntil)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const softDeleteMovie = `-- name: SoftDeleteMovie :exec
@@ -774,6 +2668,35 @@ func (q *Queries) TVShowExists(ctx context.Context, arg TVShowExistsParams) (boo
 	return exists, err
 }
 
+const unmarkEpisodeWatched = `-- name: UnmarkEpisodeWatched :execrows
+DELETE
+FROM episode_watches
+WHERE user_id = $1
+  AND show_api_id = $2
+  AND season_number = $3
+  AND episode_number = $4
+`
+
+type UnmarkEpisodeWatchedParams struct {
+	UserID        int64 `json:"user_id"`
+	ShowApiID     int64 `json:"show_api_id"`
+	SeasonNumber  int32 `json:"season_number"`
+	EpisodeNumber int32 `json:"episode_number"`
+}
+
+func (q *Queries) UnmarkEpisodeWatched(ctx context.Context, arg UnmarkEpisodeWatchedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unmarkEpisodeWatched,
+		arg.UserID,
+		arg.ShowApiID,
+		arg.SeasonNumber,
+		arg.EpisodeNumber,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateMovie = `-- name: UpdateMovie :exec
 UPDATE movies
 SET runtime = $3,
@@ -829,6 +2752,45 @@ func (q *Queries) UpdateTVShow(ctx context.Context, arg UpdateTVShowParams) erro
 	return err
 }
 
+const updateTVShowStatus = `-- name: UpdateTVShowStatus :exec
+UPDATE tv_shows
+SET status = $3
+WHERE api_id = $1
+  AND user_id = $2
+  AND deleted_at IS NULL
+`
+
+type UpdateTVShowStatusParams struct {
+	ApiID  int64  `json:"api_id"`
+	UserID int64  `json:"user_id"`
+	Status string `json:"status"`
+}
+
+func (q *Queries) UpdateTVShowStatus(ctx context.Context, arg UpdateTVShowStatusParams) error {
+	_, err := q.db.Exec(ctx, updateTVShowStatus, arg.ApiID, arg.UserID, arg.Status)
+	return err
+}
+
+const updateTVShowWatchStatus = `-- name: UpdateTVShowWatchStatus :exec
+UPDATE tv_shows
+SET watch_status            = $3,
+    watch_status_updated_at = NOW()
+WHERE api_id = $1
+  AND user_id = $2
+  AND deleted_at IS NULL
+`
+
+type UpdateTVShowWatchStatusParams struct {
+	ApiID       int64  `json:"api_id"`
+	UserID      int64  `json:"user_id"`
+	WatchStatus string `json:"watch_status"`
+}
+
+func (q *Queries) UpdateTVShowWatchStatus(ctx context.Context, arg UpdateTVShowWatchStatusParams) error {
+	_, err := q.db.Exec(ctx, updateTVShowWatchStatus, arg.ApiID, arg.UserID, arg.WatchStatus)
+	return err
+}
+
 const updateUserTMDBKey = `-- name: UpdateUserTMDBKey :exec
 UPDATE users
 SET tmdb_api_key = $2
@@ -845,6 +2807,165 @@ func (q *Queries) UpdateUserTMDBKey(ctx context.Context, arg UpdateUserTMDBKeyPa
 	return err
 }
 
+const updateWatchlistNote = `-- name: UpdateWatchlistNote :execrows
+UPDATE watchlists
+SET note = $4
+WHERE user_id = $1
+  AND show_api_id = $2
+  AND type = $3
+  AND deleted_at IS NULL
+`
+
+type UpdateWatchlistNoteParams struct {
+	UserID    int64   `json:"user_id"`
+	ShowApiID int64   `json:"show_api_id"`
+	Type      string  `json:"type"`
+	Note      *string `json:"note"`
+}
+
+func (q *Queries) UpdateWatchlistNote(ctx context.Context, arg UpdateWatchlistNoteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateWatchlistNote,
+		arg.UserID,
+		arg.ShowApiID,
+		arg.Type,
+		arg.Note,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateWatchlistPosition = `-- name: UpdateWatchlistPosition :exec
+UPDATE watchlists
+SET position = $2
+WHERE id = $1
+`
+
+type UpdateWatchlistPositionParams struct {
+	ID       uuid.UUID `json:"id"`
+	Position int32     `json:"position"`
+}
+
+func (q *Queries) UpdateWatchlistPosition(ctx context.Context, arg UpdateWatchlistPositionParams) error {
+	_, err := q.db.Exec(ctx, updateWatchlistPosition, arg.ID, arg.Position)
+	return err
+}
+
+const updateWatchlistPriority = `-- name: UpdateWatchlistPriority :execrows
+UPDATE watchlists
+SET priority = $4
+WHERE user_id = $1
+  AND show_api_id = $2
+  AND type = $3
+  AND deleted_at IS NULL
+`
+
+type UpdateWatchlistPriorityParams struct {
+	UserID    int64  `json:"user_id"`
+	ShowApiID int64  `json:"show_api_id"`
+	Type      string `json:"type"`
+	Priority  string `json:"priority"`
+}
+
+func (q *Queries) UpdateWatchlistPriority(ctx context.Context, arg UpdateWatchlistPriorityParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateWatchlistPriority,
+		arg.UserID,
+		arg.ShowApiID,
+		arg.Type,
+		arg.Priority,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateWatchlistProviders = `-- name: UpdateWatchlistProviders :exec
+UPDATE watchlists
+SET available_providers = $2
+WHERE id = $1
+`
+
+type UpdateWatchlistProvidersParams struct {
+	ID                 uuid.UUID `json:"id"`
+	AvailableProviders []int32   `json:"available_providers"`
+}
+
+func (q *Queries) UpdateWatchlistProviders(ctx context.Context, arg UpdateWatchlistProvidersParams) error {
+	_, err := q.db.Exec(ctx, updateWatchlistProviders, arg.ID, arg.AvailableProviders)
+	return err
+}
+
+const updateWatchlistRelease = `-- name: UpdateWatchlistRelease :exec
+UPDATE watchlists
+SET release_date   = $2,
+    release_status = $3,
+    runtime        = $4
+WHERE id = $1
+`
+
+type UpdateWatchlistReleaseParams struct {
+	ID            uuid.UUID   `json:"id"`
+	ReleaseDate   pgtype.Date `json:"release_date"`
+	ReleaseStatus *string     `json:"release_status"`
+	Runtime       *int32      `json:"runtime"`
+}
+
+func (q *Queries) UpdateWatchlistRelease(ctx context.Context, arg UpdateWatchlistReleaseParams) error {
+	_, err := q.db.Exec(ctx, updateWatchlistRelease,
+		arg.ID,
+		arg.ReleaseDate,
+		arg.ReleaseStatus,
+		arg.Runtime,
+	)
+	return err
+}
+
+const updateWatchlistShowRelease = `-- name: UpdateWatchlistShowRelease :exec
+UPDATE watchlists
+SET release_date   = $2,
+    release_status = $3,
+    aired_seasons  = $4,
+    runtime        = $5
+WHERE id = $1
+`
+
+type UpdateWatchlistShowReleaseParams struct {
+	ID            uuid.UUID   `json:"id"`
+	ReleaseDate   pgtype.Date `json:"release_date"`
+	ReleaseStatus *string     `json:"release_status"`
+	AiredSeasons  *int32      `json:"aired_seasons"`
+	Runtime       *int32      `json:"runtime"`
+}
+
+func (q *Queries) UpdateWatchlistShowRelease(ctx context.Context, arg UpdateWatchlistShowReleaseParams) error {
+	_, err := q.db.Exec(ctx, updateWatchlistShowRelease,
+		arg.ID,
+		arg.ReleaseDate,
+		arg.ReleaseStatus,
+		arg.AiredSeasons,
+		arg.Runtime,
+	)
+	return err
+}
+
+const updateWorkerCycleCursor = `-- name: UpdateWorkerCycleCursor :exec
+UPDATE worker_states
+SET cycle_cursor = $2
+WHERE worker_id = $1
+`
+
+type UpdateWorkerCycleCursorParams struct {
+	WorkerID    string `json:"worker_id"`
+	CycleCursor *int64 `json:"cycle_cursor"`
+}
+
+func (q *Queries) UpdateWorkerCycleCursor(ctx context.Context, arg UpdateWorkerCycleCursorParams) error {
+	_, err := q.db.Exec(ctx, updateWorkerCycleCursor, arg.WorkerID, arg.CycleCursor)
+	return err
+}
+
 const updateWorkerTask = `-- name: UpdateWorkerTask :exec
 UPDATE worker_tasks
 SET status        = $2,
@@ -879,6 +3000,176 @@ func (q *Queries) UpdateWorkerTask(ctx context.Context, arg UpdateWorkerTaskPara
 	return err
 }
 
+const updateWorkerTaskApiCallsSaved = `-- name: UpdateWorkerTaskApiCallsSaved :exec
+UPDATE worker_tasks
+SET api_calls_saved = $2
+WHERE id = $1
+`
+
+type UpdateWorkerTaskApiCallsSavedParams struct {
+	ID            uuid.UUID `json:"id"`
+	ApiCallsSaved *int32    `json:"api_calls_saved"`
+}
+
+func (q *Queries) UpdateWorkerTaskApiCallsSaved(ctx context.Context, arg UpdateWorkerTaskApiCallsSavedParams) error {
+	_, err := q.db.Exec(ctx, updateWorkerTaskApiCallsSaved, arg.ID, arg.ApiCallsSaved)
+	return err
+}
+
+const upsertEpisodeSchedule = `-- name: UpsertEpisodeSchedule :exec
+
+INSERT INTO episode_schedules (show_api_id, show_name, season_number, episode_number, episode_name, air_date, runtime)
+VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (show_api_id, season_number, episode_number) DO
+UPDATE SET
+    show_name = EXCLUDED.show_name,
+    episode_name = EXCLUDED.episode_name,
+    air_date = EXCLUDED.air_date,
+    runtime = EXCLUDED.runtime
+`
+
+type UpsertEpisodeScheduleParams struct {
+	ShowApiID     int64       `json:"show_api_id"`
+	ShowName      string      `json:"show_name"`
+	SeasonNumber  int32       `json:"season_number"`
+	EpisodeNumber int32       `json:"episode_number"`
+	EpisodeName   *string     `json:"episode_name"`
+	AirDate       pgtype.Date `json:"air_date"`
+	Runtime       *int32      `json:"runtime"`
+}
+
+// Episode Schedules
+func (q *Queries) UpsertEpisodeSchedule(ctx context.Context, arg UpsertEpisodeScheduleParams) error {
+	_, err := q.db.Exec(ctx, upsertEpisodeSchedule,
+		arg.ShowApiID,
+		arg.ShowName,
+		arg.SeasonNumber,
+		arg.EpisodeNumber,
+		arg.EpisodeName,
+		arg.AirDate,
+		arg.Runtime,
+	)
+	return err
+}
+
+const upsertRating = `-- name: UpsertRating :exec
+INSERT INTO ratings (user_id, api_id, type, season_number, rating)
+VALUES ($1, $2, $3, $4, $5) ON CONFLICT (user_id, api_id, type, season_number) DO
+UPDATE SET rating = EXCLUDED.rating
+`
+
+type UpsertRatingParams struct {
+	UserID       int64  `json:"user_id"`
+	ApiID        int64  `json:"api_id"`
+	Type         string `json:"type"`
+	SeasonNumber int32  `json:"season_number"`
+	Rating       int16  `json:"rating"`
+}
+
+func (q *Queries) UpsertRating(ctx context.Context, arg UpsertRatingParams) error {
+	_, err := q.db.Exec(ctx, upsertRating,
+		arg.UserID,
+		arg.ApiID,
+		arg.Type,
+		arg.SeasonNumber,
+		arg.Rating,
+	)
+	return err
+}
+
+const upsertShowCheckSchedule = `-- name: UpsertShowCheckSchedule :exec
+INSERT INTO show_check_schedules (show_api_id, show_status, next_check_at, last_checked_at)
+VALUES ($1, $2, $3, NOW()) ON CONFLICT (show_api_id) DO
+UPDATE SET
+    show_status = EXCLUDED.show_status,
+    next_check_at = EXCLUDED.next_check_at,
+    last_checked_at = EXCLUDED.last_checked_at
+`
+
+type UpsertShowCheckScheduleParams struct {
+	ShowApiID   int64              `json:"show_api_id"`
+	ShowStatus  string             `json:"show_status"`
+	NextCheckAt pgtype.Timestamptz `json:"next_check_at"`
+}
+
+func (q *Queries) UpsertShowCheckSchedule(ctx context.Context, arg UpsertShowCheckScheduleParams) error {
+	_, err := q.db.Exec(ctx, upsertShowCheckSchedule, arg.ShowApiID, arg.ShowStatus, arg.NextCheckAt)
+	return err
+}
+
+const upsertShowNotificationState = `-- name: UpsertShowNotificationState :exec
+INSERT INTO show_notification_states (user_id, show_api_id, last_notified_seasons)
+VALUES ($1, $2, $3) ON CONFLICT (user_id, show_api_id) DO
+UPDATE SET
+    last_notified_seasons = EXCLUDED.last_notified_seasons,
+    snoozed_until = NULL
+`
+
+type UpsertShowNotificationStateParams struct {
+	UserID              int64 `json:"user_id"`
+	ShowApiID           int64 `json:"show_api_id"`
+	LastNotifiedSeasons int32 `json:"last_notified_seasons"`
+}
+
+func (q *Queries) UpsertShowNotificationState(ctx context.Context, arg UpsertShowNotificationStateParams) error {
+	_, err := q.db.Exec(ctx, upsertShowNotificationState, arg.UserID, arg.ShowApiID, arg.LastNotifiedSeasons)
+	return err
+}
+
+const upsertUserSettings = `-- name: UpsertUserSettings :exec
+INSERT INTO user_settings (user_id, timezone, quiet_hours_start, quiet_hours_end, notify_new_seasons,
+                           notify_status_changes, notify_episode_reminders, notify_releases, notify_digest,
+                           digest_mode, region, subscribed_providers, notify_availability)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) ON CONFLICT (user_id) DO
+UPDATE SET
+    timezone = EXCLUDED.timezone,
+    quiet_hours_start = EXCLUDED.quiet_hours_start,
+    quiet_hours_end = EXCLUDED.quiet_hours_end,
+    notify_new_seasons = EXCLUDED.notify_new_seasons,
+    notify_status_changes = EXCLUDED.notify_status_changes,
+    notify_episode_reminders = EXCLUDED.notify_episode_reminders,
+    notify_releases = EXCLUDED.notify_releases,
+    notify_digest = EXCLUDED.notify_digest,
+    digest_mode = EXCLUDED.digest_mode,
+    region = EXCLUDED.region,
+    subscribed_providers = EXCLUDED.subscribed_providers,
+    notify_availability = EXCLUDED.notify_availability
+`
+
+type UpsertUserSettingsParams struct {
+	UserID                 int64   `json:"user_id"`
+	Timezone               string  `json:"timezone"`
+	QuietHoursStart        *int32  `json:"quiet_hours_start"`
+	QuietHoursEnd          *int32  `json:"quiet_hours_end"`
+	NotifyNewSeasons       bool    `json:"notify_new_seasons"`
+	NotifyStatusChanges    bool    `json:"notify_status_changes"`
+	NotifyEpisodeReminders bool    `json:"notify_episode_reminders"`
+	NotifyReleases         bool    `json:"notify_releases"`
+	NotifyDigest           bool    `json:"notify_digest"`
+	DigestMode             string  `json:"digest_mode"`
+	Region                 string  `json:"region"`
+	SubscribedProviders    []int32 `json:"subscribed_providers"`
+	NotifyAvailability     bool    `json:"notify_availability"`
+}
+
+func (q *Queries) UpsertUserSettings(ctx context.Context, arg UpsertUserSettingsParams) error {
+	_, err := q.db.Exec(ctx, upsertUserSettings,
+		arg.UserID,
+		arg.Timezone,
+		arg.QuietHoursStart,
+		arg.QuietHoursEnd,
+		arg.NotifyNewSeasons,
+		arg.NotifyStatusChanges,
+		arg.NotifyEpisodeReminders,
+		arg.NotifyReleases,
+		arg.NotifyDigest,
+		arg.DigestMode,
+		arg.Region,
+		arg.SubscribedProviders,
+		arg.NotifyAvailability,
+	)
+	return err
+}
+
 const upsertWorkerState = `-- name: UpsertWorkerState :one
 INSERT INTO worker_states (worker_id, worker_type, status, last_check_time, next_check_time,
                            error, shows_checked, updates_found, created_at, updated_at)
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type EpisodeRepositoryInterface interface {
+	UpsertEpisodeSchedule(ctx context.Context, params database.UpsertEpisodeScheduleParams) error
+	GetEpisodeSchedule(ctx context.Context, showAPIID int64, seasonNumber, episodeNumber int32) (database.EpisodeSchedule, error)
+	GetLastScheduledEpisode(ctx context.Context, showAPIID int64, seasonNumber int32) (int32, error)
+	GetDueEpisodeReminders(ctx context.Context, airDate pgtype.Date) ([]database.GetDueEpisodeRemindersRow, error)
+	CreateEpisodeReminder(ctx context.Context, params database.CreateEpisodeReminderParams) error
+	MarkEpisodeReminderWatched(ctx context.Context, params database.MarkEpisodeReminderWatchedParams) (bool, error)
+	MarkEpisodeWatched(ctx context.Context, params database.MarkEpisodeWatchedParams) (bool, error)
+	UnmarkEpisodeWatched(ctx context.Context, userID, showAPIID int64, seasonNumber, episodeNumber int32) (bool, error)
+	DeleteEpisodeWatchesAfter(ctx context.Context, userID, showAPIID int64, seasonNumber int32) ([]database.DeleteEpisodeWatchesAfterRow, error)
+	RestoreEpisodeWatch(ctx context.Context, params database.RestoreEpisodeWatchParams) error
+	GetWatchedEpisodes(ctx context.Context, userID, showAPIID int64) ([]database.GetWatchedEpisodesRow, error)
+	GetEpisodeWatchTotals(ctx context.Context, userID, showAPIID int64) (database.GetEpisodeWatchTotalsRow, error)
+}
+
+type EpisodeRepository struct {
+	q *database.Queries
+}
+
+// NewEpisodeRepository creates a new repository of episode schedules, reminders and watches
+func NewEpisodeRepository(db database.DBTX) EpisodeRepositoryInterface {
+	return &EpisodeRepository{
+		q: database.New(db),
+	}
+}
+
+func (r *EpisodeRepository) UpsertEpisodeSchedule(ctx context.Context, params database.UpsertEpisodeScheduleParams) error {
+	return r.q.UpsertEpisodeSchedule(ctx, params)
+}
+
+func (r *EpisodeRepository) GetEpisodeSchedule(ctx context.Context, showAPIID int64, seasonNumber, episodeNumber int32) (database.EpisodeSchedule, error) {
+	return r.q.GetEpisodeSchedule(ctx, database.GetEpisodeScheduleParams{
+		ShowApiID:     showAPIID,
+		SeasonNumber:  seasonNumber,
+		EpisodeNumber: episodeNumber,
+	})
+}
+
+func (r *EpisodeRepository) GetLastScheduledEpisode(ctx context.Context, showAPIID int64, seasonNumber int32) (int32, error) {
+	return r.q.GetLastScheduledEpisode(ctx, database.GetLastScheduledEpisodeParams{
+		ShowApiID:    showAPIID,
+		SeasonNumber: seasonNumber,
+	})
+}
+
+func (r *EpisodeRepository) GetDueEpisodeReminders(ctx context.Context, airDate pgtype.Date) ([]database.GetDueEpisodeRemindersRow, error) {
+	return r.q.GetDueEpisodeReminders(ctx, airDate)
+}
+
+func (r *EpisodeRepository) CreateEpisodeReminder(ctx context.Context, params database.CreateEpisodeReminderParams) error {
+	return r.q.CreateEpisodeReminder(ctx, params)
+}
+
+// MarkEpisodeReminderWatched reports whether the reminder was still unwatched before this call
+func (r *EpisodeRepository) MarkEpisodeReminderWatched(ctx context.Context, params database.MarkEpisodeReminderWatchedParams) (bool, error) {
+	affected, err := r.q.MarkEpisodeReminderWatched(ctx, params)
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// MarkEpisodeWatched reports whether the episode was not watched before this call
+func (r *EpisodeRepository) MarkEpisodeWatched(ctx context.Context, params database.MarkEpisodeWatchedParams) (bool, error) {
+	affected, err := r.q.MarkEpisodeWatched(ctx, params)
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// UnmarkEpisodeWatched reports whether the episode was watched before this call
+func (r *EpisodeRepository) UnmarkEpisodeWatched(ctx context.Context, userID, showAPIID int64, seasonNumber, episodeNumber int32) (bool, error) {
+	affected, err := r.q.UnmarkEpisodeWatched(ctx, database.UnmarkEpisodeWatchedParams{
+		UserID:        userID,
+		ShowApiID:     showAPIID,
+		SeasonNumber:  seasonNumber,
+		EpisodeNumber: episodeNumber,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// DeleteEpisodeWatchesAfter removes the watched episodes of the seasons after the given one and returns them
+func (r *EpisodeRepository) DeleteEpisodeWatchesAfter(ctx context.Context, userID, showAPIID int64, seasonNumber int32) ([]database.DeleteEpisodeWatchesAfterRow, error) {
+	return r.q.DeleteEpisodeWatchesAfter(ctx, database.DeleteEpisodeWatchesAfterParams{
+		UserID:       userID,
+		ShowApiID:    showAPIID,
+		SeasonNumber: seasonNumber,
+	})
+}
+
+// RestoreEpisodeWatch puts back a removed episode watch with its original watch time
+func (r *EpisodeRepository) RestoreEpisodeWatch(ctx context.Context, params database.RestoreEpisodeWatchParams) error {
+	return r.q.RestoreEpisodeWatch(ctx, params)
+}
+
+func (r *EpisodeRepository) GetWatchedEpisodes(ctx context.Context, userID, showAPIID int64) ([]database.GetWatchedEpisodesRow, error) {
+	return r.q.GetWatchedEpisodes(ctx, database.GetWatchedEpisodesParams{
+		UserID:    userID,
+		ShowApiID: showAPIID,
+	})
+}
+
+func (r *EpisodeRepository) GetEpisodeWatchTotals(ctx context.Context, userID, showAPIID int64) (database.GetEpisodeWatchTotalsRow, error) {
+	return r.q.GetEpisodeWatchTotals(ctx, database.GetEpisodeWatchTotalsParams{
+		UserID:    userID,
+		ShowApiID: showAPIID,
+	})
+}
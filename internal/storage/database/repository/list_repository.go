@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/google/uuid"
+)
+
+type ListRepositoryInterface interface {
+	CreateUserList(ctx context.Context, userID int64, name string) (uuid.UUID, error)
+	GetUserLists(ctx context.Context, userID int64) ([]database.GetUserListsRow, error)
+	GetUserList(ctx context.Context, id uuid.UUID, userID int64) (database.GetUserListRow, error)
+	RenameUserList(ctx context.Context, id uuid.UUID, userID int64, name string) (bool, error)
+	DeleteUserList(ctx context.Context, id uuid.UUID, userID int64) (bool, error)
+	GetListsWithTitle(ctx context.Context, userID int64, showAPIID int64, showType string) ([]database.GetListsWithTitleRow, error)
+	AddListItem(ctx context.Context, params database.AddListItemParams) error
+	RemoveListItem(ctx context.Context, listID uuid.UUID, showAPIID int64, showType string) (bool, error)
+	GetListItems(ctx context.Context, listID uuid.UUID) ([]database.GetListItemsRow, error)
+}
+
+type ListRepository struct {
+	q *database.Queries
+}
+
+// NewListRepository creates a new repository of custom lists and their titles
+func NewListRepository(db database.DBTX) ListRepositoryInterface {
+	return &ListRepository{
+		q: database.New(db),
+	}
+}
+
+func (r *ListRepository) CreateUserList(ctx context.Context, userID int64, name string) (uuid.UUID, error) {
+	return r.q.CreateUserList(ctx, database.CreateUserListParams{
+		UserID: userID,
+		Name:   name,
+	})
+}
+
+// GetUserLists returns the lists of a user with their item counts, oldest first
+func (r *ListRepository) GetUserLists(ctx context.Context, userID int64) ([]database.GetUserListsRow, error) {
+	return r.q.GetUserLists(ctx, userID)
+}
+
+func (r *ListRepository) GetUserList(ctx context.Context, id uuid.UUID, userID int64) (database.GetUserListRow, error) {
+	return r.q.GetUserList(ctx, database.GetUserListParams{
+		ID:     id,
+		UserID: userID,
+	})
+}
+
+// RenameUserList reports whether the list exists and belongs to the user
+func (r *ListRepository) RenameUserList(ctx context.Context, id uuid.UUID, userID int64, name string) (bool, error) {
+	rows, err := r.q.RenameUserList(ctx, database.RenameUserListParams{
+		ID:     id,
+		UserID: userID,
+		Name:   name,
+	})
+	return rows > 0, err
+}
+
+// DeleteUserList removes a list with its items and reports whether it existed
+func (r *ListRepository) DeleteUserList(ctx context.Context, id uuid.UUID, userID int64) (bool, error) {
+	rows, err := r.q.DeleteUserList(ctx, database.DeleteUserListParams{
+		ID:     id,
+		UserID: userID,
+	})
+	return rows > 0, err
+}
+
+// GetListsWithTitle returns every list of a user and whether the title is on it
+func (r *ListRepository) GetListsWithTitle(ctx context.Context, userID int64, showAPIID int64, showType string) ([]database.GetListsWithTitleRow, error) {
+	return r.q.GetListsWithTitle(ctx, database.GetListsWithTitleParams{
+		UserID:    userID,
+		ShowApiID: showAPIID,
+		Type:      showType,
+	})
+}
+
+// AddListItem puts a title on a list, adding it twice is a no-op
+func (r *ListRepository) AddListItem(ctx context.Context, params database.AddListItemParams) error {
+	return r.q.AddListItem(ctx, params)
+}
+
+func (r *ListRepository) RemoveListItem(ctx context.Context, listID uuid.UUID, showAPIID int64, showType string) (bool, error) {
+	rows, err := r.q.RemoveListItem(ctx, database.RemoveListItemParams{
+		ListID:    listID,
+		ShowApiID: showAPIID,
+		Type:      showType,
+	})
+	return rows > 0, err
+}
+
+func (r *ListRepository) GetListItems(ctx context.Context, listID uuid.UUID) ([]database.GetListItemsRow, error) {
+	return r.q.GetListItems(ctx, listID)
+}
@@ -13,13 +13,19 @@ import (
 
 // Manager wraps SQLC queries with connection management
 type Manager struct {
-	Users      UserRepositoryInterface
-	Movies     MovieRepositoryInterface
-	TVShows    TVShowRepositoryInterface
-	Watchlists WatchlistRepositoryInterface
-	Worker     WorkerRepositoryInterface
-	rawQueries *database.Queries
-	pool       *pgxpool.Pool
+	Users         UserRepositoryInterface
+	Movies        MovieRepositoryInterface
+	TVShows       TVShowRepositoryInterface
+	Watchlists    WatchlistRepositoryInterface
+	Worker        WorkerRepositoryInterface
+	Episodes      EpisodeRepositoryInterface
+	Notifications NotificationRepositoryInterface
+	Settings      SettingsRepositoryInterface
+	WatchEvents   WatchEventRepositoryInterface
+	Ratings       RatingRepositoryInterface
+	Lists         ListRepositoryInterface
+	rawQueries    *database.Queries
+	pool          *pgxpool.Pool
 }
 
 type Tx struct {
@@ -28,11 +34,17 @@ type Tx struct {
 }
 
 type ReposTx struct {
-	Users      UserRepositoryInterface
-	Movies     MovieRepositoryInterface
-	TVShows    TVShowRepositoryInterface
-	Watchlists WatchlistRepositoryInterface
-	Worker     WorkerRepositoryInterface
+	Users         UserRepositoryInterface
+	Movies        MovieRepositoryInterface
+	TVShows       TVShowRepositoryInterface
+	Watchlists    WatchlistRepositoryInterface
+	Worker        WorkerRepositoryInterface
+	Episodes      EpisodeRepositoryInterface
+	Notifications NotificationRepositoryInterface
+	Settings      SettingsRepositoryInterface
+	WatchEvents   WatchEventRepositoryInterface
+	Ratings       RatingRepositoryInterface
+	Lists         ListRepositoryInterface
 }
 
 // connectSqlcWithPool connects to the database and returns a SQLC Queries instance with the underlying pool
@@ -78,13 +90,19 @@ func connectSqlcWithPool(config *config.Config, ctx context.Context) (*Manager,
 	)
 
 	return &Manager{
-		Users:      NewUserRepository(pool),
-		Movies:     NewMovieRepository(pool),
-		TVShows:    NewTVShowRepository(pool),
-		Watchlists: NewWatchlistRepository(pool),
-		Worker:     NewWorkerRepository(pool),
-		rawQueries: database.New(pool),
-		pool:       pool,
+		Users:         NewUserRepository(pool),
+		Movies:        NewMovieRepository(pool),
+		TVShows:       NewTVShowRepository(pool),
+		Watchlists:    NewWatchlistRepository(pool),
+		Worker:        NewWorkerRepository(pool),
+		Episodes:      NewEpisodeRepository(pool),
+		Notifications: NewNotificationRepository(pool),
+		Settings:      NewSettingsRepository(pool),
+		WatchEvents:   NewWatchEventRepository(pool),
+		Ratings:       NewRatingRepository(pool),
+		Lists:         NewListRepository(pool),
+		rawQueries:    database.New(pool),
+		pool:          pool,
 	}, nil
 }
 
@@ -116,11 +134,17 @@ func (m *Manager) BeginTx(ctx context.Context) (*Tx, error) {
 	return &Tx{
 		tx: tx,
 		Repos: &ReposTx{
-			Users:      NewUserRepository(tx),
-			Movies:     NewMovieRepository(tx),
-			TVShows:    NewTVShowRepository(tx),
-			Watchlists: NewWatchlistRepository(tx),
-			Worker:     NewWorkerRepository(tx),
+			Users:         NewUserRepository(tx),
+			Movies:        NewMovieRepository(tx),
+			TVShows:       NewTVShowRepository(tx),
+			Watchlists:    NewWatchlistRepository(tx),
+			Worker:        NewWorkerRepository(tx),
+			Episodes:      NewEpisodeRepository(tx),
+			Notifications: NewNotificationRepository(tx),
+			Settings:      NewSettingsRepository(tx),
+			WatchEvents:   NewWatchEventRepository(tx),
+			Ratings:       NewRatingRepository(tx),
+			Lists:         NewListRepository(tx),
 		},
 	}, nil
 }
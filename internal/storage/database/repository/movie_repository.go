@@ -7,6 +7,7 @@ import (
 
 type MovieRepositoryInterface interface {
 	GetUserMovies(ctx context.Context, userID int64) ([]database.GetUserMoviesRow, error)
+	GetUserMovie(ctx context.Context, apiID int64, userID int64) (database.GetUserMovieRow, error)
 	MovieExists(ctx context.Context, apiID int64, userID int64) (bool, error)
 	CreateMovie(ctx context.Context, params database.CreateMovieParams) error
 	UpdateMovie(ctx context.Context, params database.UpdateMovieParams) error
@@ -27,6 +28,13 @@ func (r *MovieRepository) GetUserMovies(ctx context.Context, userID int64) ([]da
 	return r.q.GetUserMovies(ctx, userID)
 }
 
+func (r *MovieRepository) GetUserMovie(ctx context.Context, apiID int64, userID int64) (database.GetUserMovieRow, error) {
+	return r.q.GetUserMovie(ctx, database.GetUserMovieParams{
+		ApiID:  apiID,
+		UserID: userID,
+	})
+}
+
 func (r *MovieRepository) MovieExists(ctx context.Context, apiID int64, userID int64) (bool, error) {
 	return r.q.MovieExists(ctx, database.MovieExistsParams{
 		ApiID:  apiID,
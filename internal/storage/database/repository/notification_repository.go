@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"time"
+)
+
+type NotificationRepositoryInterface interface {
+	EnqueueNotification(ctx context.Context, params database.EnqueueNotificationParams) (uuid.UUID, error)
+	GetDueNotifications(ctx context.Context, limit int32) ([]database.GetDueNotificationsRow, error)
+	MarkNotificationSent(ctx context.Context, id uuid.UUID) error
+	MarkNotificationRetry(ctx context.Context, id uuid.UUID, nextAttempt time.Time, lastError string) error
+	MarkNotificationFailed(ctx context.Context, id uuid.UUID, lastError string) error
+	DeferNotification(ctx context.Context, id uuid.UUID, nextAttempt time.Time) error
+	MarkNotificationSkipped(ctx context.Context, id uuid.UUID, reason string) error
+	MarkNotificationPosterSent(ctx context.Context, id uuid.UUID) error
+	HoldNotification(ctx context.Context, params database.HoldNotificationParams) error
+	GetHeldNotificationUsers(ctx context.Context) ([]database.GetHeldNotificationUsersRow, error)
+	GetHeldNotifications(ctx context.Context, userID int64) ([]database.GetHeldNotificationsRow, error)
+	MarkNotificationsDigested(ctx context.Context, ids []uuid.UUID) error
+}
+
+type NotificationRepository struct {
+	q *database.Queries
+}
+
+// NewNotificationRepository creates a new notification outbox repository
+func NewNotificationRepository(db database.DBTX) NotificationRepositoryInterface {
+	return &NotificationRepository{
+		q: database.New(db),
+	}
+}
+
+func (r *NotificationRepository) EnqueueNotification(ctx context.Context, params database.EnqueueNotificationParams) (uuid.UUID, error) {
+	return r.q.EnqueueNotification(ctx, params)
+}
+
+func (r *NotificationRepository) GetDueNotifications(ctx context.Context, limit int32) ([]database.GetDueNotificationsRow, error) {
+	return r.q.GetDueNotifications(ctx, limit)
+}
+
+func (r *NotificationRepository) MarkNotificationSent(ctx context.Context, id uuid.UUID) error {
+	return r.q.MarkNotificationSent(ctx, id)
+}
+
+func (r *NotificationRepository) MarkNotificationRetry(ctx context.Context, id uuid.UUID, nextAttempt time.Time, lastError string) error {
+	return r.q.MarkNotificationRetry(ctx, database.MarkNotificationRetryParams{
+		ID:            id,
+		NextAttemptAt: pgtype.Timestamptz{Time: nextAttempt, Valid: true},
+		LastError:     &lastError,
+	})
+}
+
+func (r *NotificationRepository) MarkNotificationFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	return r.q.MarkNotificationFailed(ctx, database.MarkNotificationFailedParams{
+		ID:        id,
+		LastError: &lastError,
+	})
+}
+
+func (r *NotificationRepository) DeferNotification(ctx context.Context, id uuid.UUID, nextAttempt time.Time) error {
+	return r.q.DeferNotification(ctx, database.DeferNotificationParams{
+		ID:            id,
+		NextAttemptAt: pgtype.Timestamptz{Time: nextAttempt, Valid: true},
+	})
+}
+
+func (r *NotificationRepository) MarkNotificationSkipped(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.q.MarkNotificationSkipped(ctx, database.MarkNotificationSkippedParams{
+		ID:        id,
+		LastError: &reason,
+	})
+}
+
+// MarkNotificationPosterSent records that the poster of a split message went out, retries only send the text
+func (r *NotificationRepository) MarkNotificationPosterSent(ctx context.Context, id uuid.UUID) error {
+	return r.q.MarkNotificationPosterSent(ctx, id)
+}
+
+// HoldNotification stores a digest entry, it is never sent on its own
+func (r *NotificationRepository) HoldNotification(ctx context.Context, params database.HoldNotificationParams) error {
+	return r.q.HoldNotification(ctx, params)
+}
+
+func (r *NotificationRepository) GetHeldNotificationUsers(ctx context.Context) ([]database.GetHeldNotificationUsersRow, error) {
+	return r.q.GetHeldNotificationUsers(ctx)
+}
+
+func (r *NotificationRepository) GetHeldNotifications(ctx context.Context, userID int64) ([]database.GetHeldNotificationsRow, error) {
+	return r.q.GetHeldNotifications(ctx, userID)
+}
+
+func (r *NotificationRepository) MarkNotificationsDigested(ctx context.Context, ids []uuid.UUID) error {
+	return r.q.MarkNotificationsDigested(ctx, ids)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+)
+
+type RatingRepositoryInterface interface {
+	UpsertRating(ctx context.Context, params database.UpsertRatingParams) error
+	SetRatingReview(ctx context.Context, params database.SetRatingReviewParams) (bool, error)
+	GetTitleRatings(ctx context.Context, userID int64, apiID int64, showType string) ([]database.GetTitleRatingsRow, error)
+	DeleteTitleRatings(ctx context.Context, userID int64, apiID int64, showType string) ([]database.DeleteTitleRatingsRow, error)
+	RestoreRating(ctx context.Context, params database.RestoreRatingParams) error
+	GetRatingSummary(ctx context.Context, userID int64, showType string) (database.GetRatingSummaryRow, error)
+	GetTopRated(ctx context.Context, userID int64, showType string, limit int32) ([]database.GetTopRatedRow, error)
+}
+
+type RatingRepository struct {
+	q *database.Queries
+}
+
+// NewRatingRepository creates a new repository of title ratings and reviews
+func NewRatingRepository(db database.DBTX) RatingRepositoryInterface {
+	return &RatingRepository{
+		q: database.New(db),
+	}
+}
+
+// UpsertRating stores a rating, rating a title again replaces the score and keeps the review
+func (r *RatingRepository) UpsertRating(ctx context.Context, params database.UpsertRatingParams) error {
+	return r.q.UpsertRating(ctx, params)
+}
+
+// SetRatingReview attaches a review to an existing rating and reports whether the rating was found
+func (r *RatingRepository) SetRatingReview(ctx context.Context, params database.SetRatingReviewParams) (bool, error) {
+	rows, err := r.q.SetRatingReview(ctx, params)
+	return rows > 0, err
+}
+
+// GetTitleRatings returns the ratings of one title, the whole title first and then its seasons
+func (r *RatingRepository) GetTitleRatings(ctx context.Context, userID int64, apiID int64, showType string) ([]database.GetTitleRatingsRow, error) {
+	return r.q.GetTitleRatings(ctx, database.GetTitleRatingsParams{
+		UserID: userID,
+		ApiID:  apiID,
+		Type:   showType,
+	})
+}
+
+// DeleteTitleRatings removes the ratings of one title and its seasons and returns them
+func (r *RatingRepository) DeleteTitleRatings(ctx context.Context, userID int64, apiID int64, showType string) ([]database.DeleteTitleRatingsRow, error) {
+	return r.q.DeleteTitleRatings(ctx, database.DeleteTitleRatingsParams{
+		UserID: userID,
+		ApiID:  apiID,
+		Type:   showType,
+	})
+}
+
+// RestoreRating puts back a removed rating, a rating given in the meantime is kept
+func (r *RatingRepository) RestoreRating(ctx context.Context, params database.RestoreRatingParams) error {
+	return r.q.RestoreRating(ctx, params)
+}
+
+// GetRatingSummary counts and averages the title ratings of one type, constants.AllType covers both
+func (r *RatingRepository) GetRatingSummary(ctx context.Context, userID int64, showType string) (database.GetRatingSummaryRow, error) {
+	return r.q.GetRatingSummary(ctx, database.GetRatingSummaryParams{
+		UserID: userID,
+		Type:   showType,
+	})
+}
+
+// GetTopRated returns the best rated titles of one type, constants.AllType covers both
+func (r *RatingRepository) GetTopRated(ctx context.Context, userID int64, showType string, limit int32) ([]database.GetTopRatedRow, error) {
+	return r.q.GetTopRated(ctx, database.GetTopRatedParams{
+		UserID: userID,
+		Type:   showType,
+		Limit:  limit,
+	})
+}
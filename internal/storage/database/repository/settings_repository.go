@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/jackc/pgx/v5"
+)
+
+type SettingsRepositoryInterface interface {
+	GetUserSettings(ctx context.Context, userID int64) (database.UserSetting, error)
+	UpsertUserSettings(ctx context.Context, params database.UpsertUserSettingsParams) error
+}
+
+type SettingsRepository struct {
+	q *database.Queries
+}
+
+// NewSettingsRepository creates a new user settings repository
+func NewSettingsRepository(db database.DBTX) SettingsRepositoryInterface {
+	return &SettingsRepository{
+		q: database.New(db),
+	}
+}
+
+// GetUserSettings returns the stored settings, or the defaults when the user never changed them
+func (r *SettingsRepository) GetUserSettings(ctx context.Context, userID int64) (database.UserSetting, error) {
+	settings, err := r.q.GetUserSettings(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return database.UserSetting{
+			UserID:                 userID,
+			Timezone:               "UTC",
+			NotifyNewSeasons:       true,
+			NotifyStatusChanges:    true,
+			NotifyEpisodeReminders: true,
+			NotifyReleases:         true,
+			NotifyDigest:           true,
+			DigestMode:             constants.DigestModeOff,
+			Region:                 constants.DefaultRegion,
+			SubscribedProviders:    []int32{},
+			NotifyAvailability:     true,
+		}, nil
+	}
+
+	return settings, err
+}
+
+func (r *SettingsRepository) UpsertUserSettings(ctx context.Context, params database.UpsertUserSettingsParams) error {
+	return r.q.UpsertUserSettings(ctx, params)
+}
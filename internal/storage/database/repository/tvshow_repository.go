@@ -3,7 +3,9 @@ package repository
 import (
 	"context"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/jackc/pgx/v5/pgtype"
 	"strings"
+	"time"
 )
 
 type TVShowRepositoryInterface interface {
@@ -13,7 +15,14 @@ type TVShowRepositoryInterface interface {
 	TVShowExists(ctx context.Context, apiID int64, userID int64) (bool, error)
 	CreateTVShow(ctx context.Context, params database.CreateTVShowParams) error
 	UpdateTVShow(ctx context.Context, params database.UpdateTVShowParams) error
+	UpdateTVShowStatus(ctx context.Context, apiID int64, userID int64, status string) error
+	UpdateTVShowWatchStatus(ctx context.Context, apiID int64, userID int64, watchStatus string) error
 	SoftDeleteTVShow(ctx context.Context, apiID int64, userID int64) error
+	GetShowNotificationState(ctx context.Context, userID int64, apiID int64) (database.GetShowNotificationStateRow, error)
+	UpsertShowNotificationState(ctx context.Context, userID int64, apiID int64, seasons int32) error
+	SnoozeShowNotification(ctx context.Context, userID int64, apiID int64, until time.Time) (bool, error)
+	GetShowCheckSchedules(ctx context.Context) (map[int64]time.Time, error)
+	UpsertShowCheckSchedule(ctx context.Context, apiID int64, status string, nextCheck time.Time) error
 }
 
 type TVShowRepository struct {
@@ -66,9 +75,78 @@ func (r *TVShowRepository) UpdateTVShow(ctx context.Context, params database.Upd
 	return r.q.UpdateTVShow(ctx, params)
 }
 
+func (r *TVShowRepository) UpdateTVShowStatus(ctx context.Context, apiID int64, userID int64, status string) error {
+	return r.q.UpdateTVShowStatus(ctx, database.UpdateTVShowStatusParams{
+		ApiID:  apiID,
+		UserID: userID,
+		Status: status,
+	})
+}
+
+// UpdateTVShowWatchStatus sets where the user is with a show, see the constants.WatchStatus values
+func (r *TVShowRepository) UpdateTVShowWatchStatus(ctx context.Context, apiID int64, userID int64, watchStatus string) error {
+	return r.q.UpdateTVShowWatchStatus(ctx, database.UpdateTVShowWatchStatusParams{
+		ApiID:       apiID,
+		UserID:      userID,
+		WatchStatus: watchStatus,
+	})
+}
+
 func (r *TVShowRepository) SoftDeleteTVShow(ctx context.Context, apiID int64, userID int64) error {
 	return r.q.SoftDeleteTVShow(ctx, database.SoftDeleteTVShowParams{
 		ApiID:  apiID,
 		UserID: userID,
 	})
 }
+
+func (r *TVShowRepository) GetShowNotificationState(ctx context.Context, userID int64, apiID int64) (database.GetShowNotificationStateRow, error) {
+	return r.q.GetShowNotificationState(ctx, database.GetShowNotificationStateParams{
+		UserID:    userID,
+		ShowApiID: apiID,
+	})
+}
+
+// UpsertShowNotificationState records the season count the user was notified about and clears any snooze
+func (r *TVShowRepository) UpsertShowNotificationState(ctx context.Context, userID int64, apiID int64, seasons int32) error {
+	return r.q.UpsertShowNotificationState(ctx, database.UpsertShowNotificationStateParams{
+		UserID:              userID,
+		ShowApiID:           apiID,
+		LastNotifiedSeasons: seasons,
+	})
+}
+
+// SnoozeShowNotification reports whether there was a notification state to snooze
+func (r *TVShowRepository) SnoozeShowNotification(ctx context.Context, userID int64, apiID int64, until time.Time) (bool, error) {
+	affected, err := r.q.SnoozeShowNotification(ctx, database.SnoozeShowNotificationParams{
+		UserID:       userID,
+		ShowApiID:    apiID,
+		SnoozedUntil: pgtype.Timestamptz{Time: until, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// GetShowCheckSchedules returns when each scheduled show is due, shows missing from the map were never checked
+func (r *TVShowRepository) GetShowCheckSchedules(ctx context.Context) (map[int64]time.Time, error) {
+	rows, err := r.q.GetShowCheckSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make(map[int64]time.Time, len(rows))
+	for _, row := range rows {
+		schedules[row.ShowApiID] = row.NextCheckAt.Time
+	}
+	return schedules, nil
+}
+
+func (r *TVShowRepository) UpsertShowCheckSchedule(ctx context.Context, apiID int64, status string, nextCheck time.Time) error {
+	return r.q.UpsertShowCheckSchedule(ctx, database.UpsertShowCheckScheduleParams{
+		ShowApiID:   apiID,
+		ShowStatus:  status,
+		NextCheckAt: pgtype.Timestamptz{Time: nextCheck, Valid: true},
+	})
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+)
+
+type WatchEventRepositoryInterface interface {
+	CreateWatchEvent(ctx context.Context, params database.CreateWatchEventParams) error
+	GetWatchEvents(ctx context.Context, userID int64, apiID int64, showType string) ([]database.GetWatchEventsRow, error)
+	DeleteWatchEvents(ctx context.Context, userID int64, apiID int64, showType string) ([]database.DeleteWatchEventsRow, error)
+	RestoreWatchEvent(ctx context.Context, params database.RestoreWatchEventParams) error
+	GetWatchTotals(ctx context.Context, userID int64, showType string) (database.GetWatchTotalsRow, error)
+}
+
+type WatchEventRepository struct {
+	q *database.Queries
+}
+
+// NewWatchEventRepository creates a new repository of dated watches
+func NewWatchEventRepository(db database.DBTX) WatchEventRepositoryInterface {
+	return &WatchEventRepository{
+		q: database.New(db),
+	}
+}
+
+func (r *WatchEventRepository) CreateWatchEvent(ctx context.Context, params database.CreateWatchEventParams) error {
+	return r.q.CreateWatchEvent(ctx, params)
+}
+
+// GetWatchEvents returns the watches of one title, the latest first
+func (r *WatchEventRepository) GetWatchEvents(ctx context.Context, userID int64, apiID int64, showType string) ([]database.GetWatchEventsRow, error) {
+	return r.q.GetWatchEvents(ctx, database.GetWatchEventsParams{
+		UserID: userID,
+		ApiID:  apiID,
+		Type:   showType,
+	})
+}
+
+// DeleteWatchEvents removes every watch of one title and returns them
+func (r *WatchEventRepository) DeleteWatchEvents(ctx context.Context, userID int64, apiID int64, showType string) ([]database.DeleteWatchEventsRow, error) {
+	return r.q.DeleteWatchEvents(ctx, database.DeleteWatchEventsParams{
+		UserID: userID,
+		ApiID:  apiID,
+		Type:   showType,
+	})
+}
+
+// RestoreWatchEvent puts back a removed watch with its original creation time
+func (r *WatchEventRepository) RestoreWatchEvent(ctx context.Context, params database.RestoreWatchEventParams) error {
+	return r.q.RestoreWatchEvent(ctx, params)
+}
+
+// GetWatchTotals sums up the watches of one type, rewatches count towards watches and runtime
+func (r *WatchEventRepository) GetWatchTotals(ctx context.Context, userID int64, showType string) (database.GetWatchTotalsRow, error) {
+	return r.q.GetWatchTotals(ctx, database.GetWatchTotalsParams{
+		UserID: userID,
+		Type:   showType,
+	})
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/google/uuid"
 )
 
 type WatchlistRepositoryInterface interface {
@@ -10,8 +11,18 @@ type WatchlistRepositoryInterface interface {
 	GetUserWatchlist(ctx context.Context, showAPIID int64, userID int64) (database.GetUserWatchlistRow, error)
 	WatchlistExists(ctx context.Context, showAPIID int64, userID int64, showType string) (bool, error)
 	GetUserWatchlists(ctx context.Context, userID int64) ([]database.GetUserWatchlistsRow, error)
-	GetUserWatchlistsWithType(ctx context.Context, userID int64, showType string) ([]database.GetUserWatchlistsWithTypeRow, error)
-	DeleteWatchlist(ctx context.Context, showAPIID int64, userID int64) error
+	GetWatchlistEntries(ctx context.Context, userID int64, showType, priority, sortBy string) ([]database.GetWatchlistEntriesRow, error)
+	GetWatchlistEntry(ctx context.Context, userID int64, showAPIID int64, showType string) (database.GetWatchlistEntryRow, error)
+	UpdateWatchlistPriority(ctx context.Context, userID int64, showAPIID int64, showType, priority string) (bool, error)
+	UpdateWatchlistNote(ctx context.Context, userID int64, showAPIID int64, showType string, note *string) (bool, error)
+	UpdateWatchlistPosition(ctx context.Context, id uuid.UUID, position int32) error
+	GetWatchlistsByType(ctx context.Context, showType string) ([]database.GetWatchlistsByTypeRow, error)
+	UpdateWatchlistRelease(ctx context.Context, params database.UpdateWatchlistReleaseParams) error
+	UpdateWatchlistShowRelease(ctx context.Context, params database.UpdateWatchlistShowReleaseParams) error
+	GetWatchlistAvailability(ctx context.Context) ([]database.GetWatchlistAvailabilityRow, error)
+	UpdateWatchlistProviders(ctx context.Context, id uuid.UUID, providers []int32) error
+	ResetWatchlistProviders(ctx context.Context, userID int64) error
+	DeleteWatchlist(ctx context.Context, showAPIID int64, userID int64, showType string) error
 }
 
 type WatchlistRepository struct {
@@ -48,16 +59,87 @@ func (r *WatchlistRepository) GetUserWatchlists(ctx context.Context, userID int6
 	return r.q.GetUserWatchlists(ctx, userID)
 }
 
-func (r *WatchlistRepository) GetUserWatchlistsWithType(ctx context.Context, userID int64, showType string) ([]database.GetUserWatchlistsWithTypeRow, error) {
-	return r.q.GetUserWatchlistsWithType(ctx, database.GetUserWatchlistsWithTypeParams{
-		UserID: userID,
-		Type:   showType,
+// GetWatchlistEntries returns the watchlist of a user in the given order, showType and priority can be
+// constants.AllType to skip that filter. Ties are broken by the manual order.
+func (r *WatchlistRepository) GetWatchlistEntries(ctx context.Context, userID int64, showType, priority, sortBy string) ([]database.GetWatchlistEntriesRow, error) {
+	return r.q.GetWatchlistEntries(ctx, database.GetWatchlistEntriesParams{
+		UserID:   userID,
+		Type:     showType,
+		Priority: priority,
+		SortBy:   sortBy,
 	})
 }
 
-func (r *WatchlistRepository) DeleteWatchlist(ctx context.Context, showAPIID int64, userID int64) error {
+func (r *WatchlistRepository) GetWatchlistEntry(ctx context.Context, userID int64, showAPIID int64, showType string) (database.GetWatchlistEntryRow, error) {
+	return r.q.GetWatchlistEntry(ctx, database.GetWatchlistEntryParams{
+		UserID:    userID,
+		ShowApiID: showAPIID,
+		Type:      showType,
+	})
+}
+
+// UpdateWatchlistPriority reports whether the title is on the watchlist of the user
+func (r *WatchlistRepository) UpdateWatchlistPriority(ctx context.Context, userID int64, showAPIID int64, showType, priority string) (bool, error) {
+	rows, err := r.q.UpdateWatchlistPriority(ctx, database.UpdateWatchlistPriorityParams{
+		UserID:    userID,
+		ShowApiID: showAPIID,
+		Type:      showType,
+		Priority:  priority,
+	})
+	return rows > 0, err
+}
+
+// UpdateWatchlistNote sets or, with a nil note, clears the note of an entry and reports whether the entry exists
+func (r *WatchlistRepository) UpdateWatchlistNote(ctx context.Context, userID int64, showAPIID int64, showType string, note *string) (bool, error) {
+	rows, err := r.q.UpdateWatchlistNote(ctx, database.UpdateWatchlistNoteParams{
+		UserID:    userID,
+		ShowApiID: showAPIID,
+		Type:      showType,
+		Note:      note,
+	})
+	return rows > 0, err
+}
+
+func (r *WatchlistRepository) UpdateWatchlistPosition(ctx context.Context, id uuid.UUID, position int32) error {
+	return r.q.UpdateWatchlistPosition(ctx, database.UpdateWatchlistPositionParams{
+		ID:       id,
+		Position: position,
+	})
+}
+
+func (r *WatchlistRepository) GetWatchlistsByType(ctx context.Context, showType string) ([]database.GetWatchlistsByTypeRow, error) {
+	return r.q.GetWatchlistsByType(ctx, showType)
+}
+
+func (r *WatchlistRepository) UpdateWatchlistRelease(ctx context.Context, params database.UpdateWatchlistReleaseParams) error {
+	return r.q.UpdateWatchlistRelease(ctx, params)
+}
+
+func (r *WatchlistRepository) UpdateWatchlistShowRelease(ctx context.Context, params database.UpdateWatchlistShowReleaseParams) error {
+	return r.q.UpdateWatchlistShowRelease(ctx, params)
+}
+
+func (r *WatchlistRepository) GetWatchlistAvailability(ctx context.Context) ([]database.GetWatchlistAvailabilityRow, error) {
+	return r.q.GetWatchlistAvailability(ctx)
+}
+
+func (r *WatchlistRepository) UpdateWatchlistProviders(ctx context.Context, id uuid.UUID, providers []int32) error {
+	return r.q.UpdateWatchlistProviders(ctx, database.UpdateWatchlistProvidersParams{
+		ID:                 id,
+		AvailableProviders: providers,
+	})
+}
+
+// ResetWatchlistProviders forgets the stored availability of a user's watchlist, used when the region changes
+func (r *WatchlistRepository) ResetWatchlistProviders(ctx context.Context, userID int64) error {
+	return r.q.ResetWatchlistProviders(ctx, userID)
+}
+
+// DeleteWatchlist removes one entry, movie and TV IDs of TMDB overlap so the type is needed to tell them apart
+func (r *WatchlistRepository) DeleteWatchlist(ctx context.Context, showAPIID int64, userID int64, showType string) error {
 	return r.q.DeleteWatchlist(ctx, database.DeleteWatchlistParams{
 		ShowApiID: showAPIID,
 		UserID:    userID,
+		Type:      showType,
 	})
 }
@@ -4,15 +4,27 @@ import (
 	"context"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"time"
 )
 
 type WorkerRepositoryInterface interface {
 	GetWorkerState(ctx context.Context, workerID string) (database.WorkerState, error)
+	GetLatestWorkerState(ctx context.Context, workerType string) (database.WorkerState, error)
 	UpsertWorkerState(ctx context.Context, params database.UpsertWorkerStateParams) error
 	CreateWorkerTask(ctx context.Context, params database.CreateWorkerTaskParams) (uuid.UUID, error)
 	UpdateWorkerTask(ctx context.Context, params database.UpdateWorkerTaskParams) error
 	GetRecentTasks(ctx context.Context, params database.GetRecentTasksParams) ([]database.WorkerTask, error)
 	GetWorkerTask(ctx context.Context, id uuid.UUID) (database.WorkerTask, error)
+	UpdateWorkerTaskApiCallsSaved(ctx context.Context, id uuid.UUID, saved int32) error
+	UpdateWorkerCycleCursor(ctx context.Context, workerID string, cursor *int64) error
+	AbortRunningWorkerTasks(ctx context.Context, workerType, reason string) (int64, error)
+	AcquireWorkerLease(ctx context.Context, workerType, holderID string, expiresAt time.Time) (bool, error)
+	ReleaseWorkerLease(ctx context.Context, workerType, holderID string) error
+	GetWorkerPerformance(ctx context.Context) ([]database.WorkerPerformance, error)
+	SetWorkerPaused(ctx context.Context, workerType string, paused bool) (int64, error)
+	IsWorkerTypePaused(ctx context.Context, workerType string) (bool, error)
+	RollupWorkerTasks(ctx context.Context, before time.Time, limit int32) (database.RollupWorkerTasksRow, error)
 }
 
 type WorkerRepository struct {
@@ -29,6 +41,11 @@ func (r *WorkerRepository) GetWorkerState(ctx context.Context, workerID string)
 	return r.q.GetWorkerState(ctx, workerID)
 }
 
+// GetLatestWorkerState returns the most recently updated state of a worker type, the one its last leader left behind
+func (r *WorkerRepository) GetLatestWorkerState(ctx context.Context, workerType string) (database.WorkerState, error) {
+	return r.q.GetLatestWorkerState(ctx, workerType)
+}
+
 func (r *WorkerRepository) UpsertWorkerState(ctx context.Context, params database.UpsertWorkerStateParams) error {
 	_, err := r.q.UpsertWorkerState(ctx, params)
 	if err != nil {
@@ -53,3 +70,72 @@ func (r *WorkerRepository) GetRecentTasks(ctx context.Context, params database.G
 func (r *WorkerRepository) GetWorkerTask(ctx context.Context, id uuid.UUID) (database.WorkerTask, error) {
 	return r.q.GetWorkerTask(ctx, id)
 }
+
+func (r *WorkerRepository) UpdateWorkerTaskApiCallsSaved(ctx context.Context, id uuid.UUID, saved int32) error {
+	return r.q.UpdateWorkerTaskApiCallsSaved(ctx, database.UpdateWorkerTaskApiCallsSavedParams{
+		ID:            id,
+		ApiCallsSaved: &saved,
+	})
+}
+
+// UpdateWorkerCycleCursor stores how far the current cycle got, nil marks the cycle as finished
+func (r *WorkerRepository) UpdateWorkerCycleCursor(ctx context.Context, workerID string, cursor *int64) error {
+	return r.q.UpdateWorkerCycleCursor(ctx, database.UpdateWorkerCycleCursorParams{
+		WorkerID:    workerID,
+		CycleCursor: cursor,
+	})
+}
+
+// AbortRunningWorkerTasks closes tasks any worker of a type left open, returning how many were closed
+func (r *WorkerRepository) AbortRunningWorkerTasks(ctx context.Context, workerType, reason string) (int64, error) {
+	return r.q.AbortRunningWorkerTasks(ctx, database.AbortRunningWorkerTasksParams{
+		WorkerType: workerType,
+		Error:      &reason,
+	})
+}
+
+// AcquireWorkerLease takes or renews the lease of a worker type, it reports false while another holder's lease is valid
+func (r *WorkerRepository) AcquireWorkerLease(ctx context.Context, workerType, holderID string, expiresAt time.Time) (bool, error) {
+	rows, err := r.q.AcquireWorkerLease(ctx, database.AcquireWorkerLeaseParams{
+		WorkerType: workerType,
+		HolderID:   holderID,
+		ExpiresAt:  pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *WorkerRepository) ReleaseWorkerLease(ctx context.Context, workerType, holderID string) error {
+	return r.q.ReleaseWorkerLease(ctx, database.ReleaseWorkerLeaseParams{
+		WorkerType: workerType,
+		HolderID:   holderID,
+	})
+}
+
+func (r *WorkerRepository) GetWorkerPerformance(ctx context.Context) ([]database.WorkerPerformance, error) {
+	return r.q.GetWorkerPerformance(ctx)
+}
+
+// SetWorkerPaused pauses or resumes every instance of a worker type, returning how many workers were changed
+func (r *WorkerRepository) SetWorkerPaused(ctx context.Context, workerType string, paused bool) (int64, error) {
+	return r.q.SetWorkerPaused(ctx, database.SetWorkerPausedParams{
+		WorkerType: workerType,
+		Paused:     paused,
+	})
+}
+
+// IsWorkerTypePaused reports whether an admin paused a worker type, whichever instance currently runs it
+func (r *WorkerRepository) IsWorkerTypePaused(ctx context.Context, workerType string) (bool, error) {
+	return r.q.IsWorkerTypePaused(ctx, workerType)
+}
+
+// RollupWorkerTasks moves up to limit finished tasks created before the given time into daily stats
+func (r *WorkerRepository) RollupWorkerTasks(ctx context.Context, before time.Time, limit int32) (database.RollupWorkerTasksRow, error) {
+	return r.q.RollupWorkerTasks(ctx, database.RollupWorkerTasksParams{
+		CreatedAt: pgtype.Timestamptz{Time: before, Valid: true},
+		Limit:     limit,
+	})
+}
@@ -3,7 +3,9 @@ package tmdb
 import (
 	"crypto/tls"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/config"
+	"golang.org/x/time/rate"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -11,6 +13,18 @@ type Client struct {
 	HttpClient *http.Client
 	BaseUrl    string
 	ImageUrl   string
+
+	// limiters holds one rate limiter per API key, every user's key has its own TMDB quota
+	limiters      map[string]*limiterEntry
+	limitersMu    sync.Mutex
+	limitersSwept time.Time
+	keyRateLimit  int
+}
+
+// limiterEntry is the rate limiter of one API key and when it was last handed out
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
 }
 
 func NewClient(config *config.Config) *Client {
@@ -26,13 +40,20 @@ func NewClient(config *config.Config) *Client {
 		DisableCompression: false,
 	}
 
+	keyRateLimit := config.General.TMDBKeyRateLimit
+	if keyRateLimit <= 0 {
+		keyRateLimit = defaultKeyRateLimit
+	}
+
 	return &Client{
 		HttpClient: &http.Client{
 			Transport: transport,
 			Timeout:   10 * time.Second, // Overall request timeout
 		},
-		BaseUrl:  config.Endpoints.BaseUrl,
-		ImageUrl: config.Endpoints.ImageUrl,
+		BaseUrl:      config.Endpoints.BaseUrl,
+		ImageUrl:     config.Endpoints.ImageUrl,
+		limiters:     make(map[string]*limiterEntry),
+		keyRateLimit: keyRateLimit,
 	}
 }
 
@@ -7,24 +7,24 @@ import (
 	"fmt"
 	appCfg "github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/image"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/providers"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
 	"gopkg.in/telebot.v3"
 	"io"
-	"net/http"
 	"time"
 )
 
 // GetMovie fetches movie details by Id from the API.
-func GetMovie(app *appCfg.App, movieId int, userId int64) (*Movie, error) {
+func GetMovie(ctx context.Context, app *appCfg.App, movieId int, userId int64) (*Movie, error) {
 	const op = "movie.GetMovie"
 	app.Logger.Debug(op, nil, "Fetching movie details", "movie_id", movieId, "user_id", userId)
 
 	url := utils.MakeUrl(app, fmt.Sprintf("%s/%v", app.Cfg.Endpoints.Resources.GetMovie, movieId), nil, userId)
 
 	app.Logger.Debug(op, nil, "Making API request", "url", url)
-	resp, err := app.TMDBClient.HttpClient.Get(url)
+	resp, err := app.TMDBClient.Get(ctx, url)
 	if err != nil {
 		app.Logger.Error(op, nil, "Failed to fetch movie data", "movie_id", movieId, "error", err.Error())
 		return nil, fmt.Errorf("error fetching movie data: %w", err)
@@ -33,12 +33,6 @@ func GetMovie(app *appCfg.App, movieId int, userId int64) (*Movie, error) {
 		_ = Body.Close()
 	}(resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
-		app.Logger.Error(op, nil, "Non-200 response from API",
-			"movie_id", movieId, "status_code", resp.StatusCode)
-		return nil, fmt.Errorf("non-200 response: %d", resp.StatusCode)
-	}
-
 	var result Movie
 	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		app.Logger.Error(op, nil, "Failed to parse JSON response", "movie_id", movieId, "error", err.Error())
@@ -50,8 +44,9 @@ func GetMovie(app *appCfg.App, movieId int, userId int64) (*Movie, error) {
 	return &result, nil
 }
 
-// ShowMovie displays movie details along with an image and interactive buttons.
-func ShowMovie(app *appCfg.App, ctx telebot.Context, movieData *Movie, isMovie bool) error {
+// ShowMovie displays movie details along with an image and interactive buttons. backData is the callback
+// of the back button, it leads to wherever the movie was picked from.
+func ShowMovie(app *appCfg.App, ctx telebot.Context, movieData *Movie, backData string) error {
 	const op = "movie.ShowMovie"
 	app.Logger.Info(op, ctx, "Showing movie details to user",
 		"movie_id", movieData.ID, "title", movieData.Title)
@@ -66,24 +61,29 @@ func ShowMovie(app *appCfg.App, ctx telebot.Context, movieData *Movie, isMovie b
 
 	// Prepare movie details caption
 	app.Logger.Debug(op, ctx, "Preparing movie details caption")
-	caption := fmt.Sprintf(
-		"🎬 *Title*: %v\n\n"+
-			"📝 *Overview*: %v\n\n"+
-			"📅 *Release Date*: %s\n\n"+
-			"⏳ *Runtime*: %v minutes\n\n"+
-			"🔞 *Is Adult*: %v\n\n"+
-			"🔥 *Popularity*: %.2f\n\n"+
-			"🌐 *Language*: %v\n\n"+
-			"🎥 *Status*: %v\n",
-		movieData.Title,
-		movieData.Overview,
-		movieData.ReleaseDate,
-		movieData.Runtime,
-		movieData.Adult,
-		movieData.Popularity,
-		movieData.OriginalLanguage,
-		movieData.Status,
-	)
+	extras := providers.CardSection(app, app.Cfg.Endpoints.Resources.GetMovie, int(movieData.ID), ctx.Sender().ID)
+	extras += utils.RatingSection(app, constants.MovieType, movieData.ID, ctx.Sender().ID)
+	// The overview is shortened to leave room for the sections below the details
+	caption := utils.FitCaption(movieData.Overview, func(overview string) string {
+		return fmt.Sprintf(
+			"🎬 *Title*: %v\n\n"+
+				"📝 *Overview*: %v\n\n"+
+				"📅 *Release Date*: %s\n\n"+
+				"⏳ *Runtime*: %v minutes\n\n"+
+				"🔞 *Is Adult*: %v\n\n"+
+				"🔥 *Popularity*: %.2f\n\n"+
+				"🌐 *Language*: %v\n\n"+
+				"🎥 *Status*: %v\n",
+			movieData.Title,
+			overview,
+			movieData.ReleaseDate,
+			movieData.Runtime,
+			movieData.Adult,
+			movieData.Popularity,
+			movieData.OriginalLanguage,
+			movieData.Status,
+		) + extras
+	})
 
 	// Delete the original ctx message
 	if err = ctx.Delete(); err != nil {
@@ -103,7 +103,13 @@ func ShowMovie(app *appCfg.App, ctx telebot.Context, movieData *Movie, isMovie b
 		return err
 	}
 
-	replyMarkup := generateReplyMarkup(movieData.ID, movieExists, isMovie)
+	isWatched, err := app.Repository.Movies.MovieExists(ctxDb, movieData.ID, ctx.Sender().ID)
+	if err != nil {
+		app.Logger.Error(op, ctx, "Failed to check watched status", "error", err.Error())
+		return err
+	}
+
+	replyMarkup := generateReplyMarkup(movieData.ID, movieExists, isWatched, backData)
 
 	// Send the movie details with poster and buttons
 	imageFile := &telebot.Photo{
@@ -123,37 +129,32 @@ func ShowMovie(app *appCfg.App, ctx telebot.Context, movieData *Movie, isMovie b
 }
 
 // generateReplyMarkup generates inline keyboard buttons for the movie.
-func generateReplyMarkup(movieID int64, isWatchlisted bool, isMovie bool) *telebot.ReplyMarkup {
+func generateReplyMarkup(movieID int64, isWatchlisted bool, isWatched bool, backData string) *telebot.ReplyMarkup {
 	btn := &telebot.ReplyMarkup{}
 
-	var backButton telebot.Btn
-	if isMovie {
-		backButton = btn.Data("🔙 Back to list", "movie|back_to_pagination|")
-	} else {
-		backButton = btn.Data("🔙 Back to list", fmt.Sprintf("watchlist|back_to_pagination|%s", constants.MovieType))
-	}
+	backButton := btn.Data("🔙 Back to list", backData)
 
 	watchlistButton := btn.Data(
 		"🌟 Watchlist", fmt.Sprintf("movie|watchlist|%v", movieID),
 	)
 	watchlistedButton := btn.Data(
-		"📌 Watchlisted", fmt.Sprintf("", movieID),
+		"📌 Watchlisted", fmt.Sprintf("movie|watchlisted|%v", movieID),
 	)
 	watchedButton := btn.Data(
 		"👀 Watched", fmt.Sprintf("movie|watched|%v", movieID),
 	)
 
+	rows := []telebot.Row{btn.Row(backButton)}
 	if isWatchlisted {
-		btn.Inline(
-			btn.Row(backButton),
-			btn.Row(watchlistedButton, watchedButton),
-		)
+		rows = append(rows, btn.Row(watchlistedButton, watchedButton))
 	} else {
-		btn.Inline(
-			btn.Row(backButton),
-			btn.Row(watchlistButton, watchedButton),
-		)
+		rows = append(rows, btn.Row(watchlistButton, watchedButton))
+	}
+	rows = append(rows, btn.Row(btn.Data("📂 Add to list…", fmt.Sprintf("list|picker|%s-%v", constants.MovieType, movieID))))
+	if isWatched {
+		rows = append(rows, btn.Row(btn.Data("🗑 Remove from watched", fmt.Sprintf("movie|unwatch|%v", movieID))))
 	}
+	btn.Inline(rows...)
 
 	return btn
 }
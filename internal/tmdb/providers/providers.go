@@ -0,0 +1,222 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	appCfg "github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxListedProviders keeps the where to watch lines of a card short, photo captions are limited in length
+	maxListedProviders = 5
+	// cardCacheTTL is how long the providers of a title are reused for detail cards, offers change over days
+	cardCacheTTL = 6 * time.Hour
+	// cardTimeout bounds the provider lookup of a card, the card is shown without providers when it runs out
+	cardTimeout = 2 * time.Second
+)
+
+// cachedProviders are the providers of one title fetched for a detail card
+type cachedProviders struct {
+	results map[string]RegionProviders
+	expires time.Time
+}
+
+var (
+	cardCacheMu sync.Mutex
+	cardCache   = make(map[string]cachedProviders)
+)
+
+// GetWatchProviders fetches where a title can be watched. The resource is the movie or TV endpoint of the title.
+func GetWatchProviders(ctx context.Context, app *appCfg.App, resource string, titleId int, userId int64) (*WatchProviders, error) {
+	const op = "providers.GetWatchProviders"
+	app.Logger.Debug(op, nil, "Fetching watch providers", "resource", resource, "title_id", titleId, "user_id", userId)
+
+	url := utils.MakeUrl(app, fmt.Sprintf("%s/%v%s", resource, titleId, app.Cfg.Endpoints.Resources.WatchProviders), nil, userId)
+	var result WatchProviders
+	if err := getJSON(ctx, app, url, &result); err != nil {
+		app.Logger.Error(op, nil, "Failed to fetch watch providers",
+			"resource", resource, "title_id", titleId, "error", err.Error())
+		return nil, err
+	}
+
+	app.Logger.Debug(op, nil, "Watch providers fetched successfully",
+		"resource", resource, "title_id", titleId, "regions", len(result.Results))
+	return &result, nil
+}
+
+// GetRegionProviders fetches the providers offering movies or TV shows in a region, ordered by TMDB's display priority
+func GetRegionProviders(ctx context.Context, app *appCfg.App, region string, userId int64) ([]Provider, error) {
+	const op = "providers.GetRegionProviders"
+	app.Logger.Debug(op, nil, "Fetching providers of region", "region", region, "user_id", userId)
+
+	params := map[string]string{"watch_region": region}
+	priorities := make(map[int32]Provider)
+	for _, resource := range []string{app.Cfg.Endpoints.Resources.GetMovie, app.Cfg.Endpoints.Resources.GetTV} {
+		url := utils.MakeUrl(app, app.Cfg.Endpoints.Resources.WatchProviders+resource, params, userId)
+		var result ProviderList
+		if err := getJSON(ctx, app, url, &result); err != nil {
+			app.Logger.Error(op, nil, "Failed to fetch providers of region",
+				"region", region, "resource", resource, "error", err.Error())
+			return nil, err
+		}
+
+		for _, provider := range result.Results {
+			if known, exists := priorities[provider.ID]; !exists || provider.DisplayPriority < known.DisplayPriority {
+				priorities[provider.ID] = provider
+			}
+		}
+	}
+
+	list := make([]Provider, 0, len(priorities))
+	for _, provider := range priorities {
+		list = append(list, provider)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].DisplayPriority != list[j].DisplayPriority {
+			return list[i].DisplayPriority < list[j].DisplayPriority
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	app.Logger.Debug(op, nil, "Providers of region fetched successfully", "region", region, "count", len(list))
+	return list, nil
+}
+
+// CardSection returns the where to watch lines of a detail card in the user's region. Providers are a nice
+// extra, so the card is shown without them when they can't be fetched in time. Fetched providers are reused for
+// cardCacheTTL, opening the same card again costs no TMDB request.
+func CardSection(app *appCfg.App, resource string, titleId int, userId int64) string {
+	const op = "providers.CardSection"
+	region := UserRegion(app, userId)
+	key := fmt.Sprintf("%s/%d", resource, titleId)
+
+	results, ok := cachedCardProviders(key)
+	if !ok {
+		ctx, cancel := context.WithTimeout(app.Ctx, cardTimeout)
+		defer cancel()
+
+		watchProviders, err := GetWatchProviders(ctx, app, resource, titleId, userId)
+		if err != nil {
+			app.Logger.Warning(op, nil, "Showing card without watch providers",
+				"title_id", titleId, "error", err.Error())
+			return ""
+		}
+		results = watchProviders.Results
+		cacheCardProviders(key, results)
+	}
+
+	return "\n" + Describe(results[region], region)
+}
+
+func cachedCardProviders(key string) (map[string]RegionProviders, bool) {
+	cardCacheMu.Lock()
+	defer cardCacheMu.Unlock()
+
+	cached, ok := cardCache[key]
+	if !ok || time.Now().After(cached.expires) {
+		return nil, false
+	}
+	return cached.results, true
+}
+
+// cacheCardProviders stores the providers of a title, expired titles are dropped on the way
+func cacheCardProviders(key string, results map[string]RegionProviders) {
+	cardCacheMu.Lock()
+	defer cardCacheMu.Unlock()
+
+	now := time.Now()
+	for k, cached := range cardCache {
+		if now.After(cached.expires) {
+			delete(cardCache, k)
+		}
+	}
+	cardCache[key] = cachedProviders{results: results, expires: now.Add(cardCacheTTL)}
+}
+
+// UserRegion returns the watch region picked by the user, or the default one
+func UserRegion(app *appCfg.App, userId int64) string {
+	const op = "providers.UserRegion"
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	settings, err := app.Repository.Settings.GetUserSettings(ctxDb, userId)
+	if err != nil || settings.Region == "" {
+		if err != nil {
+			app.Logger.Warning(op, nil, "Failed to get user region, using the default", "error", err.Error())
+		}
+		return constants.DefaultRegion
+	}
+	return settings.Region
+}
+
+// Describe formats the providers of one region for a detail card
+func Describe(providers RegionProviders, region string) string {
+	if len(providers.Flatrate) == 0 && len(providers.Rent) == 0 && len(providers.Buy) == 0 {
+		return fmt.Sprintf("📡 *Where to Watch (%v)*: Not available yet\n", region)
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("📡 *Where to Watch (%v)*:\n", region))
+	for _, line := range []struct {
+		label     string
+		providers []Provider
+	}{
+		{"📺 Stream", providers.Flatrate},
+		{"💵 Rent", providers.Rent},
+		{"🛒 Buy", providers.Buy},
+	} {
+		if len(line.providers) > 0 {
+			text.WriteString(fmt.Sprintf("└ %v: %v\n", line.label, Names(line.providers, maxListedProviders)))
+		}
+	}
+	return text.String()
+}
+
+// Names joins provider names, listing at most limit of them
+func Names(providers []Provider, limit int) string {
+	names := make([]string, 0, min(len(providers), limit))
+	for i, provider := range providers {
+		if i == limit {
+			break
+		}
+		names = append(names, provider.Name)
+	}
+
+	joined := strings.Join(names, ", ")
+	if len(providers) > limit {
+		joined += fmt.Sprintf(" +%d more", len(providers)-limit)
+	}
+	return joined
+}
+
+// FlatrateIDs returns the IDs of the streaming services offering the title in a region
+func (r RegionProviders) FlatrateIDs() []int32 {
+	ids := make([]int32, 0, len(r.Flatrate))
+	for _, provider := range r.Flatrate {
+		ids = append(ids, provider.ID)
+	}
+	return ids
+}
+
+func getJSON(ctx context.Context, app *appCfg.App, url string, target any) error {
+	resp, err := app.TMDBClient.Get(ctx, url)
+	if err != nil {
+		return fmt.Errorf("error fetching watch providers: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if err = json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("error parsing JSON response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package providers
+
+// Provider is a streaming service, rental store or shop listed by TMDB
+type Provider struct {
+	ID              int32  `json:"provider_id"`
+	Name            string `json:"provider_name"`
+	LogoPath        string `json:"logo_path"`
+	DisplayPriority int32  `json:"display_priority"`
+}
+
+// RegionProviders lists where a title can be watched in one region
+type RegionProviders struct {
+	Link     string     `json:"link"`
+	Flatrate []Provider `json:"flatrate"`
+	Rent     []Provider `json:"rent"`
+	Buy      []Provider `json:"buy"`
+}
+
+// WatchProviders holds the providers of a title for every region, keyed by ISO 3166-1 country code
+type WatchProviders struct {
+	ID      int64                      `json:"id"`
+	Results map[string]RegionProviders `json:"results"`
+}
+
+// ProviderList is every provider TMDB knows for a region
+type ProviderList struct {
+	Results []Provider `json:"results"`
+}
@@ -0,0 +1,198 @@
+package tmdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/time/rate"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxAttempts is how often a request is sent before a transient failure is returned
+	maxAttempts = 4
+	// backoffBase and backoffCap bound the jittered wait between two attempts
+	backoffBase = 500 * time.Millisecond
+	backoffCap  = 8 * time.Second
+	// maxRetryAfter is the longest Retry-After we wait for, longer ones are returned as ErrRateLimited right away
+	maxRetryAfter = 30 * time.Second
+	// defaultKeyRateLimit applies when no per-key limit is configured, in requests per second
+	defaultKeyRateLimit = 20
+	// limiterIdleTTL is how long the limiter of an unused key is kept, its bucket is long full again by then so
+	// dropping it loses nothing
+	limiterIdleTTL = 30 * time.Minute
+	// limiterSweepInterval is how often idle limiters are looked for
+	limiterSweepInterval = 5 * time.Minute
+)
+
+// Errors of a failed TMDB request, StatusError and network failures wrap one of them
+var (
+	ErrUnauthorized = errors.New("tmdb rejected the api key")
+	ErrNotFound     = errors.New("tmdb resource not found")
+	ErrRateLimited  = errors.New("tmdb rate limit exceeded")
+	ErrUnavailable  = errors.New("tmdb is unavailable")
+	ErrBadRequest   = errors.New("tmdb rejected the request")
+)
+
+// StatusError is a response of TMDB other than 200
+type StatusError struct {
+	StatusCode int
+	Attempts   int
+	kind       error
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%v: status %d after %d attempts", e.kind, e.StatusCode, e.Attempts)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.kind
+}
+
+// Get sends a GET request to TMDB. Requests of one API key share a rate limiter, 429 responses are retried
+// after their Retry-After and 5xx responses and network errors with a jittered backoff. Any response other
+// than 200 is returned as an error, so on success the caller only has to read and close the body.
+func (c *Client) Get(ctx context.Context, rawUrl string) (*http.Response, error) {
+	limiter := c.keyLimiter(apiKey(rawUrl))
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limiter error: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawUrl, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error building request: %w", err)
+		}
+
+		resp, err := c.HttpClient.Do(req)
+		var wait time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = fmt.Errorf("%w: %w", ErrUnavailable, err)
+			wait = backoff(attempt)
+
+		case resp.StatusCode == http.StatusOK:
+			return resp, nil
+
+		default:
+			_ = resp.Body.Close()
+			statusErr := &StatusError{StatusCode: resp.StatusCode, Attempts: attempt, kind: classify(resp.StatusCode)}
+			lastErr = statusErr
+			if !retryable(resp.StatusCode) {
+				return nil, statusErr
+			}
+
+			wait = backoff(attempt)
+			if resp.StatusCode == http.StatusTooManyRequests {
+				if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+					if retryAfter > maxRetryAfter {
+						return nil, statusErr
+					}
+					wait = retryAfter
+				}
+			}
+		}
+
+		if attempt == maxAttempts {
+			break
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return nil, lastErr
+}
+
+// keyLimiter returns the rate limiter of an API key, creating it on first use. Limiters of keys that were not
+// used for limiterIdleTTL are dropped on the way, so keys of users who left don't pile up.
+func (c *Client) keyLimiter(key string) *rate.Limiter {
+	c.limitersMu.Lock()
+	defer c.limitersMu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.limitersSwept) >= limiterSweepInterval {
+		for k, entry := range c.limiters {
+			if now.Sub(entry.lastUsed) >= limiterIdleTTL {
+				delete(c.limiters, k)
+			}
+		}
+		c.limitersSwept = now
+	}
+
+	entry, exists := c.limiters[key]
+	if !exists {
+		entry = &limiterEntry{limiter: rate.NewLimiter(rate.Limit(c.keyRateLimit), c.keyRateLimit)}
+		c.limiters[key] = entry
+	}
+	entry.lastUsed = now
+	return entry.limiter
+}
+
+func classify(statusCode int) error {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode >= http.StatusInternalServerError:
+		return ErrUnavailable
+	default:
+		return ErrBadRequest
+	}
+}
+
+func retryable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns a random wait below an exponentially growing bound, so retrying clients spread out
+func backoff(attempt int) time.Duration {
+	bound := min(backoffBase<<(attempt-1), backoffCap)
+	return time.Duration(rand.Int63n(int64(bound)))
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// apiKey extracts the api_key query parameter that utils.MakeUrl puts on every request
+func apiKey(rawUrl string) string {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+	return parsed.Query().Get("api_key")
+}
@@ -1,15 +1,15 @@
 package search
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	appCfg "github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
 	"io"
-	"net/http"
 )
 
-func SearchMovie(app *appCfg.App, movieTitle string, userId int64) (*MovieSearch, error) {
+func SearchMovie(ctx context.Context, app *appCfg.App, movieTitle string, userId int64) (*MovieSearch, error) {
 	const op = "search.SearchMovie"
 	app.Logger.Info(op, nil, "Searching for movie", "query", movieTitle, "user_id", userId)
 
@@ -21,7 +21,7 @@ func SearchMovie(app *appCfg.App, movieTitle string, userId int64) (*MovieSearch
 	url := utils.MakeUrl(app, fmt.Sprintf("%v%v", app.Cfg.Endpoints.Resources.Search.Prefix, app.Cfg.Endpoints.Resources.Search.Movie), params, userId)
 	app.Logger.Debug(op, nil, "Making API request", "url", url)
 
-	resp, err := app.TMDBClient.HttpClient.Get(url)
+	resp, err := app.TMDBClient.Get(ctx, url)
 	if err != nil {
 		app.Logger.Error(op, nil, "Failed to fetch movie search results",
 			"query", movieTitle, "error", err.Error())
@@ -31,12 +31,6 @@ func SearchMovie(app *appCfg.App, movieTitle string, userId int64) (*MovieSearch
 		_ = Body.Close()
 	}(resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
-		app.Logger.Error(op, nil, "Received non-200 response from API",
-			"query", movieTitle, "status_code", resp.StatusCode)
-		return nil, fmt.Errorf("received non-200 response: %d", resp.StatusCode)
-	}
-
 	app.Logger.Debug(op, nil, "Parsing search results JSON")
 	var result MovieSearch
 	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
@@ -50,7 +44,7 @@ func SearchMovie(app *appCfg.App, movieTitle string, userId int64) (*MovieSearch
 	return &result, nil
 }
 
-func SearchTV(app *appCfg.App, tvTitle string, userId int64) (*TVSearch, error) {
+func SearchTV(ctx context.Context, app *appCfg.App, tvTitle string, userId int64) (*TVSearch, error) {
 	const op = "search.SearchTV"
 	app.Logger.Info(op, nil, "Searching for TV show", "query", tvTitle, "user_id", userId)
 
@@ -61,7 +55,7 @@ func SearchTV(app *appCfg.App, tvTitle string, userId int64) (*TVSearch, error)
 	url := utils.MakeUrl(app, fmt.Sprintf("%v%v", app.Cfg.Endpoints.Resources.Search.Prefix, app.Cfg.Endpoints.Resources.Search.TV), params, userId)
 	app.Logger.Debug(op, nil, "Making API request", "url", url)
 
-	resp, err := app.TMDBClient.HttpClient.Get(url)
+	resp, err := app.TMDBClient.Get(ctx, url)
 	if err != nil {
 		app.Logger.Error(op, nil, "Failed to fetch TV search results",
 			"query", tvTitle, "error", err.Error())
@@ -71,12 +65,6 @@ func SearchTV(app *appCfg.App, tvTitle string, userId int64) (*TVSearch, error)
 		_ = Body.Close()
 	}(resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
-		app.Logger.Error(op, nil, "Received non-200 response from API",
-			"query", tvTitle, "status_code", resp.StatusCode)
-		return nil, fmt.Errorf("received non-200 response: %d", resp.StatusCode)
-	}
-
 	app.Logger.Debug(op, nil, "Parsing search results JSON")
 	var result TVSearch
 	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
@@ -4,26 +4,28 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	appCfg "github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/image"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/providers"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/messages"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"github.com/jackc/pgx/v5"
 	"gopkg.in/telebot.v3"
 	"io"
-	"net/http"
 	"time"
 )
 
-func GetTV(app *appCfg.App, tvId int, userId int64) (*TV, error) {
+func GetTV(ctx context.Context, app *appCfg.App, tvId int, userId int64) (*TV, error) {
 	const op = "tv.GetTV"
 	app.Logger.Debug(op, nil, "Fetching TV show details", "tv_id", tvId, "user_id", userId)
 
 	url := utils.MakeUrl(app, fmt.Sprintf("%s/%v", app.Cfg.Endpoints.Resources.GetTV, tvId), nil, userId)
 	app.Logger.Debug(op, nil, "Making API request", "url", url)
 
-	resp, err := app.TMDBClient.HttpClient.Get(url)
+	resp, err := app.TMDBClient.Get(ctx, url)
 	if err != nil {
 		app.Logger.Error(op, nil, "Failed to fetch TV show data", "tv_id", tvId, "error", err.Error())
 		return nil, fmt.Errorf("error fetching tv data: %w", err)
@@ -32,12 +34,6 @@ func GetTV(app *appCfg.App, tvId int, userId int64) (*TV, error) {
 		_ = Body.Close()
 	}(resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
-		app.Logger.Error(op, nil, "Received non-200 response from API",
-			"tv_id", tvId, "status_code", resp.StatusCode)
-		return nil, fmt.Errorf("received non-200 response: %d", resp.StatusCode)
-	}
-
 	var result TV
 	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		app.Logger.Error(op, nil, "Failed to parse JSON response", "tv_id", tvId, "error", err.Error())
@@ -49,7 +45,7 @@ func GetTV(app *appCfg.App, tvId int, userId int64) (*TV, error) {
 	return &result, nil
 }
 
-func GetSeason(app *appCfg.App, tvId, seasonNumber int, userId int64) (*Season, error) {
+func GetSeason(ctx context.Context, app *appCfg.App, tvId, seasonNumber int, userId int64) (*Season, error) {
 	const op = "tv.GetSeason"
 	app.Logger.Debug(op, nil, "Fetching TV season details",
 		"tv_id", tvId, "season", seasonNumber, "user_id", userId)
@@ -57,7 +53,7 @@ func GetSeason(app *appCfg.App, tvId, seasonNumber int, userId int64) (*Season,
 	url := utils.MakeUrl(app, fmt.Sprintf("%s/%v/season/%v", app.Cfg.Endpoints.Resources.GetTV, tvId, seasonNumber), nil, userId)
 	app.Logger.Debug(op, nil, "Making API request", "url", url)
 
-	resp, err := app.TMDBClient.HttpClient.Get(url)
+	resp, err := app.TMDBClient.Get(ctx, url)
 	if err != nil {
 		app.Logger.Error(op, nil, "Failed to fetch season data",
 			"tv_id", tvId, "season", seasonNumber, "error", err.Error())
@@ -67,12 +63,6 @@ func GetSeason(app *appCfg.App, tvId, seasonNumber int, userId int64) (*Season,
 		_ = Body.Close()
 	}(resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
-		app.Logger.Error(op, nil, "Received non-200 response from API",
-			"tv_id", tvId, "season", seasonNumber, "status_code", resp.StatusCode)
-		return nil, fmt.Errorf("received non-200 response: %d", resp.StatusCode)
-	}
-
 	var result Season
 	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		app.Logger.Error(op, nil, "Failed to parse JSON response",
@@ -85,7 +75,17 @@ func GetSeason(app *appCfg.App, tvId, seasonNumber int, userId int64) (*Season,
 	return &result, nil
 }
 
-func ShowTV(app *appCfg.App, ctx telebot.Context, tvData *TV, isTVShow bool) error {
+// EpisodeRuntime returns the length of the latest aired episode, the closest TMDB gets to the runtime of a show
+func EpisodeRuntime(tvData *TV) *int32 {
+	if tvData.LastEpisodeToAir == nil {
+		return nil
+	}
+	return utils.WatchlistRuntime(tvData.LastEpisodeToAir.Runtime)
+}
+
+// ShowTV displays TV show details along with an image and interactive buttons. backData is the callback
+// of the back button, it leads to wherever the show was picked from.
+func ShowTV(app *appCfg.App, ctx telebot.Context, tvData *TV, backData string) error {
 	const op = "tv.ShowTV"
 	app.Logger.Info(op, ctx, "Showing TV show details to user",
 		"tv_id", tvData.Id, "name", tvData.Name)
@@ -98,24 +98,9 @@ func ShowTV(app *appCfg.App, ctx telebot.Context, tvData *TV, isTVShow bool) err
 		return ctx.Send(messages.InternalError)
 	}
 
-	// Prepare TV details caption
-	app.Logger.Debug(op, ctx, "Preparing TV details caption")
-	caption := fmt.Sprintf(
-		"📺 *Name*: %v\n\n"+
-			"📝 *Overview*: %v\n\n"+
-			"📜 *Status*: %v\n\n"+
-			"🔞 *Is Adult*: %v\n\n"+
-			"🔥 *Popularity*: %.2f\n\n"+
-			"🎥 *Seasons*: %v\n\n"+
-			"#️⃣ *Episodes*: %v\n",
-		tvData.Name,
-		tvData.Overview,
-		tvData.Status,
-		tvData.Adult,
-		tvData.Popularity,
-		tvData.Seasons,
-		tvData.Episodes,
-	)
+	// Sections below the details, the overview is shortened to leave room for them
+	extras := providers.CardSection(app, app.Cfg.Endpoints.Resources.GetTV, int(tvData.Id), ctx.Sender().ID)
+	extras += utils.RatingSection(app, constants.TVShowType, tvData.Id, ctx.Sender().ID)
 
 	// Delete the original ctx message
 	if err = ctx.Delete(); err != nil {
@@ -135,7 +120,42 @@ func ShowTV(app *appCfg.App, ctx telebot.Context, tvData *TV, isTVShow bool) err
 		return err
 	}
 
-	replyMarkup := generateReplyMarkup(tvData.Id, tvShowExists, isTVShow)
+	isTracked := true
+	trackedShow, err := app.Repository.TVShows.GetUserTVShow(ctxDb, tvData.Id, ctx.Sender().ID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			app.Logger.Error(op, ctx, "Failed to check watched status", "error", err.Error())
+			return err
+		}
+		isTracked = false
+	}
+
+	if isTracked {
+		extras += fmt.Sprintf("\n📌 *Your Status*: %v\n", utils.WatchStatusLabel(trackedShow.WatchStatus))
+	}
+
+	// Prepare TV details caption
+	app.Logger.Debug(op, ctx, "Preparing TV details caption")
+	caption := utils.FitCaption(tvData.Overview, func(overview string) string {
+		return fmt.Sprintf(
+			"📺 *Name*: %v\n\n"+
+				"📝 *Overview*: %v\n\n"+
+				"📜 *Status*: %v\n\n"+
+				"🔞 *Is Adult*: %v\n\n"+
+				"🔥 *Popularity*: %.2f\n\n"+
+				"🎥 *Seasons*: %v\n\n"+
+				"#️⃣ *Episodes*: %v\n",
+			tvData.Name,
+			overview,
+			tvData.Status,
+			tvData.Adult,
+			tvData.Popularity,
+			tvData.Seasons,
+			tvData.Episodes,
+		) + extras
+	})
+
+	replyMarkup := generateReplyMarkup(tvData.Id, tvShowExists, isTracked, backData)
 
 	// Send the TV details with poster and buttons
 	imageFile := &telebot.Photo{
@@ -155,37 +175,34 @@ func ShowTV(app *appCfg.App, ctx telebot.Context, tvData *TV, isTVShow bool) err
 }
 
 // generateReplyMarkup generates inline keyboard buttons for the TV show.
-func generateReplyMarkup(TvId int64, isWatchlisted bool, isTVShow bool) *telebot.ReplyMarkup {
+func generateReplyMarkup(TvId int64, isWatchlisted bool, isTracked bool, backData string) *telebot.ReplyMarkup {
 	btn := &telebot.ReplyMarkup{}
 
-	var backButton telebot.Btn
-
-	if isTVShow {
-		backButton = btn.Data("🔙 Back to list", "tv|back_to_pagination|")
-	} else {
-		backButton = btn.Data("🔙 Back to list", fmt.Sprintf("watchlist|back_to_pagination|%s", constants.TVShowType))
-	}
+	backButton := btn.Data("🔙 Back to list", backData)
 	watchlistButton := btn.Data(
 		"🌟 Watchlist", fmt.Sprintf("tv|watchlist|%v", TvId),
 	)
 	watchlistedButton := btn.Data(
-		"📌 Watchlisted", fmt.Sprintf("", TvId),
+		"📌 Watchlisted", fmt.Sprintf("tv|watchlisted|%v", TvId),
 	)
 	watchedButton := btn.Data(
 		"👀 Watched", fmt.Sprintf("tv|select_seasons|%v", TvId),
 	)
 
+	rows := []telebot.Row{btn.Row(backButton)}
 	if isWatchlisted {
-		btn.Inline(
-			btn.Row(backButton),
-			btn.Row(watchlistedButton, watchedButton),
-		)
+		rows = append(rows, btn.Row(watchlistedButton, watchedButton))
 	} else {
-		btn.Inline(
-			btn.Row(backButton),
-			btn.Row(watchlistButton, watchedButton),
-		)
+		rows = append(rows, btn.Row(watchlistButton, watchedButton))
+	}
+	rows = append(rows, btn.Row(btn.Data("📂 Add to list…", fmt.Sprintf("list|picker|%s-%v", constants.TVShowType, TvId))))
+	if isTracked {
+		rows = append(rows, btn.Row(
+			btn.Data("🏷 Change status", fmt.Sprintf("tv|status_menu|%v", TvId)),
+			btn.Data("🗑 Remove from watched", fmt.Sprintf("tv|unwatch|%v", TvId)),
+		))
 	}
+	btn.Inline(rows...)
 
 	return btn
 }
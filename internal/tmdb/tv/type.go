@@ -1,16 +1,27 @@
 package tv
 
 type TV struct {
-	Id           int64   `json:"id"`
-	Name         string  `json:"name"`
-	Overview     string  `json:"overview"`
-	Status       string  `json:"status"`
-	Adult        bool    `json:"adult"`
-	Popularity   float32 `json:"popularity"`
-	Seasons      int32   `json:"number_of_seasons"`
-	Episodes     int32   `json:"number_of_episodes"`
-	BackdropPath string  `json:"backdrop_path"`
-	PosterPath   string  `json:"poster_path"`
+	Id               int64           `json:"id"`
+	Name             string          `json:"name"`
+	Overview         string          `json:"overview"`
+	Status           string          `json:"status"`
+	Adult            bool            `json:"adult"`
+	Popularity       float32         `json:"popularity"`
+	Seasons          int32           `json:"number_of_seasons"`
+	Episodes         int32           `json:"number_of_episodes"`
+	BackdropPath     string          `json:"backdrop_path"`
+	PosterPath       string          `json:"poster_path"`
+	NextEpisodeToAir *Episode        `json:"next_episode_to_air"`
+	LastEpisodeToAir *Episode        `json:"last_episode_to_air"`
+	SeasonList       []SeasonSummary `json:"seasons"`
+}
+
+// SeasonSummary is a season as listed in the show details, season 0 holds the specials
+type SeasonSummary struct {
+	SeasonNumber int32  `json:"season_number"`
+	Name         string `json:"name"`
+	EpisodeCount int32  `json:"episode_count"`
+	AirDate      string `json:"air_date"`
 }
 
 type Season struct {
@@ -23,6 +34,7 @@ type Season struct {
 
 type Episode struct {
 	Id            int64  `json:"id"`
+	Name          string `json:"name"`
 	AirDate       string `json:"air_date"`
 	SeasonNumber  int32  `json:"season_number"`
 	EpisodeNumber int32  `json:"episode_number"`
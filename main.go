@@ -15,13 +15,22 @@ import (
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/workers"
 	"gopkg.in/telebot.v3"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
+	// The runtime image ships without zoneinfo, user timezones are resolved from the embedded copy
+	_ "time/tzdata"
 )
 
+// defaultShutdownTimeout applies when shutdown_timeout is not configured
+const defaultShutdownTimeout = 30 * time.Second
+
 func main() {
-	// Create a cancellable context
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// The context is cancelled on SIGINT or SIGTERM, which starts the shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	log.Print("starting bot...")
 	cfg := config.MustLoad()
@@ -32,9 +41,8 @@ func main() {
 	encryptor := encryption.NewKeyEncryptor(cfg.General.SecretKey)
 	cacheManager := cache.NewCacheManager(repoManager, encryptor)
 	lgr := logger.NewLogger(cfg.Env, cfg.Betterstack.Host, cfg.Betterstack.Token)
-	defer lgr.Stop()
 
-	appCfg := app.NewApp(cfg, repoManager, tmdbClient, cacheManager, encryptor, lgr)
+	appCfg := app.NewApp(ctx, cfg, repoManager, tmdbClient, cacheManager, encryptor, lgr)
 
 	settings := telebot.Settings{
 		Token:  cfg.General.BotToken,
@@ -47,7 +55,15 @@ func main() {
 		return
 	}
 
-	resolver := api.NewResolver(appCfg)
+	// Periodic workers run as jobs of the scheduler, admins control them through the bot
+	apiClient := workers.NewWorkerApiClient(appCfg, cfg.General.WorkerRateLimit)
+	scheduler := workers.NewScheduler(appCfg)
+	if err = workers.RegisterJobs(scheduler, appCfg, apiClient); err != nil {
+		log.Fatal(fmt.Sprintf("cant register worker jobs: %v", err.Error()))
+		return
+	}
+
+	resolver := api.NewResolver(appCfg, scheduler)
 	resolver.KeyboardFactory.LoadAllKeyboards(bot, resolver.DefaultHandler)
 
 	routes.SetupDefaultRoutes(bot, resolver, appCfg)
@@ -55,12 +71,57 @@ func main() {
 	routes.SetupTVRoutes(bot, resolver, appCfg)
 	routes.SetupInfoRoutes(bot, resolver, appCfg)
 	routes.SetupWatchlistRoutes(bot, resolver, appCfg)
+	routes.SetupListRoutes(bot, resolver, appCfg)
+	routes.SetupSettingsRoutes(bot, resolver, appCfg)
+	routes.SetupAdminRoutes(bot, resolver, appCfg)
 
-	// Start the checker in a separate goroutine
-	apiClient := workers.NewWorkerApiClient(appCfg, cfg.General.WorkerRateLimit)
-	checker := workers.NewTVShowChecker(appCfg, bot, apiClient)
-	go checker.StartChecking(ctx, cfg.General.WorkerPeriod)
+	var running sync.WaitGroup
+	running.Add(2)
+	go func() {
+		defer running.Done()
+		scheduler.Start(ctx)
+	}()
+
+	// Workers only queue their messages, the dispatcher is the one delivering them
+	dispatcher := workers.NewNotificationDispatcher(appCfg, bot, cfg.General.NotificationAttempts)
+	go func() {
+		defer running.Done()
+		dispatcher.StartDispatching(ctx, cfg.General.NotificationPoll)
+	}()
+
+	go bot.Start()
+	lgr.WorkerInfo("MAIN", "Bot and Workers started")
 
-	lgr.WorkerInfo("MAIN", "Bot and Worker started")
-	bot.Start()
+	<-ctx.Done()
+	stop()
+	lgr.WorkerInfo("MAIN", "Shutdown signal received, stopping bot and workers")
+
+	// No new updates come in while the workers finish what they are doing
+	bot.Stop()
+	shutdownTimeout := time.Duration(cfg.General.ShutdownTimeout) * time.Second
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+	drainWorkers(&running, shutdownTimeout, lgr)
+
+	lgr.WorkerInfo("MAIN", "Shutdown complete")
+	lgr.Stop()
+	repoManager.Close()
+}
+
+// drainWorkers waits for the scheduler and the dispatcher to stop, but no longer than the timeout
+func drainWorkers(running *sync.WaitGroup, timeout time.Duration, lgr *logger.Logger) {
+	done := make(chan struct{})
+	go func() {
+		running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		lgr.WorkerInfo("MAIN", "Workers stopped")
+	case <-time.After(timeout):
+		lgr.WorkerWarning("MAIN", "Workers did not stop in time, exiting anyway",
+			"timeout_seconds", timeout.Seconds())
+	}
 }
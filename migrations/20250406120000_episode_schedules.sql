@@ -0,0 +1,36 @@
+-- Create "episode_schedules" table
+CREATE TABLE "episode_schedules" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "show_api_id" bigint NOT NULL,
+  "show_name" text NOT NULL,
+  "season_number" integer NOT NULL,
+  "episode_number" integer NOT NULL,
+  "episode_name" text NULL,
+  "air_date" date NOT NULL,
+  "runtime" integer NULL,
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  "updated_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("id"),
+  CONSTRAINT "episode_schedules_show_episode_unique" UNIQUE ("show_api_id", "season_number", "episode_number")
+);
+-- Create index "idx_episode_schedules_air_date" to table: "episode_schedules"
+CREATE INDEX "idx_episode_schedules_air_date" ON "episode_schedules" ("air_date");
+-- Set comment to table: "episode_schedules"
+COMMENT ON TABLE "episode_schedules" IS 'Stores air dates of episodes for shows tracked by users';
+-- Create trigger "update_episode_schedules_timestamp"
+CREATE TRIGGER "update_episode_schedules_timestamp" BEFORE UPDATE ON "episode_schedules" FOR EACH ROW EXECUTE FUNCTION "update_modified_column"();
+-- Create "episode_reminders" table
+CREATE TABLE "episode_reminders" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" bigint NOT NULL,
+  "show_api_id" bigint NOT NULL,
+  "season_number" integer NOT NULL,
+  "episode_number" integer NOT NULL,
+  "sent_at" timestamptz NOT NULL DEFAULT now(),
+  "watched_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "episode_reminders_user_episode_unique" UNIQUE ("user_id", "show_api_id", "season_number", "episode_number"),
+  CONSTRAINT "fk_episode_reminders_user" FOREIGN KEY ("user_id") REFERENCES "users" ("tg_id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Set comment to table: "episode_reminders"
+COMMENT ON TABLE "episode_reminders" IS 'Stores air day reminders sent to users and whether they watched the episode';
@@ -0,0 +1,2 @@
+-- Modify "watchlists" table
+ALTER TABLE "watchlists" ADD COLUMN "release_date" date NULL, ADD COLUMN "release_status" text NULL;
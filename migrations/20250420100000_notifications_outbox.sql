@@ -0,0 +1,27 @@
+-- Create "notifications" table
+CREATE TABLE "notifications" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" bigint NOT NULL,
+  "kind" text NOT NULL,
+  "show_api_id" bigint NULL,
+  "text" text NOT NULL,
+  "image_path" text NULL,
+  "reply_markup" jsonb NULL,
+  "status" text NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT now(),
+  "last_error" text NULL,
+  "sent_at" timestamptz NULL,
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  "updated_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_notifications_user" FOREIGN KEY ("user_id") REFERENCES "users" ("tg_id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_notifications_kind_show" to table: "notifications"
+CREATE INDEX "idx_notifications_kind_show" ON "notifications" ("kind", "show_api_id");
+-- Create index "idx_notifications_status_next_attempt" to table: "notifications"
+CREATE INDEX "idx_notifications_status_next_attempt" ON "notifications" ("status", "next_attempt_at");
+-- Set comment to table: "notifications"
+COMMENT ON TABLE "notifications" IS 'Outbox of worker messages with their delivery status';
+-- Create trigger "update_notifications_timestamp"
+CREATE TRIGGER "update_notifications_timestamp" BEFORE UPDATE ON "notifications" FOR EACH ROW EXECUTE FUNCTION "update_modified_column"();
@@ -0,0 +1,17 @@
+-- Create "show_notification_states" table
+CREATE TABLE "show_notification_states" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" bigint NOT NULL,
+  "show_api_id" bigint NOT NULL,
+  "last_notified_seasons" integer NOT NULL DEFAULT 0,
+  "snoozed_until" timestamptz NULL,
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  "updated_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("id"),
+  CONSTRAINT "show_notification_states_user_show_unique" UNIQUE ("user_id", "show_api_id"),
+  CONSTRAINT "fk_show_notification_states_user" FOREIGN KEY ("user_id") REFERENCES "users" ("tg_id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Set comment to table: "show_notification_states"
+COMMENT ON TABLE "show_notification_states" IS 'Stores the last season count each user was notified about and snoozed alerts';
+-- Create trigger "update_show_notification_states_timestamp"
+CREATE TRIGGER "update_show_notification_states_timestamp" BEFORE UPDATE ON "show_notification_states" FOR EACH ROW EXECUTE FUNCTION "update_modified_column"();
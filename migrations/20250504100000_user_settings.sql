@@ -0,0 +1,23 @@
+-- Create "user_settings" table
+CREATE TABLE "user_settings" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" bigint NOT NULL,
+  "timezone" text NOT NULL DEFAULT 'UTC',
+  "quiet_hours_start" integer NULL,
+  "quiet_hours_end" integer NULL,
+  "notify_new_seasons" boolean NOT NULL DEFAULT true,
+  "notify_status_changes" boolean NOT NULL DEFAULT true,
+  "notify_episode_reminders" boolean NOT NULL DEFAULT true,
+  "notify_releases" boolean NOT NULL DEFAULT true,
+  "notify_digest" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  "updated_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("id"),
+  CONSTRAINT "user_settings_user_unique" UNIQUE ("user_id"),
+  CONSTRAINT "fk_user_settings_user" FOREIGN KEY ("user_id") REFERENCES "users" ("tg_id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "user_settings_quiet_hours_check" CHECK (((quiet_hours_start IS NULL) AND (quiet_hours_end IS NULL)) OR (((quiet_hours_start >= 0) AND (quiet_hours_start <= 23)) AND ((quiet_hours_end >= 0) AND (quiet_hours_end <= 23))))
+);
+-- Set comment to table: "user_settings"
+COMMENT ON TABLE "user_settings" IS 'Stores timezone, quiet hours and notification toggles of users';
+-- Create trigger "update_user_settings_timestamp"
+CREATE TRIGGER "update_user_settings_timestamp" BEFORE UPDATE ON "user_settings" FOR EACH ROW EXECUTE FUNCTION "update_modified_column"();
@@ -0,0 +1,4 @@
+-- Modify "user_settings" table
+ALTER TABLE "user_settings" ADD COLUMN "digest_mode" text NOT NULL DEFAULT 'off', ADD CONSTRAINT "user_settings_digest_mode_check" CHECK (digest_mode = ANY (ARRAY['off'::text, 'cycle'::text, 'weekly'::text]));
+-- Set comment to table: "user_settings"
+COMMENT ON TABLE "user_settings" IS 'Stores timezone, quiet hours, digest mode and notification toggles of users';
@@ -0,0 +1,2 @@
+-- Modify "worker_tasks" table
+ALTER TABLE "worker_tasks" ADD COLUMN "api_calls_saved" integer NULL;
@@ -0,0 +1,13 @@
+-- Create "worker_leases" table
+CREATE TABLE "worker_leases" (
+  "worker_type" character varying(50) NOT NULL,
+  "holder_id" character varying(255) NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  "updated_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("worker_type")
+);
+-- Set comment to table: "worker_leases"
+COMMENT ON TABLE "worker_leases" IS 'Leases electing the single bot instance that runs each worker type';
+-- Create trigger "update_worker_leases_timestamp"
+CREATE TRIGGER "update_worker_leases_timestamp" BEFORE UPDATE ON "worker_leases" FOR EACH ROW EXECUTE FUNCTION "update_modified_column"();
@@ -0,0 +1,2 @@
+-- Modify "worker_states" table
+ALTER TABLE "worker_states" ADD COLUMN "cycle_cursor" bigint NULL;
@@ -0,0 +1,39 @@
+-- Modify "worker_states" table
+ALTER TABLE "worker_states" ADD COLUMN "paused" boolean NOT NULL DEFAULT false;
+-- Modify "worker_performance" view
+CREATE OR REPLACE VIEW "worker_performance" (
+  "worker_id",
+  "worker_type",
+  "status",
+  "last_check_time",
+  "next_check_time",
+  "shows_checked",
+  "updates_found",
+  "error",
+  "total_tasks",
+  "avg_task_duration_ms",
+  "max_task_duration_ms",
+  "error_tasks",
+  "last_task_time",
+  "paused"
+) AS SELECT w.worker_id,
+    w.worker_type,
+    w.status,
+    w.last_check_time,
+    w.next_check_time,
+    w.shows_checked,
+    w.updates_found,
+    w.error,
+    count(t.id) AS total_tasks,
+    avg(t.duration_ms) AS avg_task_duration_ms,
+    max(t.duration_ms) AS max_task_duration_ms,
+    sum(
+        CASE
+            WHEN ((t.status)::text = 'error'::text) THEN 1
+            ELSE 0
+        END) AS error_tasks,
+    max(t.created_at) AS last_task_time,
+    w.paused
+   FROM (worker_states w
+     LEFT JOIN worker_tasks t ON (((w.worker_id)::text = (t.worker_id)::text)))
+  GROUP BY w.id, w.worker_id, w.worker_type, w.status, w.last_check_time, w.next_check_time, w.shows_checked, w.updates_found, w.error, w.paused;
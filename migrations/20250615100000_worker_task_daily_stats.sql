@@ -0,0 +1,81 @@
+-- Create "worker_task_daily_stats" table
+CREATE TABLE "worker_task_daily_stats" (
+  "worker_id" character varying(255) NOT NULL,
+  "task_type" character varying(50) NOT NULL,
+  "day" date NOT NULL,
+  "task_count" integer NOT NULL DEFAULT 0,
+  "error_count" integer NOT NULL DEFAULT 0,
+  "timed_count" integer NOT NULL DEFAULT 0,
+  "total_duration_ms" bigint NOT NULL DEFAULT 0,
+  "max_duration_ms" bigint NULL,
+  "shows_checked" integer NOT NULL DEFAULT 0,
+  "updates_found" integer NOT NULL DEFAULT 0,
+  "api_calls_saved" integer NOT NULL DEFAULT 0,
+  "last_task_time" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  "updated_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("worker_id", "task_type", "day"),
+  CONSTRAINT "fk_worker_task_daily_stats_worker_id" FOREIGN KEY ("worker_id") REFERENCES "worker_states" ("worker_id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Set comment to table: "worker_task_daily_stats"
+COMMENT ON TABLE "worker_task_daily_stats" IS 'Daily aggregates of per-show worker tasks removed by the retention job';
+-- Create trigger "update_worker_task_daily_stats_timestamp"
+CREATE TRIGGER "update_worker_task_daily_stats_timestamp" BEFORE UPDATE ON "worker_task_daily_stats" FOR EACH ROW EXECUTE FUNCTION "update_modified_column"();
+-- Modify "worker_performance" view
+CREATE OR REPLACE VIEW "worker_performance" (
+  "worker_id",
+  "worker_type",
+  "status",
+  "last_check_time",
+  "next_check_time",
+  "shows_checked",
+  "updates_found",
+  "error",
+  "total_tasks",
+  "avg_task_duration_ms",
+  "max_task_duration_ms",
+  "error_tasks",
+  "last_task_time",
+  "paused"
+) AS WITH task_totals AS (
+         SELECT worker_tasks.worker_id,
+            count(worker_tasks.id) AS task_count,
+            count(worker_tasks.duration_ms) AS timed_count,
+            sum(worker_tasks.duration_ms) AS total_duration_ms,
+            max(worker_tasks.duration_ms) AS max_duration_ms,
+            sum(
+                CASE
+                    WHEN ((worker_tasks.status)::text = 'error'::text) THEN 1
+                    ELSE 0
+                END) AS error_count,
+            max(worker_tasks.created_at) AS last_task_time
+           FROM worker_tasks
+          GROUP BY worker_tasks.worker_id
+        UNION ALL
+         SELECT worker_task_daily_stats.worker_id,
+            sum(worker_task_daily_stats.task_count) AS sum,
+            sum(worker_task_daily_stats.timed_count) AS sum,
+            sum(worker_task_daily_stats.total_duration_ms) AS sum,
+            max(worker_task_daily_stats.max_duration_ms) AS max,
+            sum(worker_task_daily_stats.error_count) AS sum,
+            max(worker_task_daily_stats.last_task_time) AS max
+           FROM worker_task_daily_stats
+          GROUP BY worker_task_daily_stats.worker_id
+        )
+ SELECT w.worker_id,
+    w.worker_type,
+    w.status,
+    w.last_check_time,
+    w.next_check_time,
+    w.shows_checked,
+    w.updates_found,
+    w.error,
+    (COALESCE(sum(t.task_count), (0)::numeric))::bigint AS total_tasks,
+    COALESCE((sum(t.total_duration_ms) / NULLIF(sum(t.timed_count), (0)::numeric)), (0)::numeric) AS avg_task_duration_ms,
+    COALESCE(max(t.max_duration_ms), (0)::bigint) AS max_task_duration_ms,
+    (COALESCE(sum(t.error_count), (0)::numeric))::bigint AS error_tasks,
+    max(t.last_task_time) AS last_task_time,
+    w.paused
+   FROM (worker_states w
+     LEFT JOIN task_totals t ON (((w.worker_id)::text = (t.worker_id)::text)))
+  GROUP BY w.id, w.worker_id, w.worker_type, w.status, w.last_check_time, w.next_check_time, w.shows_checked, w.updates_found, w.error, w.paused;
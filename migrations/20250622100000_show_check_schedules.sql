@@ -0,0 +1,16 @@
+-- Create "show_check_schedules" table
+CREATE TABLE "show_check_schedules" (
+  "show_api_id" bigint NOT NULL,
+  "show_status" text NOT NULL,
+  "next_check_at" timestamptz NOT NULL,
+  "last_checked_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  "updated_at" timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT "show_check_schedules_pkey" PRIMARY KEY ("show_api_id")
+);
+-- Create index "idx_show_check_schedules_next_check_at" to table: "show_check_schedules"
+CREATE INDEX "idx_show_check_schedules_next_check_at" ON "show_check_schedules" ("next_check_at");
+-- Set comment to table: "show_check_schedules"
+COMMENT ON TABLE "show_check_schedules" IS 'Per-show next check times derived from the TMDB status and the next air date';
+-- Create trigger "update_show_check_schedules_timestamp"
+CREATE TRIGGER "update_show_check_schedules_timestamp" BEFORE UPDATE ON "show_check_schedules" FOR EACH ROW EXECUTE FUNCTION "update_modified_column"();
@@ -0,0 +1,2 @@
+-- Modify "watchlists" table
+ALTER TABLE "watchlists" ADD COLUMN "aired_seasons" integer NULL;
@@ -0,0 +1,6 @@
+-- Modify "user_settings" table
+ALTER TABLE "user_settings" ADD COLUMN "region" text NOT NULL DEFAULT 'US', ADD COLUMN "subscribed_providers" integer[] NOT NULL DEFAULT '{}', ADD COLUMN "notify_availability" boolean NOT NULL DEFAULT true;
+-- Set comment to table: "user_settings"
+COMMENT ON TABLE "user_settings" IS 'Stores timezone, region, streaming services, quiet hours, digest mode and notification toggles of users';
+-- Modify "watchlists" table
+ALTER TABLE "watchlists" ADD COLUMN "available_providers" integer[] NULL;
@@ -0,0 +1,17 @@
+-- Modify "tv_shows" table
+ALTER TABLE "tv_shows" DROP CONSTRAINT "check_tv_positive_values", ADD CONSTRAINT "check_tv_non_negative_values" CHECK ((seasons >= 0) AND (episodes >= 0) AND (runtime >= 0));
+-- Create "episode_watches" table
+CREATE TABLE "episode_watches" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" bigint NOT NULL,
+  "show_api_id" bigint NOT NULL,
+  "season_number" integer NOT NULL,
+  "episode_number" integer NOT NULL,
+  "runtime" integer NOT NULL DEFAULT 0,
+  "watched_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("id"),
+  CONSTRAINT "episode_watches_user_episode_unique" UNIQUE ("user_id", "show_api_id", "season_number", "episode_number"),
+  CONSTRAINT "fk_episode_watches_user" FOREIGN KEY ("user_id") REFERENCES "users" ("tg_id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Set comment to table: "episode_watches"
+COMMENT ON TABLE "episode_watches" IS 'Stores every episode a user has watched, the totals of tv_shows are derived from it';
@@ -0,0 +1,21 @@
+-- Create "watch_events" table
+CREATE TABLE "watch_events" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" bigint NOT NULL,
+  "api_id" bigint NOT NULL,
+  "type" text NOT NULL,
+  "runtime" integer NOT NULL DEFAULT 0,
+  "watched_on" date NOT NULL DEFAULT CURRENT_DATE,
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_watch_events_user" FOREIGN KEY ("user_id") REFERENCES "users" ("tg_id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_watch_events_user_title" to table: "watch_events"
+CREATE INDEX "idx_watch_events_user_title" ON "watch_events" ("user_id", "type", "api_id");
+-- Set comment to table: "watch_events"
+COMMENT ON TABLE "watch_events" IS 'Stores every dated watch of a title, rewatches included';
+-- Record the first watch of movies watched so far on the day they were added
+INSERT INTO "watch_events" ("user_id", "api_id", "type", "runtime", "watched_on", "created_at")
+SELECT "user_id", "api_id", 'MOVIE', "runtime", "created_at"::date, "created_at"
+FROM "movies"
+WHERE "deleted_at" IS NULL;
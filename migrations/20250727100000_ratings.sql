@@ -0,0 +1,20 @@
+-- Create "ratings" table
+CREATE TABLE "ratings" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" bigint NOT NULL,
+  "api_id" bigint NOT NULL,
+  "type" text NOT NULL,
+  "season_number" integer NOT NULL DEFAULT 0,
+  "rating" smallint NOT NULL,
+  "review" text NULL,
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  "updated_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("id"),
+  CONSTRAINT "ratings_user_title_unique" UNIQUE ("user_id", "api_id", "type", "season_number"),
+  CONSTRAINT "fk_ratings_user" FOREIGN KEY ("user_id") REFERENCES "users" ("tg_id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "check_rating_range" CHECK ((rating >= 1) AND (rating <= 10))
+);
+-- Set comment to table: "ratings"
+COMMENT ON TABLE "ratings" IS 'Stores ratings and short reviews of watched titles, season 0 rates the whole title';
+-- Create trigger "update_ratings_timestamp"
+CREATE TRIGGER "update_ratings_timestamp" BEFORE UPDATE ON "ratings" FOR EACH ROW EXECUTE FUNCTION "update_modified_column"();
@@ -0,0 +1,2 @@
+-- Modify "tv_shows" table
+ALTER TABLE "tv_shows" ADD COLUMN "watch_status" text NOT NULL DEFAULT 'WATCHING', ADD CONSTRAINT "check_tv_watch_status" CHECK (watch_status = ANY (ARRAY['PLAN_TO_WATCH'::text, 'WATCHING'::text, 'COMPLETED'::text, 'ON_HOLD'::text, 'DROPPED'::text]));
@@ -0,0 +1,31 @@
+-- Create "user_lists" table
+CREATE TABLE "user_lists" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" bigint NOT NULL,
+  "name" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  "updated_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_user_lists_user" FOREIGN KEY ("user_id") REFERENCES "users" ("tg_id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_user_lists_user_name_unique" to table: "user_lists"
+CREATE UNIQUE INDEX "idx_user_lists_user_name_unique" ON "user_lists" ("user_id", (lower(name)));
+-- Set comment to table: "user_lists"
+COMMENT ON TABLE "user_lists" IS 'Stores custom lists users create besides their watchlist';
+-- Create trigger "update_user_lists_timestamp"
+CREATE TRIGGER "update_user_lists_timestamp" BEFORE UPDATE ON "user_lists" FOR EACH ROW EXECUTE FUNCTION "update_modified_column"();
+-- Create "user_list_items" table
+CREATE TABLE "user_list_items" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "list_id" uuid NOT NULL,
+  "show_api_id" bigint NOT NULL,
+  "type" text NOT NULL,
+  "title" text NOT NULL,
+  "image" text NULL,
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("id"),
+  CONSTRAINT "user_list_items_list_title_unique" UNIQUE ("list_id", "show_api_id", "type"),
+  CONSTRAINT "fk_user_list_items_list" FOREIGN KEY ("list_id") REFERENCES "user_lists" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Set comment to table: "user_list_items"
+COMMENT ON TABLE "user_list_items" IS 'Stores the movies and shows on custom lists';
@@ -0,0 +1,4 @@
+-- Modify "watchlists" table
+ALTER TABLE "watchlists" ADD COLUMN "priority" text NOT NULL DEFAULT 'NORMAL', ADD COLUMN "note" text NULL, ADD COLUMN "position" integer NOT NULL DEFAULT 0, ADD COLUMN "runtime" integer NULL, ADD CONSTRAINT "check_watchlist_priority" CHECK (priority = ANY (ARRAY['HIGH'::text, 'NORMAL'::text, 'LOW'::text]));
+-- Number existing entries in the order they were added
+UPDATE "watchlists" w SET "position" = o."position" FROM (SELECT "id", ROW_NUMBER() OVER (PARTITION BY "user_id" ORDER BY "created_at") AS "position" FROM "watchlists") o WHERE w."id" = o."id";
@@ -0,0 +1,2 @@
+-- Modify "worker_task_daily_stats" table
+COMMENT ON TABLE "worker_task_daily_stats" IS 'Daily aggregates of worker tasks removed by the retention job';
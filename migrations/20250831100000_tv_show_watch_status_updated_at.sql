@@ -0,0 +1,4 @@
+-- Modify "tv_shows" table
+ALTER TABLE "tv_shows" ADD COLUMN "watch_status_updated_at" timestamptz NULL;
+-- Rows added from now on are decided when they are added, existing ones stay NULL until the show checker looked at them
+ALTER TABLE "tv_shows" ALTER COLUMN "watch_status_updated_at" SET DEFAULT now();
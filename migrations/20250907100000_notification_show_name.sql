@@ -0,0 +1,2 @@
+-- Modify "notifications" table
+ALTER TABLE "notifications" ADD COLUMN "show_name" text NULL;
@@ -0,0 +1,2 @@
+-- Modify "notifications" table
+ALTER TABLE "notifications" ADD COLUMN "poster_sent" boolean NOT NULL DEFAULT false;
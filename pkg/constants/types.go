@@ -10,3 +10,70 @@ const (
 	LocalEnv = "local"
 	Prod     = "prod"
 )
+
+// TV show statuses as returned by TMDB
+const (
+	ShowStatusReturning    string = "Returning Series"
+	ShowStatusInProduction string = "In Production"
+	ShowStatusPlanned      string = "Planned"
+	ShowStatusPilot        string = "Pilot"
+	ShowStatusEnded        string = "Ended"
+	ShowStatusCanceled     string = "Canceled"
+)
+
+// Where a user is with a tracked TV show
+const (
+	WatchStatusPlanToWatch string = "PLAN_TO_WATCH"
+	WatchStatusWatching    string = "WATCHING"
+	WatchStatusCompleted   string = "COMPLETED"
+	WatchStatusOnHold      string = "ON_HOLD"
+	WatchStatusDropped     string = "DROPPED"
+)
+
+// How much a user wants to get to a watchlist entry
+const (
+	WatchlistPriorityHigh   string = "HIGH"
+	WatchlistPriorityNormal string = "NORMAL"
+	WatchlistPriorityLow    string = "LOW"
+)
+
+// Orders the watchlist can be shown in, manual is the order the user arranged by hand
+const (
+	WatchlistSortManual   string = "MANUAL"
+	WatchlistSortPriority string = "PRIORITY"
+	WatchlistSortAdded    string = "ADDED"
+	WatchlistSortTitle    string = "TITLE"
+	WatchlistSortRelease  string = "RELEASE"
+	WatchlistSortRuntime  string = "RUNTIME"
+)
+
+// Movie statuses as returned by TMDB
+const (
+	MovieStatusRumored        string = "Rumored"
+	MovieStatusPlanned        string = "Planned"
+	MovieStatusInProduction   string = "In Production"
+	MovieStatusPostProduction string = "Post Production"
+	MovieStatusReleased       string = "Released"
+	MovieStatusCanceled       string = "Canceled"
+)
+
+// Kinds of notifications queued by workers, each can be turned off in user settings
+const (
+	NotificationKindNewSeason       string = "new_season"
+	NotificationKindStatusChange    string = "status_change"
+	NotificationKindEpisodeReminder string = "episode_reminder"
+	NotificationKindMovieRelease    string = "movie_release"
+	NotificationKindWatchlistShow   string = "watchlist_show"
+	NotificationKindAvailability    string = "availability"
+	NotificationKindDigest          string = "digest"
+)
+
+// How show updates reach a user: one message each, or bundled into a digest per check cycle or per week
+const (
+	DigestModeOff    string = "off"
+	DigestModeCycle  string = "cycle"
+	DigestModeWeekly string = "weekly"
+)
+
+// DefaultRegion is the TMDB watch region of users who never picked one
+const DefaultRegion string = "US"
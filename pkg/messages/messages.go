@@ -21,32 +21,108 @@ const (
 		"4. Follow the instructions and fill out the required form as needed.\n" +
 		"5. Once you have your *API Key* (not the API Read Access Token), return to this bot and paste the key here to test and save it.\n\n" +
 		"If you’d prefer assistance, feel free to contact [me](https://t.me/erkinov_wiz), and I'll help you get your key."
-	TokenTestFailed         = "Your token failed the test. Ensure it is correct and write/paste it here again."
-	TokenSaved              = "Your token has been successfully tested and saved. From now on, this token will be used exclusively for you in this bot. \nPlease use the /help command to get started."
-	TokenAlreadyExists      = "Your Api Token has been already saved, no need to worry"
-	Loading                 = "Loading..."
-	InfoFirstMessage        = "What you want info about?"
-	MovieSelected           = "Selected the movie!"
-	TVShowSelected          = "Selected the TV show!"
-	WatchedMovie            = "You have already watched this movie"
-	NoSearchResult          = "No search results found"
-	BackToSearchResults     = "Returning to search results"
-	WatchedSeason           = "You already watched this season, please select later seasons"
-	WatchlistSelectType     = "Which type of watchlist do you want?"
-	NoWatchlistData         = "No records found"
-	NoChanges               = "No changes to display"
-	PageUpdated             = "Page updated"
-	RegistrationRequired    = "You need to register to use this bot. Please type /start to continue"
-	MenuSearchTVResponse    = "Write TV Show Title"
-	MenuSearchMovieResponse = "Write Movie Title"
-	TokenRequired           = "Can't search now, send API token"
+	TokenTestFailed          = "Your token failed the test. Ensure it is correct and write/paste it here again."
+	TokenSaved               = "Your token has been successfully tested and saved. From now on, this token will be used exclusively for you in this bot. \nPlease use the /help command to get started."
+	TokenAlreadyExists       = "Your Api Token has been already saved, no need to worry"
+	Loading                  = "Loading..."
+	InfoFirstMessage         = "What you want info about?"
+	MovieSelected            = "Selected the movie!"
+	TVShowSelected           = "Selected the TV show!"
+	WatchedMovie             = "You have already watched this movie"
+	WatchDatePrompt          = "📅 When did you watch it?"
+	SelectWatchDate          = "📆 Pick the day you watched it"
+	NoSearchResult           = "No search results found"
+	BackToSearchResults      = "Returning to search results"
+	WatchedSeason            = "You are already at this season, pick a later one to go on or an earlier one to lower your progress"
+	WatchlistSelectType      = "Which type of watchlist do you want?"
+	NoWatchlistData          = "No records found"
+	NoChanges                = "No changes to display"
+	PageUpdated              = "Page updated"
+	RegistrationRequired     = "You need to register to use this bot. Please type /start to continue"
+	MenuSearchTVResponse     = "Write TV Show Title"
+	MenuSearchMovieResponse  = "Write Movie Title"
+	TokenRequired            = "Can't search now, send API token"
+	AlreadyWatchlisted       = "Already in your watchlist"
+	EpisodeMarkedWatched     = "Episode marked as watched!"
+	EpisodeAlreadyWatched    = "You already marked this episode as watched"
+	EpisodeUnmarked          = "Episode unmarked"
+	SeasonMarkedWatched      = "Season marked as watched!"
+	AlertSnoozed             = "Got it, I will remind you again in a few days"
+	NothingToSnooze          = "This alert can't be snoozed anymore"
+	RemovedFromWatchlist     = "Removed from your watchlist"
+	SettingsSaved            = "Settings saved"
+	SettingsSelectTimezone   = "🌍 Pick your timezone, or send `/settings tz Area/City` for any other one"
+	SettingsSelectQuietStart = "🌙 When should quiet hours start?"
+	SettingsSelectQuietEnd   = "🌙 Quiet hours start at %02d:00. When should they end?"
+	SettingsSelectRegion     = "🗺 Pick your region, or send `/settings region XX` for any other country code"
+	SettingsSelectProviders  = "📺 Tap the streaming services you subscribe to in %v, you will hear when a watchlisted title lands on one of them"
+	AdminOnly                = "This command is available to bot admins only"
+	WorkerRunStarted         = "Run started"
+	WorkerPaused             = "Worker paused"
+	WorkerResumed            = "Worker resumed"
+	WorkerRecheckUsage       = "Send /worker recheck <user_id> <show_id> to re-check a single show of a user"
+	WorkerRecheckUpdated     = "Show re-checked, an update was found and the user was notified"
+	WorkerRecheckNoUpdates   = "Show re-checked, no updates found"
+	RatingSaved              = "⭐ You rated it *%d/10*"
+	RatingSkipped            = "No rating this time"
+	ReviewPrompt             = "✍️ Send a short review in one message, up to %d characters"
+	ReviewSaved              = "✍️ Review saved"
+	ReviewEmpty              = "The review is empty, please send some text"
+	MovieUnwatched           = "🗑 *%s* was removed from your watched movies"
+	ShowUnwatched            = "🗑 *%s* was removed from your watched shows"
+	SeasonsLowered           = "⏪ *%s* is now at %d watched season(s)"
+	TitleRestored            = "↩️ *%s* was restored"
+	WatchStatusPrompt        = "🏷 *%s* is %s, pick a new status"
+	WatchStatusSaved         = "🏷 *%s* is now %s"
+	ListsOverview            = "📂 *Your lists*\n\nThe watchlist is your default list, create more to group titles any way you like"
+	ListNamePrompt           = "✍️ Send a name for the new list, up to %d characters"
+	ListRenamePrompt         = "✍️ Send a new name for *%s*, up to %d characters"
+	ListCreated              = "📂 List *%s* created, add titles with 📂 Add to list… on their detail cards"
+	ListRenamed              = "✏️ List renamed to *%s*"
+	ListDeleteConfirm        = "🗑 Delete *%s* with its %d title(s)? The titles stay in your library and watchlist"
+	ListDeleted              = "List deleted"
+	ListPickerPrompt         = "📂 Tap a list to add *%s* to it or remove it from there"
+	AddedToList              = "Added to %s"
+	RemovedFromList          = "Removed from %s"
+	WatchlistEntryEditor     = "✏️ *%s*\n\n🔥 *Priority*: %s\n🗒 *Note*: %s"
+	WatchlistNoNote          = "_none_"
+	WatchlistNotePrompt      = "🗒 Send a note for *%s* in one message, up to %d characters"
+	WatchlistNoteSaved       = "🗒 Note saved"
+	WatchlistNoteCleared     = "Note cleared"
+	WatchlistPrioritySaved   = "Priority set to %s"
+	WatchlistMoved           = "Moved"
+	WatchlistMoveEdge        = "Already at the edge of this view"
 )
 
 const (
-	InternalError       = "Something went wrong, please try again"
-	MalformedData       = "Malformed data received"
-	UnknownAction       = "Unknown action"
-	InvalidSeason       = "Invalid season number received"
-	InvalidPageNumber   = "Invalid page number"
-	WatchlistCheckError = "Something went wrong while checking your watchlist."
+	InternalError        = "Something went wrong, please try again"
+	MalformedData        = "Malformed data received"
+	UnknownAction        = "Unknown action"
+	InvalidSeason        = "Invalid season number received"
+	InvalidPageNumber    = "Invalid page number"
+	WatchlistCheckError  = "Something went wrong while checking your watchlist."
+	InvalidEpisode       = "Invalid episode data received"
+	InvalidTimezone      = "Unknown timezone, please use a name like Europe/Berlin"
+	InvalidQuietHours    = "Quiet hours must start and end at different hours"
+	InvalidRegion        = "Unknown region, please use a two letter country code like US or DE"
+	InvalidWatchDate     = "Invalid watch date received"
+	FutureWatchDate      = "That day hasn't come yet"
+	WorkerNotLeader      = "Another bot instance runs this worker"
+	WorkerAlreadyRunning = "This worker is already running"
+	WorkerNotScheduled   = "This worker can't be run manually"
+	ShowNotTracked       = "The user doesn't track this show"
+	InvalidRating        = "Invalid rating received"
+	RatingNotFound       = "Rate the title first, then add a review"
+	NotInLibrary         = "This title isn't in your watched library"
+	UndoExpired          = "Too late to undo, the change is kept"
+	InvalidWatchStatus   = "Invalid watch status received"
+	InvalidList          = "Invalid list data received"
+	ListNotFound         = "This list doesn't exist anymore"
+	ListNameEmpty        = "The name is empty, please send some text"
+	ListNameTooLong      = "List names can be up to %d characters, please send a shorter one"
+	ListNameTaken        = "You already have a list called %s, please send another name"
+	ListLimitReached     = "You can have up to %d lists, delete one first"
+	NotOnWatchlist       = "This title isn't on your watchlist anymore"
+	InvalidPriority      = "Invalid priority received"
+	WatchlistNoteEmpty   = "The note is empty, please send some text"
 )
@@ -0,0 +1,29 @@
+package paginators
+
+import (
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+)
+
+func PaginateListItems(items []database.GetListItemsRow, page int) []database.GetListItemsRow {
+
+	if page < 1 {
+		page = 1
+	}
+
+	startIndex := (page - 1) * itemsPerPage
+	endIndex := startIndex + itemsPerPage
+
+	if len(items) == 0 {
+		return []database.GetListItemsRow{}
+	}
+
+	if startIndex >= len(items) {
+		startIndex = (len(items) - 1) / itemsPerPage * itemsPerPage
+	}
+
+	if endIndex > len(items) {
+		endIndex = len(items)
+	}
+
+	return items[startIndex:endIndex]
+}
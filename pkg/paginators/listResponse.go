@@ -0,0 +1,64 @@
+package paginators
+
+import (
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"gopkg.in/telebot.v3"
+)
+
+// GenerateListItemsResponse renders one page of a list, listKey is the list as used in callback data and listName
+// is already escaped. The default list is the watchlist, it gets a link to its full view instead of list actions.
+func GenerateListItemsResponse(paginatedItems *[]database.GetListItemsRow, currentPage, maxPage, itemCount int, listKey, listName string, isDefault bool) (string, *telebot.ReplyMarkup) {
+	response := fmt.Sprintf("*%v*\n\n", listName)
+	for _, item := range *paginatedItems {
+		var typeStr string
+		if item.Type == constants.TVShowType {
+			typeStr = "📺 Tv Show"
+		} else {
+			typeStr = "🎥 Movie"
+		}
+		response += fmt.Sprintf(
+			"🎬 *Title*: %v\n"+
+				"📝 *Type*: %v\n"+
+				"📅 *Added At*: %v\n\n",
+			item.Title,
+			typeStr,
+			item.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+		)
+	}
+
+	btn := &telebot.ReplyMarkup{}
+	btnRow := telebot.Row{}
+
+	for i, item := range *paginatedItems {
+		btnRow = append(btnRow, btn.Data(fmt.Sprintf("%d️⃣", i+1), "", fmt.Sprintf("list|info|%v-%v-%v", listKey, item.Type, item.ShowApiID)))
+	}
+
+	var rows []telebot.Row
+	if itemCount > 0 {
+		rows = append(rows,
+			btnRow,
+			btn.Row(
+				btn.Data("⏮️ Prev", "", fmt.Sprintf("list|prev|%s-%v", listKey, currentPage)),
+				btn.Text(fmt.Sprintf("%d | %d • %d", currentPage, maxPage, itemCount)),
+				btn.Data("Next ⏭️", "", fmt.Sprintf("list|next|%s-%v", listKey, currentPage)),
+			),
+		)
+	} else {
+		response += "_Nothing here yet, use 📂 Add to list on a movie or show_\n"
+	}
+	// The default list can't be renamed or deleted, its priorities and sorting are one tap away instead
+	if isDefault {
+		rows = append(rows, btn.Row(btn.Data("⚙️ Sort & priorities", "", "watchlist|full|-")))
+	} else {
+		rows = append(rows, btn.Row(
+			btn.Data("✏️ Rename", "", fmt.Sprintf("list|rename|%s", listKey)),
+			btn.Data("🗑 Delete", "", fmt.Sprintf("list|delete|%s", listKey)),
+		))
+	}
+	rows = append(rows, btn.Row(btn.Data("🔙 Back to lists", "", "list|overview|-")))
+	btn.Inline(rows...)
+
+	return response, btn
+}
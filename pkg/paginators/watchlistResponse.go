@@ -4,10 +4,19 @@ import (
 	"fmt"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
 	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
 	"gopkg.in/telebot.v3"
 )
 
-func GenerateWatchlistResponse(paginatedWatchlists *[]database.GetUserWatchlistsRow, currentPage, maxPage, watchlistCount int, watchlistType string) (string, *telebot.ReplyMarkup) {
+var watchlistTypeLabels = map[string]string{
+	constants.AllType:    "🍿 All types",
+	constants.MovieType:  "🎥 Movies",
+	constants.TVShowType: "📺 TV Shows",
+}
+
+// GenerateWatchlistResponse renders one page of the watchlist. watchlistType, priority and sortBy describe the
+// current view, the control buttons show them and cycle to the next value when tapped.
+func GenerateWatchlistResponse(paginatedWatchlists *[]database.GetWatchlistEntriesRow, currentPage, maxPage, watchlistCount int, watchlistType, priority, sortBy string) (string, *telebot.ReplyMarkup) {
 	var response string
 	for _, w := range *paginatedWatchlists {
 		var typeStr string
@@ -19,60 +28,57 @@ func GenerateWatchlistResponse(paginatedWatchlists *[]database.GetUserWatchlists
 		response += fmt.Sprintf(
 			"🎬 *Title*: %v\n"+
 				"📝 *Type*: %v\n"+
-				"📅 *Added At*: %v\n\n",
+				"🔥 *Priority*: %v\n",
 			w.Title,
 			typeStr,
-			w.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+			utils.WatchlistPriorityLabel(w.Priority),
 		)
+		if w.Note != nil {
+			response += fmt.Sprintf("🗒 *Note*: %v\n", utils.EscapeMarkdown(*w.Note))
+		}
+		if w.ReleaseDate.Valid {
+			// For shows the stored date is the premiere of the next season, not the first air date
+			releaseLabel := "Release"
+			if w.Type == constants.TVShowType {
+				releaseLabel = "Next season"
+			}
+			response += fmt.Sprintf("🗓 *%v*: %v\n", releaseLabel, w.ReleaseDate.Time.Format(constants.DateFormat))
+		}
+		if w.Runtime != nil {
+			response += fmt.Sprintf("⏳ *Runtime*: %v minutes\n", *w.Runtime)
+		}
+		response += fmt.Sprintf("📅 *Added At*: %v\n\n", w.CreatedAt.Time.Format("2006-01-02 15:04:05"))
 	}
 
 	btn := &telebot.ReplyMarkup{}
-	btnRow := telebot.Row{}
+	infoRow := telebot.Row{}
+	editRow := telebot.Row{}
 
 	for i, w := range *paginatedWatchlists {
-		btnRow = append(btnRow, btn.Data(fmt.Sprintf("%d️⃣", i+1), "", fmt.Sprintf("watchlist|info|%v-%v", w.Type, w.ShowApiID)))
+		infoRow = append(infoRow, btn.Data(fmt.Sprintf("%d️⃣", i+1), "", fmt.Sprintf("watchlist|info|%v-%v", w.Type, w.ShowApiID)))
+		editRow = append(editRow, btn.Data(fmt.Sprintf("✏️ %d", i+1), "", fmt.Sprintf("watchlist|edit|%v-%v", w.Type, w.ShowApiID)))
 	}
 
-	btn.Inline(
-		btnRow,
-		btn.Row(
-			btn.Data("⏮️ Prev", "", fmt.Sprintf("watchlist|prev|%s-%v", watchlistType, currentPage)),
-			btn.Text(fmt.Sprintf("%d | %d • %d", currentPage, maxPage, watchlistCount)),
-			btn.Data("Next ⏭️", "", fmt.Sprintf("watchlist|next|%s-%v", watchlistType, currentPage)),
-		),
-	)
-
-	return response, btn
-}
-
-func GenerateWatchlistWithTypeResponse(paginatedWatchlists *[]database.GetUserWatchlistsWithTypeRow, currentPage, maxPage, watchlistCount int, watchlistType string) (string, *telebot.ReplyMarkup) {
-	var response string
-	for _, w := range *paginatedWatchlists {
-		var typeStr string
-		if w.Type == constants.TVShowType {
-			typeStr = "📺 Tv Show"
-		} else {
-			typeStr = "🎥 Movie"
-		}
-		response += fmt.Sprintf(
-			"🎬 *Title*: %v\n"+
-				"📝 *Type*: %v\n"+
-				"📅 *Added At*: %v\n\n",
-			w.Title,
-			typeStr,
-			w.CreatedAt.Time.Format("2006-01-02 15:04:05"),
-		)
+	priorityLabel := "🎯 All priorities"
+	if priority != constants.AllType {
+		priorityLabel = utils.WatchlistPriorityLabel(priority)
 	}
+	controls := btn.Row(
+		btn.Data(utils.WatchlistSortLabel(sortBy), "", fmt.Sprintf("watchlist|sort|%s", watchlistType)),
+		btn.Data(priorityLabel, "", fmt.Sprintf("watchlist|filter|%s", watchlistType)),
+		btn.Data(watchlistTypeLabels[watchlistType], "", fmt.Sprintf("watchlist|type|%s", watchlistType)),
+	)
 
-	btn := &telebot.ReplyMarkup{}
-	btnRow := telebot.Row{}
-
-	for i, w := range *paginatedWatchlists {
-		btnRow = append(btnRow, btn.Data(fmt.Sprintf("%d️⃣", i+1), "", fmt.Sprintf("watchlist|info|%v-%v", w.Type, w.ShowApiID)))
+	if watchlistCount == 0 {
+		response = "_No titles match these filters, tap the buttons below to change them_\n"
+		btn.Inline(controls)
+		return response, btn
 	}
 
 	btn.Inline(
-		btnRow,
+		infoRow,
+		editRow,
+		controls,
 		btn.Row(
 			btn.Data("⏮️ Prev", "", fmt.Sprintf("watchlist|prev|%s-%v", watchlistType, currentPage)),
 			btn.Text(fmt.Sprintf("%d | %d • %d", currentPage, maxPage, watchlistCount)),
@@ -0,0 +1,46 @@
+package utils
+
+// MaxCaptionLength is the longest photo caption Telegram accepts, counted in UTF-16 code units
+const MaxCaptionLength = 1024
+
+// CaptionLength measures text the way Telegram measures a caption. Markdown markers are counted as well, which
+// only errs on the safe side.
+func CaptionLength(text string) int {
+	length := 0
+	for _, r := range text {
+		length += utf16Length(r)
+	}
+	return length
+}
+
+// FitCaption builds a card caption around the overview and shortens the overview when the whole caption would
+// not fit into a photo caption. The rest of the card is kept as it is.
+func FitCaption(overview string, build func(overview string) string) string {
+	caption := build(overview)
+	excess := CaptionLength(caption) - MaxCaptionLength
+	if excess <= 0 {
+		return caption
+	}
+
+	// One unit more for the ellipsis
+	keep := CaptionLength(overview) - excess - 1
+	var short []rune
+	length := 0
+	for _, r := range overview {
+		length += utf16Length(r)
+		if length > keep {
+			break
+		}
+		short = append(short, r)
+	}
+
+	return build(string(short) + "…")
+}
+
+// utf16Length is how many UTF-16 code units a rune takes, characters outside the basic plane like most emoji take two
+func utf16Length(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
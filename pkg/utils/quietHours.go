@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"time"
+)
+
+// LoadLocation resolves an IANA timezone name, falling back to UTC for unknown names
+func LoadLocation(timezone string) *time.Location {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// QuietHoursEnd returns the moment the quiet hours covering now end, or the zero time when now is
+// outside of them. Start and end are hours in the given timezone, a window may wrap past midnight.
+func QuietHoursEnd(now time.Time, timezone string, start, end *int32) time.Time {
+	if start == nil || end == nil || *start == *end {
+		return time.Time{}
+	}
+
+	local := now.In(LoadLocation(timezone))
+	hour := int32(local.Hour())
+
+	var inQuietHours bool
+	if *start < *end {
+		inQuietHours = hour >= *start && hour < *end
+	} else {
+		inQuietHours = hour >= *start || hour < *end
+	}
+	if !inQuietHours {
+		return time.Time{}
+	}
+
+	endsAt := time.Date(local.Year(), local.Month(), local.Day(), int(*end), 0, 0, 0, local.Location())
+	if !endsAt.After(local) {
+		endsAt = endsAt.AddDate(0, 0, 1)
+	}
+	return endsAt
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	appCfg "github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+	"strings"
+	"time"
+)
+
+// maxCardReviewLength shortens reviews on detail cards, photo captions are limited to 1024 characters
+const maxCardReviewLength = 120
+
+var markdownEscaper = strings.NewReplacer("_", "\\_", "*", "\\*", "`", "\\`", "[", "\\[")
+
+// EscapeMarkdown makes text written by users safe to send with telebot.ModeMarkdown
+func EscapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// RatingSection returns the user's rating lines of a detail card, or nothing when the title isn't rated.
+// Ratings are a nice extra, so the card is shown without them when they can't be fetched.
+func RatingSection(app *appCfg.App, showType string, apiId int64, userId int64) string {
+	const op = "utils.RatingSection"
+	ctxDb, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	ratings, err := app.Repository.Ratings.GetTitleRatings(ctxDb, userId, apiId, showType)
+	if err != nil {
+		app.Logger.Warning(op, nil, "Showing card without ratings", "api_id", apiId, "error", err.Error())
+		return ""
+	}
+	if len(ratings) == 0 {
+		return ""
+	}
+
+	var text strings.Builder
+	var seasons []string
+	for _, rating := range ratings {
+		if rating.SeasonNumber > 0 {
+			seasons = append(seasons, fmt.Sprintf("S%d *%d*", rating.SeasonNumber, rating.Rating))
+			continue
+		}
+
+		text.WriteString(fmt.Sprintf("⭐ *Your Rating*: %d/10\n", rating.Rating))
+		if rating.Review != nil {
+			review := []rune(*rating.Review)
+			if len(review) > maxCardReviewLength {
+				review = append(review[:maxCardReviewLength], '…')
+			}
+			text.WriteString(fmt.Sprintf("💬 %s\n", markdownEscaper.Replace(string(review))))
+		}
+	}
+	if len(seasons) > 0 {
+		text.WriteString(fmt.Sprintf("📀 *Season Ratings*: %s\n", strings.Join(seasons, ", ")))
+	}
+
+	return "\n" + text.String()
+}
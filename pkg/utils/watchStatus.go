@@ -0,0 +1,43 @@
+package utils
+
+import "github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+
+// WatchStatuses lists the watch statuses of tracked shows in the order they are offered to users
+var WatchStatuses = []string{
+	constants.WatchStatusPlanToWatch,
+	constants.WatchStatusWatching,
+	constants.WatchStatusCompleted,
+	constants.WatchStatusOnHold,
+	constants.WatchStatusDropped,
+}
+
+var watchStatusLabels = map[string]string{
+	constants.WatchStatusPlanToWatch: "🗓 Plan to watch",
+	constants.WatchStatusWatching:    "▶️ Watching",
+	constants.WatchStatusCompleted:   "✅ Completed",
+	constants.WatchStatusOnHold:      "⏸ On hold",
+	constants.WatchStatusDropped:     "🚫 Dropped",
+}
+
+// WatchStatusLabel returns the user facing name of a watch status
+func WatchStatusLabel(status string) string {
+	if label, ok := watchStatusLabels[status]; ok {
+		return label
+	}
+	return status
+}
+
+// AutoWatchStatus returns the watch status a show moves to on its own. Every season of a show that has ended
+// watched means completed, and a completed show that got behind again, because of a lowered progress or a
+// revival, is watched again. Statuses picked by hand are kept otherwise.
+func AutoWatchStatus(current string, watchedSeasons, totalSeasons int32, showStatus string) string {
+	ended := showStatus == constants.ShowStatusEnded || showStatus == constants.ShowStatusCanceled
+	switch {
+	case ended && totalSeasons > 0 && watchedSeasons >= totalSeasons:
+		return constants.WatchStatusCompleted
+	case current == constants.WatchStatusCompleted:
+		return constants.WatchStatusWatching
+	default:
+		return current
+	}
+}
@@ -0,0 +1,69 @@
+package utils
+
+import "github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+
+// WatchlistPriorities lists the priorities of watchlist entries from the most to the least wanted
+var WatchlistPriorities = []string{
+	constants.WatchlistPriorityHigh,
+	constants.WatchlistPriorityNormal,
+	constants.WatchlistPriorityLow,
+}
+
+// WatchlistSorts lists the orders of the watchlist in the order the sort button cycles through them
+var WatchlistSorts = []string{
+	constants.WatchlistSortManual,
+	constants.WatchlistSortPriority,
+	constants.WatchlistSortAdded,
+	constants.WatchlistSortTitle,
+	constants.WatchlistSortRelease,
+	constants.WatchlistSortRuntime,
+}
+
+var watchlistPriorityLabels = map[string]string{
+	constants.WatchlistPriorityHigh:   "🔴 High",
+	constants.WatchlistPriorityNormal: "🟡 Normal",
+	constants.WatchlistPriorityLow:    "🟢 Low",
+}
+
+var watchlistSortLabels = map[string]string{
+	constants.WatchlistSortManual:   "✋ My order",
+	constants.WatchlistSortPriority: "🔥 Priority",
+	constants.WatchlistSortAdded:    "🆕 Recently added",
+	constants.WatchlistSortTitle:    "🔤 Title",
+	constants.WatchlistSortRelease:  "📅 Release / next season",
+	constants.WatchlistSortRuntime:  "⏳ Runtime",
+}
+
+// WatchlistPriorityLabel returns the user facing name of a watchlist priority
+func WatchlistPriorityLabel(priority string) string {
+	if label, ok := watchlistPriorityLabels[priority]; ok {
+		return label
+	}
+	return priority
+}
+
+// WatchlistSortLabel returns the user facing name of a watchlist order
+func WatchlistSortLabel(sort string) string {
+	if label, ok := watchlistSortLabels[sort]; ok {
+		return label
+	}
+	return sort
+}
+
+// NextInCycle returns the value after current in values, wrapping around to the first one
+func NextInCycle(values []string, current string) string {
+	for i, value := range values {
+		if value == current {
+			return values[(i+1)%len(values)]
+		}
+	}
+	return values[0]
+}
+
+// WatchlistRuntime turns a runtime in minutes from TMDB into the stored one, TMDB sends 0 when it is unknown
+func WatchlistRuntime(minutes int32) *int32 {
+	if minutes <= 0 {
+		return nil
+	}
+	return &minutes
+}
@@ -0,0 +1,224 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/providers"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"gopkg.in/telebot.v3"
+	"slices"
+	"time"
+)
+
+func (c *WorkerApiClient) GetWatchProviders(ctx context.Context, app *app.App, showType string, apiId int, userId int64) (*providers.WatchProviders, error) {
+	const op = "workers.GetWatchProviders"
+	app.Logger.WorkerDebug(op, "Attempting to fetch watch providers",
+		"title_id", apiId, "type", showType, "user_id", userId)
+
+	err := c.limiter.Wait(ctx)
+	if err != nil {
+		app.Logger.WorkerError(op, "Rate limit wait error",
+			"title_id", apiId, "error", err.Error())
+		return nil, fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	resource := app.Cfg.Endpoints.Resources.GetMovie
+	if showType == constants.TVShowType {
+		resource = app.Cfg.Endpoints.Resources.GetTV
+	}
+
+	start := time.Now()
+	result, err := providers.GetWatchProviders(ctx, app, resource, apiId, userId)
+	duration := time.Since(start)
+
+	if err != nil {
+		app.Logger.WorkerError(op, "API request failed",
+			"title_id", apiId, "duration_ms", duration.Milliseconds(), "error", err.Error())
+		return nil, fmt.Errorf("failed to get watch providers: %w", err)
+	}
+
+	app.Logger.WorkerInfo(op, "Successfully fetched watch providers",
+		"title_id", apiId, "regions", len(result.Results), "duration_ms", duration.Milliseconds())
+	return result, nil
+}
+
+// availabilityKey identifies a title on TMDB, movies and TV shows have separate ID spaces
+type availabilityKey struct {
+	showType string
+	apiId    int64
+}
+
+// runCheck is one run of the check_availability job
+func (c *AvailabilityChecker) runCheck(ctx context.Context) (JobResult, error) {
+	titles, updates, err := c.checkAvailability(ctx)
+	return JobResult{Checked: titles, Updates: updates}, err
+}
+
+// checkAvailability fetches the watch providers of every watchlisted title once and compares them with the
+// providers stored for each entry. It returns the number of titles checked and the number of notifications queued.
+func (c *AvailabilityChecker) checkAvailability(ctx context.Context) (int, int, error) {
+	const op = "workers.checkAvailability"
+	ctxDb, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	entries, err := c.app.Repository.Watchlists.GetWatchlistAvailability(ctxDb)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Error fetching watchlist entries", "error", err.Error())
+		return 0, 0, err
+	}
+
+	// One response covers every region, so each title is fetched once with the key of one of its watchers
+	var keys []availabilityKey
+	byTitle := make(map[availabilityKey][]database.GetWatchlistAvailabilityRow)
+	for _, entry := range entries {
+		key := availabilityKey{showType: entry.Type, apiId: entry.ShowApiID}
+		if _, exists := byTitle[key]; !exists {
+			keys = append(keys, key)
+		}
+		byTitle[key] = append(byTitle[key], entry)
+	}
+
+	c.app.Logger.WorkerInfo(op, "Found watchlisted titles to check",
+		"title_count", len(keys), "entry_count", len(entries))
+
+	updates := 0
+	for i, key := range keys {
+		if ctx.Err() != nil {
+			return i, updates, ctx.Err()
+		}
+
+		watchers := byTitle[key]
+		result, err := c.fetchWatchProviders(ctx, key, watchers)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range watchers {
+			if c.processEntry(ctx, entry, result) {
+				updates++
+			}
+		}
+	}
+
+	return len(keys), updates, nil
+}
+
+// fetchWatchProviders fetches the providers of a title with the key of one of its watchers. An invalid key of one
+// user should not hide a new service from everyone else, so a few other watchers' keys are tried before giving up.
+func (c *AvailabilityChecker) fetchWatchProviders(ctx context.Context, key availabilityKey, watchers []database.GetWatchlistAvailabilityRow) (*providers.WatchProviders, error) {
+	const op = "workers.fetchWatchProviders"
+	var lastErr error
+	attempts := 0
+	for _, watcher := range watchers {
+		if attempts == maxKeyAttempts {
+			break
+		}
+
+		attempts++
+		result, err := c.apiClient.GetWatchProviders(ctx, c.app, key.showType, int(key.apiId), watcher.UserID)
+		if err == nil {
+			return result, nil
+		}
+
+		lastErr = err
+		// Only a rejected key is worth retrying with another watcher's key
+		if !errors.Is(err, tmdb.ErrUnauthorized) {
+			break
+		}
+
+		c.app.Logger.WorkerWarning(op, "Key rejected while fetching watch providers, trying another watcher's key",
+			"title_id", key.apiId, "type", key.showType, "user_id", watcher.UserID, "error", err.Error())
+	}
+
+	c.app.Logger.WorkerError(op, "Error fetching watch providers",
+		"title_id", key.apiId, "type", key.showType, "attempts", attempts, "error", lastErr.Error())
+	return nil, lastErr
+}
+
+// processEntry stores the streaming services offering a title in the owner's region and notifies the owner when
+// one of their subscribed services starts offering it. The first check of an entry only records a baseline.
+func (c *AvailabilityChecker) processEntry(ctx context.Context, entry database.GetWatchlistAvailabilityRow, result *providers.WatchProviders) bool {
+	const op = "workers.processEntry"
+
+	regionProviders := result.Results[entry.Region]
+	current := regionProviders.FlatrateIDs()
+	slices.Sort(current)
+	if entry.AvailableProviders != nil && slices.Equal(current, entry.AvailableProviders) {
+		return false
+	}
+
+	ctxDb, cancel := persistContext(ctx)
+	defer cancel()
+
+	err := c.app.Repository.Watchlists.UpdateWatchlistProviders(ctxDb, entry.ID, current)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to store watch providers",
+			"title_id", entry.ShowApiID, "user_id", entry.UserID, "error", err.Error())
+		return false
+	}
+
+	if entry.AvailableProviders == nil {
+		c.app.Logger.WorkerDebug(op, "Stored initial watch providers",
+			"title_id", entry.ShowApiID, "user_id", entry.UserID, "region", entry.Region, "count", len(current))
+		return false
+	}
+
+	// Only services the user pays for are worth a message, everything else still shows up on the detail card
+	var added []providers.Provider
+	for _, provider := range regionProviders.Flatrate {
+		if !slices.Contains(entry.AvailableProviders, provider.ID) && slices.Contains(entry.SubscribedProviders, provider.ID) {
+			added = append(added, provider)
+		}
+	}
+	if len(added) == 0 {
+		c.app.Logger.WorkerDebug(op, "Watch providers changed without a subscribed service",
+			"title_id", entry.ShowApiID, "user_id", entry.UserID, "region", entry.Region)
+		return false
+	}
+
+	c.app.Logger.WorkerInfo(op, "New streaming availability detected",
+		"title_id", entry.ShowApiID, "user_id", entry.UserID, "title", entry.Title,
+		"region", entry.Region, "providers", providers.Names(added, len(added)))
+	return c.notifyUser(entry, added, regionProviders.Link)
+}
+
+func (c *AvailabilityChecker) notifyUser(entry database.GetWatchlistAvailabilityRow, added []providers.Provider, link string) bool {
+	const op = "workers.notifyUser"
+	c.app.Logger.WorkerInfo(op, "Queueing availability notification for user",
+		"user_id", entry.UserID, "title_id", entry.ShowApiID, "title", entry.Title)
+
+	caption := fmt.Sprintf(
+		"📺 Now streaming on %v\n\n"+
+			"🎬 *Title*: %v\n\n"+
+			"🗺 *Region*: %v\n",
+		providers.Names(added, len(added)),
+		entry.Title,
+		entry.Region,
+	)
+
+	replyMarkup := &telebot.ReplyMarkup{}
+	var actionButton telebot.Btn
+	if entry.Type == constants.TVShowType {
+		actionButton = replyMarkup.Data("👀 Start tracking", fmt.Sprintf("tv|select_seasons|%v", entry.ShowApiID))
+	} else {
+		actionButton = replyMarkup.Data("👀 Watched", fmt.Sprintf("movie|watched|%v", entry.ShowApiID))
+	}
+	rows := []telebot.Row{replyMarkup.Row(actionButton)}
+	if link != "" {
+		rows = append(rows, replyMarkup.Row(replyMarkup.URL("📡 Where to Watch", link)))
+	}
+	replyMarkup.Inline(rows...)
+
+	err := c.enqueueNotification(entry.UserID, constants.NotificationKindAvailability, entry.ShowApiID, caption, "", replyMarkup)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to queue availability notification", "user_id", entry.UserID, "error", err.Error())
+		return false
+	}
+
+	c.app.Logger.WorkerInfo(op, "Availability notification queued successfully", "user_id", entry.UserID, "title_id", entry.ShowApiID)
+	return true
+}
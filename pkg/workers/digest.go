@@ -0,0 +1,246 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"github.com/google/uuid"
+	"gopkg.in/telebot.v3"
+	"strings"
+	"time"
+)
+
+const (
+	digestWeek = 7 * 24 * time.Hour
+	// maxDigestShows keeps a digest within Telegram's message size limit
+	maxDigestShows = 30
+)
+
+// digestMode returns how the user wants to receive show updates, errors fall back to separate messages
+func (c *workerBase) digestMode(userId int64) string {
+	const op = "workers.digestMode"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	settings, err := c.app.Repository.Settings.GetUserSettings(ctx, userId)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to get user settings",
+			"user_id", userId, "error", err.Error())
+		return constants.DigestModeOff
+	}
+
+	return settings.DigestMode
+}
+
+// holdForDigest stores a one line summary of an update, it is delivered as part of the user's next digest.
+// The show name is kept with it, the show may be removed before the digest goes out.
+func (c *workerBase) holdForDigest(userId int64, kind string, showId int64, showName, summary string) error {
+	const op = "workers.holdForDigest"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := c.app.Repository.Notifications.HoldNotification(ctx, database.HoldNotificationParams{
+		UserID:    userId,
+		Kind:      kind,
+		ShowApiID: &showId,
+		Text:      summary,
+		ShowName:  &showName,
+	})
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to hold notification for digest",
+			"user_id", userId, "kind", kind, "show_id", showId, "error", err.Error())
+		return err
+	}
+
+	c.app.Logger.WorkerDebug(op, "Notification held for digest",
+		"user_id", userId, "kind", kind, "show_id", showId)
+	return nil
+}
+
+// sendDigests bundles held updates of every user whose digest is due into a single message.
+// Users who switched digests off get their leftovers right away. It returns the number of users
+// with held updates and the number of digests queued.
+func (c *TVShowChecker) sendDigests() (int, int) {
+	const op = "workers.sendDigests"
+	ctxDb, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	users, err := c.app.Repository.Notifications.GetHeldNotificationUsers(ctxDb)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Error fetching users with held notifications", "error", err.Error())
+		return 0, 0
+	}
+
+	c.app.Logger.WorkerInfo(op, "Found users with held notifications", "user_count", len(users))
+
+	sent := 0
+	for _, user := range users {
+		settings, err := c.userSettings(user.UserID)
+		if err != nil {
+			c.app.Logger.WorkerError(op, "Failed to get user settings",
+				"user_id", user.UserID, "error", err.Error())
+			continue
+		}
+
+		if settings.DigestMode == constants.DigestModeWeekly && time.Since(user.OldestAt.Time) < digestWeek {
+			continue
+		}
+
+		if c.sendDigest(settings) {
+			sent++
+		}
+	}
+
+	return len(users), sent
+}
+
+// userSettings reads the settings of one user with a deadline of its own, so a long run of users can't starve
+// the ones at its end
+func (c *TVShowChecker) userSettings(userId int64) (database.UserSetting, error) {
+	ctxDb, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return c.app.Repository.Settings.GetUserSettings(ctxDb, userId)
+}
+
+// sendDigest queues one summary of the held updates of a user and marks them as digested in the same
+// transaction. Updates of kinds the user turned off are marked as skipped, updates of shows past the size limit
+// of a digest stay held for the next one.
+func (c *TVShowChecker) sendDigest(settings database.UserSetting) bool {
+	const op = "workers.sendDigest"
+	ctxDb, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	held, err := c.app.Repository.Notifications.GetHeldNotifications(ctxDb, settings.UserID)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Error fetching held notifications",
+			"user_id", settings.UserID, "error", err.Error())
+		return false
+	}
+
+	var ids []uuid.UUID
+	var skipped []uuid.UUID
+	var showIds []int64
+	showNames := make(map[int64]string)
+	showLines := make(map[int64][]string)
+	remaining := 0
+	for _, entry := range held {
+		if !notificationEnabled(settings, entry.Kind) {
+			skipped = append(skipped, entry.ID)
+			continue
+		}
+
+		// Updates without a show are listed on their own at the end
+		var showId int64
+		if entry.ShowApiID != nil {
+			showId = *entry.ShowApiID
+		}
+
+		if _, exists := showLines[showId]; !exists {
+			if len(showIds) == maxDigestShows {
+				remaining++
+				continue
+			}
+			showIds = append(showIds, showId)
+			showNames[showId] = digestShowName(showId, entry.ShowName)
+		}
+		showLines[showId] = append(showLines[showId], entry.Text)
+		ids = append(ids, entry.ID)
+	}
+
+	for _, id := range skipped {
+		if err = c.app.Repository.Notifications.MarkNotificationSkipped(ctxDb, id, "notification kind turned off"); err != nil {
+			c.app.Logger.WorkerError(op, "Failed to skip held notification",
+				"user_id", settings.UserID, "notification_id", id, "error", err.Error())
+		}
+	}
+
+	if len(ids) == 0 {
+		c.app.Logger.WorkerInfo(op, "Digest processed without updates to send",
+			"user_id", settings.UserID, "entries", len(held), "skipped", len(skipped))
+		return false
+	}
+
+	text, replyMarkup := formatDigest(settings.DigestMode, showIds, showNames, showLines, remaining)
+	if err = c.queueDigest(ctxDb, settings.UserID, ids, text, replyMarkup); err != nil {
+		c.app.Logger.WorkerError(op, "Failed to queue digest",
+			"user_id", settings.UserID, "error", err.Error())
+		return false
+	}
+
+	c.app.Logger.WorkerInfo(op, "Digest processed",
+		"user_id", settings.UserID, "entries", len(held), "shows", len(showIds),
+		"skipped", len(skipped), "shows_left_held", remaining)
+	return true
+}
+
+// queueDigest enqueues a digest and marks its held updates as digested, either both happen or neither
+func (c *TVShowChecker) queueDigest(ctx context.Context, userId int64, ids []uuid.UUID, text string, replyMarkup *telebot.ReplyMarkup) error {
+	params, err := notificationParams(userId, constants.NotificationKindDigest, 0, text, "", replyMarkup)
+	if err != nil {
+		return err
+	}
+
+	tx, err := c.app.Repository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Repos.Notifications.EnqueueNotification(ctx, params); err != nil {
+		return err
+	}
+	if err = tx.Repos.Notifications.MarkNotificationsDigested(ctx, ids); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// digestShowName falls back to a generic heading when the name of a show is unknown
+func digestShowName(showId int64, name string) string {
+	switch {
+	case name != "":
+		return name
+	case showId == 0:
+		return "Other updates"
+	default:
+		return fmt.Sprintf("Show #%d", showId)
+	}
+}
+
+// formatDigest renders the digest text with a button per show, remaining is the number of shows left for the
+// next digest
+func formatDigest(mode string, showIds []int64, showNames map[int64]string, showLines map[int64][]string, remaining int) (string, *telebot.ReplyMarkup) {
+	var text strings.Builder
+	if mode == constants.DigestModeWeekly {
+		text.WriteString("📬 *Your weekly digest*\n\n")
+	} else {
+		text.WriteString("📬 *Updates from the latest check*\n\n")
+	}
+
+	replyMarkup := &telebot.ReplyMarkup{}
+	var rows []telebot.Row
+	for _, showId := range showIds {
+		text.WriteString(fmt.Sprintf("📺 *%v*\n", utils.EscapeMarkdown(showNames[showId])))
+		for _, line := range showLines[showId] {
+			text.WriteString(fmt.Sprintf("└ %v\n", line))
+		}
+		text.WriteString("\n")
+
+		if showId == 0 {
+			continue
+		}
+		rows = append(rows, replyMarkup.Row(
+			replyMarkup.Data(fmt.Sprintf("📝 %v", showNames[showId]), fmt.Sprintf("tv|select_seasons|%v", showId)),
+		))
+	}
+	if remaining > 0 {
+		text.WriteString(fmt.Sprintf("...and %d more shows in the next digest\n", remaining))
+	}
+	replyMarkup.Inline(rows...)
+
+	return text.String(), replyMarkup
+}
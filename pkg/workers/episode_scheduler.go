@@ -0,0 +1,265 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/tv"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/telebot.v3"
+	"time"
+)
+
+// runCycle is one run of the schedule_episodes job: it refreshes stored air dates and then sends reminders
+// for episodes airing today
+func (s *EpisodeScheduler) runCycle(ctx context.Context) (JobResult, error) {
+	const op = "workers.runCycle"
+	start := time.Now()
+
+	refreshTaskID, err := s.createWorkerTask(TaskTypeRefreshSchedules, nil, 0)
+	if err != nil {
+		s.app.Logger.WorkerError(op, "Failed to create refresh task record",
+			"worker_id", s.workerId, "error", err.Error())
+	}
+
+	shows, episodes := s.refreshSchedules(ctx)
+	s.completeWorkerTask(refreshTaskID, nil, shows, episodes)
+	if ctx.Err() != nil {
+		return JobResult{Checked: shows}, ctx.Err()
+	}
+
+	remindTaskID, err := s.createWorkerTask(TaskTypeSendReminders, nil, 0)
+	if err != nil {
+		s.app.Logger.WorkerError(op, "Failed to create reminder task record",
+			"worker_id", s.workerId, "error", err.Error())
+	}
+
+	due, sent, err := s.sendReminders(ctx, start)
+	s.completeWorkerTask(remindTaskID, err, due, sent)
+
+	s.app.Logger.WorkerInfo(op, "Completed schedule cycle",
+		"worker_id", s.workerId,
+		"shows_checked", shows,
+		"episodes_scheduled", episodes,
+		"reminders_sent", sent)
+	return JobResult{Checked: shows, Updates: sent}, err
+}
+
+// refreshSchedules fetches upcoming episodes of every tracked show once and stores their air dates.
+// It returns the number of shows checked and the number of episodes stored.
+func (s *EpisodeScheduler) refreshSchedules(ctx context.Context) (int, int) {
+	const op = "workers.refreshSchedules"
+	// Every query gets a deadline of its own, one for the whole gathering would run out somewhere among many users
+	ctxDb, cancel := context.WithTimeout(ctx, 5*time.Second)
+	users, err := s.app.Repository.Users.GetUsers(ctxDb)
+	cancel()
+	if err != nil {
+		s.app.Logger.WorkerError(op, "Error fetching users", "error", err.Error())
+		return 0, 0
+	}
+
+	// Air dates are the same for everyone, so each show is fetched once with the key of any user tracking it
+	showOwners := make(map[int64]int64)
+	for _, user := range users {
+		ctxDb, cancel = context.WithTimeout(ctx, 5*time.Second)
+		shows, err := s.app.Repository.TVShows.GetUserTVShows(ctxDb, user.TgID)
+		cancel()
+		if err != nil {
+			s.app.Logger.WorkerError(op, "Error fetching shows for user",
+				"user_id", user.TgID, "error", err.Error())
+			continue
+		}
+
+		for _, show := range shows {
+			if _, exists := showOwners[show.ApiID]; !exists {
+				showOwners[show.ApiID] = user.TgID
+			}
+		}
+	}
+
+	s.app.Logger.WorkerInfo(op, "Refreshing schedules for tracked shows", "show_count", len(showOwners))
+
+	checked := 0
+	episodeCount := 0
+	for showId, userId := range showOwners {
+		if ctx.Err() != nil {
+			s.app.Logger.WorkerWarning(op, "Schedule refresh stopped before all shows were checked",
+				"checked_shows", checked, "show_count", len(showOwners), "error", ctx.Err().Error())
+			break
+		}
+		episodeCount += s.refreshShowSchedule(ctx, showId, userId)
+		checked++
+	}
+
+	return checked, episodeCount
+}
+
+// refreshShowSchedule stores the air dates of the season that holds the next episode of a show
+func (s *EpisodeScheduler) refreshShowSchedule(ctx context.Context, showId, userId int64) int {
+	const op = "workers.refreshShowSchedule"
+	details, err := s.apiClient.GetShowDetails(ctx, s.app, int(showId), userId)
+	if err != nil {
+		s.app.Logger.WorkerError(op, "Error fetching show details",
+			"show_id", showId, "error", err.Error())
+		return 0
+	}
+
+	if details.NextEpisodeToAir == nil {
+		s.app.Logger.WorkerDebug(op, "No upcoming episode announced for show",
+			"show_id", showId, "name", details.Name)
+		return 0
+	}
+
+	episodes := []tv.Episode{*details.NextEpisodeToAir}
+	season, err := s.apiClient.GetSeasonDetails(ctx, s.app, int(showId), int(details.NextEpisodeToAir.SeasonNumber), userId)
+	if err != nil {
+		s.app.Logger.WorkerWarning(op, "Error fetching season of next episode, storing next episode only",
+			"show_id", showId, "season", details.NextEpisodeToAir.SeasonNumber, "error", err.Error())
+	} else {
+		episodes = season.Episodes
+	}
+
+	stored := 0
+	for _, episode := range episodes {
+		airDate, err := time.Parse(constants.DateFormat, episode.AirDate)
+		if err != nil {
+			// Episodes without an announced date are picked up in a later cycle
+			continue
+		}
+
+		params := database.UpsertEpisodeScheduleParams{
+			ShowApiID:     showId,
+			ShowName:      details.Name,
+			SeasonNumber:  episode.SeasonNumber,
+			EpisodeNumber: episode.EpisodeNumber,
+			AirDate:       pgtype.Date{Time: airDate, Valid: true},
+		}
+		if episode.Name != "" {
+			params.EpisodeName = &episode.Name
+		}
+		if episode.Runtime > 0 {
+			params.Runtime = &episode.Runtime
+		}
+
+		ctxDb, cancel := persistContext(ctx)
+		err = s.app.Repository.Episodes.UpsertEpisodeSchedule(ctxDb, params)
+		cancel()
+		if err != nil {
+			s.app.Logger.WorkerError(op, "Failed to store episode schedule",
+				"show_id", showId, "season", episode.SeasonNumber,
+				"episode", episode.EpisodeNumber, "error", err.Error())
+			continue
+		}
+		stored++
+	}
+
+	s.app.Logger.WorkerDebug(op, "Stored episode schedule for show",
+		"show_id", showId, "name", details.Name, "episodes", stored)
+	return stored
+}
+
+// sendReminders queues a reminder for every user tracking a show whose episode airs on the given day.
+// It returns the number of due reminders and the number actually queued.
+func (s *EpisodeScheduler) sendReminders(ctx context.Context, day time.Time) (int, int, error) {
+	const op = "workers.sendReminders"
+	ctxDb, cancel := context.WithTimeout(ctx, 5*time.Second)
+	reminders, err := s.app.Repository.Episodes.GetDueEpisodeReminders(ctxDb, pgtype.Date{Time: day, Valid: true})
+	cancel()
+	if err != nil {
+		s.app.Logger.WorkerError(op, "Error fetching due reminders", "error", err.Error())
+		return 0, 0, err
+	}
+
+	s.app.Logger.WorkerInfo(op, "Found due episode reminders", "reminder_count", len(reminders))
+
+	sent := 0
+	for _, reminder := range reminders {
+		if ctx.Err() != nil {
+			s.app.Logger.WorkerWarning(op, "Reminders stopped before all were queued",
+				"sent", sent, "reminder_count", len(reminders), "error", ctx.Err().Error())
+			break
+		}
+		if s.notifyUser(ctx, reminder) {
+			sent++
+		}
+	}
+
+	return len(reminders), sent, nil
+}
+
+// notifyUser queues a reminder and records it as sent in one transaction, so a reminder is never queued twice
+// and never recorded without being queued
+func (s *EpisodeScheduler) notifyUser(ctx context.Context, reminder database.GetDueEpisodeRemindersRow) bool {
+	const op = "workers.notifyUser"
+	s.app.Logger.WorkerInfo(op, "Queueing episode reminder for user",
+		"user_id", reminder.UserID, "show_id", reminder.ShowApiID,
+		"season", reminder.SeasonNumber, "episode", reminder.EpisodeNumber)
+
+	episodeName := "TBA"
+	if reminder.EpisodeName != nil {
+		episodeName = *reminder.EpisodeName
+	}
+
+	text := fmt.Sprintf(
+		"New episode airs today\n\n"+
+			"📺 *Name*: %v\n\n"+
+			"🎞 *Episode*: S%02dE%02d - %v\n\n"+
+			"📅 *Air Date*: %v\n",
+		reminder.ShowName,
+		reminder.SeasonNumber,
+		reminder.EpisodeNumber,
+		episodeName,
+		reminder.AirDate.Time.Format(constants.DateFormat),
+	)
+
+	replyMarkup := &telebot.ReplyMarkup{}
+	watchedButton := replyMarkup.Data("✅ I watched it", fmt.Sprintf("tv|episode_watched|%v-%v-%v",
+		reminder.ShowApiID, reminder.SeasonNumber, reminder.EpisodeNumber))
+	replyMarkup.Inline(
+		replyMarkup.Row(watchedButton),
+	)
+
+	ctxDb, cancel := persistContext(ctx)
+	defer cancel()
+
+	err := s.queueReminder(ctxDb, reminder, text, replyMarkup)
+	if err != nil {
+		s.app.Logger.WorkerError(op, "Failed to queue episode reminder",
+			"user_id", reminder.UserID, "error", err.Error())
+		return false
+	}
+
+	s.app.Logger.WorkerInfo(op, "Episode reminder queued successfully",
+		"user_id", reminder.UserID, "show_id", reminder.ShowApiID)
+	return true
+}
+
+// queueReminder enqueues the reminder text and records the reminder as sent within one transaction
+func (s *EpisodeScheduler) queueReminder(ctx context.Context, reminder database.GetDueEpisodeRemindersRow, text string, replyMarkup *telebot.ReplyMarkup) error {
+	params, err := notificationParams(reminder.UserID, constants.NotificationKindEpisodeReminder, reminder.ShowApiID, text, "", replyMarkup)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.app.Repository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Repos.Notifications.EnqueueNotification(ctx, params); err != nil {
+		return err
+	}
+	err = tx.Repos.Episodes.CreateEpisodeReminder(ctx, database.CreateEpisodeReminderParams{
+		UserID:        reminder.UserID,
+		ShowApiID:     reminder.ShowApiID,
+		SeasonNumber:  reminder.SeasonNumber,
+		EpisodeNumber: reminder.EpisodeNumber,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
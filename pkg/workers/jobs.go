@@ -0,0 +1,95 @@
+package workers
+
+import (
+	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+	"time"
+)
+
+// RegisterJobs adds the periodic background jobs of the bot to the scheduler. Each job runs at the period
+// set in the config unless job_schedules holds a cron expression for it.
+func RegisterJobs(scheduler *Scheduler, app *app.App, apiClient *WorkerApiClient) error {
+	const op = "workers.RegisterJobs"
+	general := app.Cfg.General
+
+	checker := NewTVShowChecker(app, apiClient)
+	episodeScheduler := NewEpisodeScheduler(app, apiClient)
+	releaseChecker := NewMovieReleaseChecker(app, apiClient)
+	availabilityChecker := NewAvailabilityChecker(app, apiClient)
+
+	scheduler.mu.Lock()
+	scheduler.showChecker = checker
+	scheduler.mu.Unlock()
+
+	jobs := []Job{
+		{
+			Name:        JobCheckAllShows,
+			Schedule:    jobSchedule(app, JobCheckAllShows, Every(time.Duration(general.WorkerPeriod)*time.Hour)),
+			Timeout:     12 * time.Hour,
+			Concurrency: ConcurrencyForbid,
+			Run:         checker.runCheck,
+			worker:      &checker.workerBase,
+		},
+		{
+			Name:        JobScheduleEpisodes,
+			Schedule:    jobSchedule(app, JobScheduleEpisodes, Every(time.Duration(general.EpisodeReminderPeriod)*time.Hour)),
+			Timeout:     time.Hour,
+			Concurrency: ConcurrencyForbid,
+			Run:         episodeScheduler.runCycle,
+			worker:      &episodeScheduler.workerBase,
+		},
+		{
+			Name:        JobCheckAllMovies,
+			Schedule:    jobSchedule(app, JobCheckAllMovies, Every(time.Duration(general.MovieReleasePeriod)*time.Hour)),
+			Timeout:     time.Hour,
+			Concurrency: ConcurrencyForbid,
+			Run:         releaseChecker.runCheck,
+			worker:      &releaseChecker.workerBase,
+		},
+		{
+			Name:        JobCheckAvailability,
+			Schedule:    jobSchedule(app, JobCheckAvailability, Every(time.Duration(general.AvailabilityPeriod)*time.Hour)),
+			Timeout:     time.Hour,
+			Concurrency: ConcurrencyForbid,
+			Run:         availabilityChecker.runCheck,
+			worker:      &availabilityChecker.workerBase,
+		},
+	}
+
+	if general.TaskRetentionDays > 0 {
+		retention := time.Duration(general.TaskRetentionDays) * 24 * time.Hour
+		jobs = append(jobs, Job{
+			Name:        JobPruneWorkerTasks,
+			Schedule:    jobSchedule(app, JobPruneWorkerTasks, Every(24*time.Hour)),
+			Timeout:     time.Hour,
+			Concurrency: ConcurrencyForbid,
+			Run:         pruneWorkerTasks(app, retention),
+		})
+	}
+
+	for _, job := range jobs {
+		if err := scheduler.Register(job); err != nil {
+			app.Logger.WorkerError(op, "Failed to register job", "job", job.Name, "error", err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jobSchedule returns the configured cron schedule of a job, an invalid expression falls back to the default
+func jobSchedule(app *app.App, name string, fallback Schedule) Schedule {
+	const op = "workers.jobSchedule"
+	expr, ok := app.Cfg.General.JobSchedules[name]
+	if !ok || expr == "" {
+		return fallback
+	}
+
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		app.Logger.WorkerError(op, "Invalid job schedule, using the default period",
+			"job", name, "fallback", fallback.String(), "error", err.Error())
+		return fallback
+	}
+
+	return schedule
+}
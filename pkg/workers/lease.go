@@ -0,0 +1,141 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// leaseTTL is how long a dead leader blocks other instances before one of them takes over
+	leaseTTL           = 2 * time.Minute
+	leaseRenewInterval = 30 * time.Second
+)
+
+// instanceId identifies this bot process among replicas sharing the same database
+var instanceId = newInstanceId()
+
+func newInstanceId() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8])
+}
+
+// leadership is shared by copies of the base and follows whether this instance holds the lease of the worker type
+type leadership struct {
+	mu   sync.Mutex
+	held bool
+	// changed is closed and replaced every time the lease is gained or lost
+	changed chan struct{}
+}
+
+func newLeadership() *leadership {
+	return &leadership{changed: make(chan struct{})}
+}
+
+// set records whether the lease is held and returns the previous value, waking everyone waiting for a change
+func (l *leadership) set(held bool) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	was := l.held
+	if was != held {
+		l.held = held
+		close(l.changed)
+		l.changed = make(chan struct{})
+	}
+	return was
+}
+
+// state returns whether the lease is held together with a channel that is closed on the next change
+func (l *leadership) state() (bool, <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.held, l.changed
+}
+
+// startLease competes for the lease of the worker type and keeps renewing it in the background until the
+// context is cancelled. The first attempt is made right away so a leader can start its first cycle immediately.
+// The lease is not released here, the owner calls releaseLease once its runs have stopped.
+func (c *workerBase) startLease(ctx context.Context) {
+	const op = "workers.startLease"
+	c.app.Logger.WorkerInfo(op, "Competing for worker lease",
+		"worker_id", c.workerId, "type", c.workerType, "instance_id", instanceId)
+
+	c.renewLease()
+
+	go func() {
+		ticker := time.NewTicker(leaseRenewInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.renewLease()
+			}
+		}
+	}()
+}
+
+// renewLease takes the lease when it is free or expired and extends it while this instance holds it
+func (c *workerBase) renewLease() {
+	const op = "workers.renewLease"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	acquired, err := c.app.Repository.Worker.AcquireWorkerLease(ctx, c.workerType, instanceId, time.Now().Add(leaseTTL))
+	if err != nil {
+		// Without a confirmed lease another instance may take over, so this one stops acting as the leader
+		c.app.Logger.WorkerError(op, "Failed to renew worker lease",
+			"worker_id", c.workerId, "type", c.workerType, "error", err.Error())
+		acquired = false
+	}
+
+	if acquired && !c.isLeader() {
+		// The state has to be taken over before the scheduler sees this instance as the leader
+		c.takeOver()
+	}
+
+	wasLeader := c.leader.set(acquired)
+	switch {
+	case acquired && !wasLeader:
+		c.app.Logger.WorkerInfo(op, "Acquired worker lease, this instance is now the leader",
+			"worker_id", c.workerId, "type", c.workerType, "instance_id", instanceId)
+	case !acquired && wasLeader:
+		c.app.Logger.WorkerWarning(op, "Lost worker lease, another instance takes over",
+			"worker_id", c.workerId, "type", c.workerType, "instance_id", instanceId)
+	}
+}
+
+// releaseLease hands the lease over on shutdown so a standby instance does not have to wait for it to expire.
+// It is called after the last run stopped, so the next leader does not abort tasks this instance still finishes.
+func (c *workerBase) releaseLease() {
+	const op = "workers.releaseLease"
+	if !c.leader.set(false) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := c.app.Repository.Worker.ReleaseWorkerLease(ctx, c.workerType, instanceId); err != nil {
+		c.app.Logger.WorkerError(op, "Failed to release worker lease",
+			"worker_id", c.workerId, "type", c.workerType, "error", err.Error())
+		return
+	}
+
+	c.app.Logger.WorkerInfo(op, "Worker lease released", "worker_id", c.workerId, "type", c.workerType)
+}
+
+// isLeader reports whether this instance currently holds the lease of its worker type
+func (c *workerBase) isLeader() bool {
+	held, _ := c.leader.state()
+	return held
+}
@@ -0,0 +1,228 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/movie"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/telebot.v3"
+	"time"
+)
+
+func (c *WorkerApiClient) GetMovieDetails(ctx context.Context, app *app.App, apiId int, userId int64) (*movie.Movie, error) {
+	const op = "workers.GetMovieDetails"
+	app.Logger.WorkerDebug(op, "Attempting to fetch details for movie",
+		"movie_id", apiId, "user_id", userId)
+
+	err := c.limiter.Wait(ctx)
+	if err != nil {
+		app.Logger.WorkerError(op, "Rate limit wait error",
+			"movie_id", apiId, "error", err.Error())
+		return nil, fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	start := time.Now()
+	movieData, err := movie.GetMovie(ctx, app, apiId, userId)
+	duration := time.Since(start)
+
+	if err != nil {
+		app.Logger.WorkerError(op, "API request failed",
+			"movie_id", apiId, "duration_ms", duration.Milliseconds(), "error", err.Error())
+		return nil, fmt.Errorf("failed to get movie details: %w", err)
+	}
+
+	app.Logger.WorkerInfo(op, "Successfully fetched movie details",
+		"movie_id", apiId, "title", movieData.Title, "duration_ms", duration.Milliseconds())
+	return movieData, nil
+}
+
+// runCheck is one run of the check_all_movies job
+func (c *MovieReleaseChecker) runCheck(ctx context.Context) (JobResult, error) {
+	movies, updates, err := c.checkAllMovies(ctx)
+	return JobResult{Checked: movies, Updates: updates}, err
+}
+
+// checkAllMovies fetches every watchlisted movie once and compares it with each user's stored release data.
+// It returns the number of movies checked and the number of notifications queued.
+func (c *MovieReleaseChecker) checkAllMovies(ctx context.Context) (int, int, error) {
+	const op = "workers.checkAllMovies"
+	ctxDb, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	entries, err := c.app.Repository.Watchlists.GetWatchlistsByType(ctxDb, constants.MovieType)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Error fetching watchlisted movies", "error", err.Error())
+		return 0, 0, err
+	}
+
+	// Entries come ordered by movie, so each movie is fetched once with the key of one of its watchers
+	var movieIds []int64
+	byMovie := make(map[int64][]database.GetWatchlistsByTypeRow)
+	for _, entry := range entries {
+		if _, exists := byMovie[entry.ShowApiID]; !exists {
+			movieIds = append(movieIds, entry.ShowApiID)
+		}
+		byMovie[entry.ShowApiID] = append(byMovie[entry.ShowApiID], entry)
+	}
+
+	c.app.Logger.WorkerInfo(op, "Found watchlisted movies to check",
+		"movie_count", len(movieIds), "entry_count", len(entries))
+
+	updates := 0
+	for i, movieId := range movieIds {
+		if ctx.Err() != nil {
+			return i, updates, ctx.Err()
+		}
+
+		watchers := byMovie[movieId]
+		details, err := c.fetchMovieDetails(ctx, movieId, watchers)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range watchers {
+			if c.processEntry(ctx, entry, details) {
+				updates++
+			}
+		}
+	}
+
+	return len(movieIds), updates, nil
+}
+
+// fetchMovieDetails fetches a movie with the key of one of its watchers. An invalid key of one user should not
+// hide a release from everyone else, so a few other watchers' keys are tried before giving up.
+func (c *MovieReleaseChecker) fetchMovieDetails(ctx context.Context, movieId int64, watchers []database.GetWatchlistsByTypeRow) (*movie.Movie, error) {
+	const op = "workers.fetchMovieDetails"
+	var lastErr error
+	attempts := 0
+	for _, watcher := range watchers {
+		if attempts == maxKeyAttempts {
+			break
+		}
+
+		attempts++
+		details, err := c.apiClient.GetMovieDetails(ctx, c.app, int(movieId), watcher.UserID)
+		if err == nil {
+			return details, nil
+		}
+
+		lastErr = err
+		// Only a rejected key is worth retrying with another watcher's key
+		if !errors.Is(err, tmdb.ErrUnauthorized) {
+			break
+		}
+
+		c.app.Logger.WorkerWarning(op, "Key rejected while fetching movie details, trying another watcher's key",
+			"movie_id", movieId, "user_id", watcher.UserID, "error", err.Error())
+	}
+
+	c.app.Logger.WorkerError(op, "Error fetching movie details",
+		"movie_id", movieId, "attempts", attempts, "error", lastErr.Error())
+	return nil, lastErr
+}
+
+// processEntry stores fresh release data for one watchlist entry and notifies its owner about a release
+// or a moved release date. The first check of an entry only records a baseline.
+func (c *MovieReleaseChecker) processEntry(ctx context.Context, entry database.GetWatchlistsByTypeRow, details *movie.Movie) bool {
+	const op = "workers.processEntry"
+
+	var releaseDate pgtype.Date
+	if parsed, err := time.Parse(constants.DateFormat, details.ReleaseDate); err == nil {
+		releaseDate = pgtype.Date{Time: parsed, Valid: true}
+	}
+
+	dateChanged := releaseDate.Valid != entry.ReleaseDate.Valid ||
+		(releaseDate.Valid && !releaseDate.Time.Equal(entry.ReleaseDate.Time))
+	statusChanged := entry.ReleaseStatus == nil || *entry.ReleaseStatus != details.Status
+	// Entries added before runtimes were stored get theirs here, the runtime sort needs it
+	runtime := utils.WatchlistRuntime(details.Runtime)
+	runtimeChanged := !sameRuntime(entry.Runtime, runtime)
+	if !dateChanged && !statusChanged && !runtimeChanged {
+		return false
+	}
+
+	ctxDb, cancel := persistContext(ctx)
+	defer cancel()
+
+	status := details.Status
+	err := c.app.Repository.Watchlists.UpdateWatchlistRelease(ctxDb, database.UpdateWatchlistReleaseParams{
+		ID:            entry.ID,
+		ReleaseDate:   releaseDate,
+		ReleaseStatus: &status,
+		Runtime:       runtime,
+	})
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to store release data",
+			"movie_id", entry.ShowApiID, "user_id", entry.UserID, "error", err.Error())
+		return false
+	}
+
+	if entry.ReleaseStatus == nil {
+		c.app.Logger.WorkerDebug(op, "Stored initial release data",
+			"movie_id", entry.ShowApiID, "user_id", entry.UserID, "status", details.Status)
+		return false
+	}
+
+	var headline string
+	switch {
+	case details.Status == constants.MovieStatusReleased && *entry.ReleaseStatus != constants.MovieStatusReleased:
+		headline = "🎉 A movie from your watchlist is out now"
+	case details.Status != constants.MovieStatusReleased && dateChanged && entry.ReleaseDate.Valid && releaseDate.Valid:
+		headline = fmt.Sprintf("📅 Release date moved from %v",
+			entry.ReleaseDate.Time.Format(constants.DateFormat))
+	default:
+		c.app.Logger.WorkerDebug(op, "Release data changed without a notable event",
+			"movie_id", entry.ShowApiID, "user_id", entry.UserID,
+			"old_status", *entry.ReleaseStatus, "new_status", details.Status)
+		return false
+	}
+
+	c.app.Logger.WorkerInfo(op, "Release update detected for movie",
+		"movie_id", entry.ShowApiID, "user_id", entry.UserID, "title", details.Title,
+		"old_status", *entry.ReleaseStatus, "new_status", details.Status)
+	return c.notifyUser(entry.UserID, headline, details)
+}
+
+func (c *MovieReleaseChecker) notifyUser(userId int64, headline string, details *movie.Movie) bool {
+	const op = "workers.notifyUser"
+	c.app.Logger.WorkerInfo(op, "Queueing release notification for user",
+		"user_id", userId, "movie_id", details.ID, "title", details.Title)
+
+	releaseDate := details.ReleaseDate
+	if releaseDate == "" {
+		releaseDate = "TBA"
+	}
+
+	caption := fmt.Sprintf(
+		"%v\n\n"+
+			"🎬 *Title*: %v\n\n"+
+			"📜 *Status*: %v\n\n"+
+			"📅 *Release Date*: %v\n",
+		headline,
+		details.Title,
+		details.Status,
+		releaseDate,
+	)
+
+	replyMarkup := &telebot.ReplyMarkup{}
+	watchedButton := replyMarkup.Data("👀 Watched", fmt.Sprintf("movie|watched|%v", details.ID))
+	replyMarkup.Inline(
+		replyMarkup.Row(watchedButton),
+	)
+
+	err := c.enqueueNotification(userId, constants.NotificationKindMovieRelease, details.ID, caption, details.PosterPath, replyMarkup)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to queue release notification", "user_id", userId, "error", err.Error())
+		return false
+	}
+
+	c.app.Logger.WorkerInfo(op, "Release notification queued successfully", "user_id", userId, "movie_id", details.ID)
+	return true
+}
@@ -0,0 +1,388 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/image"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"gopkg.in/telebot.v3"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	dispatchBatchSize = 50
+	retryBaseDelay    = 30 * time.Second
+	retryMaxDelay     = time.Hour
+)
+
+// enqueueNotification stores a message in the notifications outbox, the dispatcher takes care of delivery
+func (c *workerBase) enqueueNotification(userId int64, kind string, showId int64, text, imagePath string, markup *telebot.ReplyMarkup) error {
+	const op = "workers.enqueueNotification"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	params, err := notificationParams(userId, kind, showId, text, imagePath, markup)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to encode reply markup",
+			"user_id", userId, "kind", kind, "error", err.Error())
+		return err
+	}
+
+	id, err := c.app.Repository.Notifications.EnqueueNotification(ctx, params)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to enqueue notification",
+			"user_id", userId, "kind", kind, "error", err.Error())
+		return err
+	}
+
+	c.app.Logger.WorkerDebug(op, "Notification enqueued",
+		"notification_id", id, "user_id", userId, "kind", kind, "show_id", showId)
+	return nil
+}
+
+// notificationParams builds an outbox row, callers that enqueue within a transaction of their own use it directly
+func notificationParams(userId int64, kind string, showId int64, text, imagePath string, markup *telebot.ReplyMarkup) (database.EnqueueNotificationParams, error) {
+	params := database.EnqueueNotificationParams{
+		UserID: userId,
+		Kind:   kind,
+		Text:   text,
+	}
+	if showId > 0 {
+		params.ShowApiID = &showId
+	}
+	if imagePath != "" {
+		params.ImagePath = &imagePath
+	}
+	if markup != nil && len(markup.InlineKeyboard) > 0 {
+		keyboard, err := json.Marshal(markup.InlineKeyboard)
+		if err != nil {
+			return params, err
+		}
+		params.ReplyMarkup = keyboard
+	}
+
+	return params, nil
+}
+
+func (d *NotificationDispatcher) StartDispatching(ctx context.Context, pollInterval int) {
+	const op = "workers.StartDispatching"
+	d.app.Logger.WorkerInfo(op, "Starting notification dispatcher",
+		"worker_id", d.workerId, "poll_interval_seconds", pollInterval)
+
+	d.startLease(ctx)
+
+	ticker := time.NewTicker(time.Duration(pollInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.app.Logger.WorkerInfo(op, "Context cancelled, stopping notification dispatcher",
+				"worker_id", d.workerId)
+			if d.isLeader() {
+				d.updateWorkerStatus(StatusIdle, nil)
+			}
+			d.releaseLease()
+			return
+		case <-ticker.C:
+			d.dispatchDue(ctx, time.Duration(pollInterval)*time.Second)
+		}
+	}
+}
+
+// dispatchDue sends one batch of due notifications, the next batch follows after pollInterval
+func (d *NotificationDispatcher) dispatchDue(ctx context.Context, pollInterval time.Duration) {
+	const op = "workers.dispatchDue"
+	// Two dispatchers reading the same outbox would send every message twice, a paused one leaves it alone
+	if !d.isLeader() || d.isPaused() {
+		return
+	}
+
+	ctxDb, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	due, err := d.app.Repository.Notifications.GetDueNotifications(ctxDb, dispatchBatchSize)
+	if err != nil {
+		d.app.Logger.WorkerError(op, "Error fetching due notifications", "error", err.Error())
+		return
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	d.app.Logger.WorkerInfo(op, "Dispatching notifications", "worker_id", d.workerId, "count", len(due))
+	start := time.Now()
+	d.updateWorkerStatus(StatusRunning, nil)
+
+	taskID, err := d.createWorkerTask(TaskTypeDispatchNotifications, nil, 0)
+	if err != nil {
+		d.app.Logger.WorkerError(op, "Failed to create task record",
+			"worker_id", d.workerId, "error", err.Error())
+	}
+
+	sent := 0
+	settingsByUser := make(map[int64]database.UserSetting)
+	for i, notification := range due {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if !d.allowedNow(notification, settingsByUser) {
+			continue
+		}
+
+		err = d.deliver(notification)
+		if err == nil {
+			sent++
+			d.markSent(notification)
+			continue
+		}
+
+		var floodErr telebot.FloodError
+		if errors.As(err, &floodErr) {
+			// Telegram asked us to slow down, so everything left in the batch waits as well
+			retryAt := time.Now().Add(time.Duration(floodErr.RetryAfter) * time.Second)
+			d.app.Logger.WorkerWarning(op, "Rate limited by Telegram, deferring remaining notifications",
+				"retry_after_seconds", floodErr.RetryAfter, "remaining", len(due)-i)
+			for _, rest := range due[i:] {
+				d.deferUntil(rest, retryAt)
+			}
+			break
+		}
+
+		d.markFailedAttempt(notification, err)
+	}
+
+	d.completeWorkerTask(taskID, nil, len(due), sent)
+	d.updateWorkerCheck(start, start.Add(pollInterval), len(due), sent)
+
+	d.app.Logger.WorkerInfo(op, "Dispatch completed",
+		"worker_id", d.workerId, "duration_ms", time.Since(start).Milliseconds(),
+		"due", len(due), "sent", sent)
+}
+
+// allowedNow applies the user's preferences, skipping disabled kinds and deferring messages during quiet hours
+func (d *NotificationDispatcher) allowedNow(notification database.GetDueNotificationsRow, settingsByUser map[int64]database.UserSetting) bool {
+	const op = "workers.allowedNow"
+	settings, ok := settingsByUser[notification.UserID]
+	if !ok {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var err error
+		settings, err = d.app.Repository.Settings.GetUserSettings(ctx, notification.UserID)
+		if err != nil {
+			// Delivering with default preferences beats holding the message back
+			d.app.Logger.WorkerError(op, "Failed to get user settings",
+				"user_id", notification.UserID, "error", err.Error())
+			return true
+		}
+		settingsByUser[notification.UserID] = settings
+	}
+
+	if !notificationEnabled(settings, notification.Kind) {
+		d.app.Logger.WorkerDebug(op, "Notification kind disabled by user",
+			"notification_id", notification.ID, "user_id", notification.UserID, "kind", notification.Kind)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := d.app.Repository.Notifications.MarkNotificationSkipped(ctx, notification.ID, "disabled in user settings"); err != nil {
+			d.app.Logger.WorkerError(op, "Failed to mark notification as skipped",
+				"notification_id", notification.ID, "error", err.Error())
+		}
+		return false
+	}
+
+	quietEnd := utils.QuietHoursEnd(time.Now(), settings.Timezone, settings.QuietHoursStart, settings.QuietHoursEnd)
+	if !quietEnd.IsZero() {
+		d.app.Logger.WorkerDebug(op, "Quiet hours active, deferring notification",
+			"notification_id", notification.ID, "user_id", notification.UserID, "until", quietEnd)
+		d.deferUntil(notification, quietEnd)
+		return false
+	}
+
+	return true
+}
+
+// notificationEnabled reports whether the user wants notifications of the given kind
+func notificationEnabled(settings database.UserSetting, kind string) bool {
+	switch kind {
+	case constants.NotificationKindNewSeason, constants.NotificationKindWatchlistShow:
+		return settings.NotifyNewSeasons
+	case constants.NotificationKindStatusChange:
+		return settings.NotifyStatusChanges
+	case constants.NotificationKindEpisodeReminder:
+		return settings.NotifyEpisodeReminders
+	case constants.NotificationKindMovieRelease:
+		return settings.NotifyReleases
+	case constants.NotificationKindDigest:
+		return settings.NotifyDigest
+	case constants.NotificationKindAvailability:
+		return settings.NotifyAvailability
+	default:
+		return true
+	}
+}
+
+// deliver sends a single notification, falling back to a text message when the poster is unavailable. A text
+// too long for a caption follows the poster as a message of its own, the poster is recorded as sent so a retry
+// of the text does not send it again.
+func (d *NotificationDispatcher) deliver(notification database.GetDueNotificationsRow) error {
+	const op = "workers.deliver"
+
+	replyMarkup := &telebot.ReplyMarkup{}
+	if len(notification.ReplyMarkup) > 0 {
+		if err := json.Unmarshal(notification.ReplyMarkup, &replyMarkup.InlineKeyboard); err != nil {
+			return fmt.Errorf("invalid reply markup: %w", err)
+		}
+	}
+
+	var poster []byte
+	if notification.ImagePath != nil && *notification.ImagePath != "" {
+		imgBuffer, err := image.GetImage(d.app, *notification.ImagePath)
+		if err != nil {
+			d.app.Logger.WorkerWarning(op, "Poster unavailable, sending text only",
+				"notification_id", notification.ID, "poster_path", *notification.ImagePath, "error", err.Error())
+		} else {
+			poster = imgBuffer.Bytes()
+		}
+	}
+
+	to := &telebot.User{ID: notification.UserID}
+	text := func() interface{} { return notification.Text }
+	if poster == nil {
+		return d.send(notification, to, text, replyMarkup)
+	}
+
+	if utils.CaptionLength(notification.Text) <= utils.MaxCaptionLength {
+		return d.send(notification, to, func() interface{} { return posterPhoto(poster, notification.Text) }, replyMarkup)
+	}
+
+	if notification.PosterSent {
+		d.app.Logger.WorkerDebug(op, "Poster sent by an earlier attempt, sending the text only",
+			"notification_id", notification.ID)
+		return d.send(notification, to, text, replyMarkup)
+	}
+
+	d.app.Logger.WorkerDebug(op, "Text too long for a caption, sending it after the poster",
+		"notification_id", notification.ID, "length", utils.CaptionLength(notification.Text))
+	if _, err := d.bot.Send(to, posterPhoto(poster, "")); err != nil {
+		return err
+	}
+	d.markPosterSent(notification)
+	return d.send(notification, to, text, replyMarkup)
+}
+
+// send delivers a message with Markdown, and once more as plain text when Telegram can't parse it. The message is
+// built anew for the second try since a photo reader can only be read once.
+func (d *NotificationDispatcher) send(notification database.GetDueNotificationsRow, to telebot.Recipient, message func() interface{}, replyMarkup *telebot.ReplyMarkup) error {
+	const op = "workers.send"
+
+	_, err := d.bot.Send(to, message(), replyMarkup, telebot.ModeMarkdown)
+	if err == nil || !strings.Contains(err.Error(), "can't parse entities") {
+		return err
+	}
+
+	d.app.Logger.WorkerWarning(op, "Markdown rejected by Telegram, sending as plain text",
+		"notification_id", notification.ID, "error", err.Error())
+	_, err = d.bot.Send(to, message(), replyMarkup)
+	return err
+}
+
+func posterPhoto(poster []byte, caption string) *telebot.Photo {
+	return &telebot.Photo{
+		File:    telebot.File{FileReader: bytes.NewReader(poster)},
+		Caption: caption,
+	}
+}
+
+// markPosterSent records the poster of a split message, a failure only risks the poster being sent twice
+func (d *NotificationDispatcher) markPosterSent(notification database.GetDueNotificationsRow) {
+	const op = "workers.markPosterSent"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := d.app.Repository.Notifications.MarkNotificationPosterSent(ctx, notification.ID); err != nil {
+		d.app.Logger.WorkerError(op, "Failed to record the poster as sent",
+			"notification_id", notification.ID, "error", err.Error())
+	}
+}
+
+func (d *NotificationDispatcher) markSent(notification database.GetDueNotificationsRow) {
+	const op = "workers.markSent"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := d.app.Repository.Notifications.MarkNotificationSent(ctx, notification.ID); err != nil {
+		d.app.Logger.WorkerError(op, "Failed to mark notification as sent",
+			"notification_id", notification.ID, "error", err.Error())
+		return
+	}
+
+	d.app.Logger.WorkerInfo(op, "Notification sent successfully",
+		"notification_id", notification.ID, "user_id", notification.UserID, "kind", notification.Kind)
+}
+
+// markFailedAttempt schedules a retry with exponential backoff, or gives up when retrying cannot help
+func (d *NotificationDispatcher) markFailedAttempt(notification database.GetDueNotificationsRow, sendErr error) {
+	const op = "workers.markFailedAttempt"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	attempt := int(notification.Attempts) + 1
+	if isPermanentSendError(sendErr) || attempt >= d.maxAttempts {
+		d.app.Logger.WorkerError(op, "Notification delivery failed permanently",
+			"notification_id", notification.ID, "user_id", notification.UserID,
+			"attempts", attempt, "error", sendErr.Error())
+		if err := d.app.Repository.Notifications.MarkNotificationFailed(ctx, notification.ID, sendErr.Error()); err != nil {
+			d.app.Logger.WorkerError(op, "Failed to mark notification as failed",
+				"notification_id", notification.ID, "error", err.Error())
+		}
+		return
+	}
+
+	delay := retryBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+
+	d.app.Logger.WorkerWarning(op, "Notification delivery failed, scheduling retry",
+		"notification_id", notification.ID, "user_id", notification.UserID,
+		"attempts", attempt, "retry_in_seconds", delay.Seconds(), "error", sendErr.Error())
+	err := d.app.Repository.Notifications.MarkNotificationRetry(ctx, notification.ID, time.Now().Add(delay), sendErr.Error())
+	if err != nil {
+		d.app.Logger.WorkerError(op, "Failed to schedule notification retry",
+			"notification_id", notification.ID, "error", err.Error())
+	}
+}
+
+func (d *NotificationDispatcher) deferUntil(notification database.GetDueNotificationsRow, retryAt time.Time) {
+	const op = "workers.deferUntil"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := d.app.Repository.Notifications.DeferNotification(ctx, notification.ID, retryAt); err != nil {
+		d.app.Logger.WorkerError(op, "Failed to defer notification",
+			"notification_id", notification.ID, "error", err.Error())
+	}
+}
+
+// isPermanentSendError reports errors that will not go away by retrying. Telegram rejects malformed requests with
+// 400 Bad Request, sending the same message again gets the same answer.
+func isPermanentSendError(err error) bool {
+	var tgErr *telebot.Error
+	if errors.As(err, &tgErr) && tgErr.Code == http.StatusBadRequest {
+		return true
+	}
+	return errors.Is(err, telebot.ErrBlockedByUser) ||
+		errors.Is(err, telebot.ErrUserIsDeactivated) ||
+		errors.Is(err, telebot.ErrNotStartedByUser) ||
+		strings.Contains(err.Error(), "Bad Request")
+}
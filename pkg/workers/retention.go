@@ -0,0 +1,43 @@
+package workers
+
+import (
+	"context"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+	"time"
+)
+
+// pruneBatchSize limits how many tasks one statement rolls up, keeping locks on worker_tasks short
+const pruneBatchSize = 5000
+
+// pruneWorkerTasks returns the prune_worker_tasks job. It rolls finished tasks older than the retention period up
+// into daily stats and deletes them. Cycle level and dispatcher tasks go as well, the dispatcher alone records a
+// task on every poll that finds due notifications.
+func pruneWorkerTasks(app *app.App, retention time.Duration) JobFunc {
+	return func(ctx context.Context) (JobResult, error) {
+		const op = "workers.pruneWorkerTasks"
+		cutoff := time.Now().Add(-retention)
+		app.Logger.WorkerInfo(op, "Rolling up old worker tasks", "cutoff", cutoff)
+
+		result := JobResult{}
+		for ctx.Err() == nil {
+			dbCtx, cancel := context.WithTimeout(ctx, time.Minute)
+			batch, err := app.Repository.Worker.RollupWorkerTasks(dbCtx, cutoff, pruneBatchSize)
+			cancel()
+			if err != nil {
+				app.Logger.WorkerError(op, "Failed to roll up worker tasks",
+					"pruned_so_far", result.Checked, "error", err.Error())
+				return result, err
+			}
+
+			result.Checked += int(batch.PrunedTasks)
+			result.Updates += int(batch.StatRows)
+			if batch.PrunedTasks < pruneBatchSize {
+				break
+			}
+		}
+
+		app.Logger.WorkerInfo(op, "Worker tasks rolled up",
+			"pruned_tasks", result.Checked, "stat_rows", result.Updates)
+		return result, nil
+	}
+}
@@ -0,0 +1,216 @@
+package workers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when a job runs next
+type Schedule interface {
+	// Next returns the first run time strictly after the given time
+	Next(after time.Time) time.Time
+	String() string
+}
+
+type intervalSchedule struct {
+	every time.Duration
+}
+
+// Every runs a job at a fixed interval counted from its previous run
+func Every(every time.Duration) Schedule {
+	return intervalSchedule{every: every}
+}
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(s.every)
+}
+
+func (s intervalSchedule) String() string {
+	return "every " + s.every.String()
+}
+
+// cronSchedule is a standard five field cron expression: minute, hour, day of month, month and day of week
+type cronSchedule struct {
+	expr     string
+	minute   uint64
+	hour     uint64
+	dom      uint64
+	month    uint64
+	dow      uint64
+	anyDom   bool
+	anyDow   bool
+	location *time.Location
+}
+
+// cronDescriptors are the shorthand expressions understood next to the five field form
+var cronDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// ParseCron parses a five field cron expression. Fields accept "*", single values, ranges, lists and
+// steps such as "*/15" or "1-5". Day of week runs from 0 (Sunday) to 6, 7 is accepted for Sunday as well.
+func ParseCron(expr string) (Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[spec]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", expr, len(fields))
+	}
+
+	schedule := cronSchedule{expr: expr, location: time.Local}
+	bounds := []struct {
+		target   *uint64
+		min, max int
+	}{
+		{&schedule.minute, 0, 59},
+		{&schedule.hour, 0, 23},
+		{&schedule.dom, 1, 31},
+		{&schedule.month, 1, 12},
+		{&schedule.dow, 0, 7},
+	}
+
+	for i, field := range fields {
+		bits, err := parseCronField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+		*bounds[i].target = bits
+	}
+
+	// Sunday may be written as 0 or 7
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	// A day field counts as unrestricted when it starts with "*", like "*/2" does, or allows every day anyway
+	schedule.anyDom = strings.HasPrefix(fields[2], "*") || schedule.dom == cronBits(1, 31)
+	schedule.anyDow = strings.HasPrefix(fields[4], "*") || schedule.dow&cronBits(0, 6) == cronBits(0, 6)
+
+	// Expressions like "0 0 31 2 *" parse fine but never fire
+	if schedule.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never matches", expr)
+	}
+
+	return schedule, nil
+}
+
+// parseCronField turns one cron field into a bit set of the allowed values
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			parsed, err := strconv.Atoi(part[idx+1:])
+			if err != nil || parsed <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:idx], parsed
+		}
+
+		start, end := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid range start in %q", part)
+			}
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, fmt.Errorf("invalid range end in %q", part)
+			}
+		default:
+			value, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			start, end = value, value
+			if step > 1 {
+				end = max
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return 0, fmt.Errorf("value out of range in %q, allowed %d-%d", part, min, max)
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+// cronBits is the bit set of every value from min to max
+func cronBits(min, max int) uint64 {
+	var bits uint64
+	for value := min; value <= max; value++ {
+		bits |= 1 << uint(value)
+	}
+	return bits
+}
+
+// Next walks the wall clock of the schedule's location. A time skipped when daylight saving time starts runs
+// as soon as the clock jumped past it, and the hour repeated when it ends runs only once.
+func (s cronSchedule) Next(after time.Time) time.Time {
+	after = after.In(s.location)
+
+	// Every valid expression matches at least once within a few years, the limit only guards against loops
+	for i := 0; i <= 5*366; i++ {
+		// Dates are counted in UTC, a local midnight may not exist on the day the clock changes
+		date := time.Date(after.Year(), after.Month(), after.Day()+i, 0, 0, 0, 0, time.UTC)
+		if s.month&(1<<uint(date.Month())) == 0 || !s.dayMatches(date) {
+			continue
+		}
+
+		for hour := 0; hour < 24; hour++ {
+			if s.hour&(1<<uint(hour)) == 0 {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if s.minute&(1<<uint(minute)) == 0 {
+					continue
+				}
+				t := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, s.location)
+				if t.Hour() != hour || t.Minute() != minute {
+					// The time falls into the gap when daylight saving time starts, the run moves to the jump
+					start, end := t.ZoneBounds()
+					if t.Hour()*60+t.Minute() > hour*60+minute {
+						t = start
+					} else {
+						t = end
+					}
+				}
+				if t.After(after) {
+					return t
+				}
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// dayMatches follows the usual cron rule: when both day fields are restricted, matching either one is enough,
+// otherwise the day has to match both
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.anyDom || s.anyDow {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func (s cronSchedule) String() string {
+	return "cron " + s.expr
+}
@@ -0,0 +1,353 @@
+package workers
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load location %s: %v", name, err)
+	}
+	return location
+}
+
+func mustCron(t *testing.T, expr string, location *time.Location) cronSchedule {
+	t.Helper()
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		t.Fatalf("ParseCron(%q): %v", expr, err)
+	}
+	cron := schedule.(cronSchedule)
+	cron.location = location
+	return cron
+}
+
+func TestParseCronRejectsInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1-x * * * *",
+		"@yearly",
+		"0 0 31 2 *",
+	}
+
+	for _, expr := range tests {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	utc := time.UTC
+	// 2025-01-01 is a Wednesday
+	start := time.Date(2025, 1, 1, 10, 7, 30, 0, utc)
+
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "every minute skips the current one",
+			expr:  "* * * * *",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 1, 10, 8, 0, 0, utc),
+				time.Date(2025, 1, 1, 10, 9, 0, 0, utc),
+			},
+		},
+		{
+			name:  "minute step",
+			expr:  "*/15 * * * *",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 1, 10, 15, 0, 0, utc),
+				time.Date(2025, 1, 1, 10, 30, 0, 0, utc),
+				time.Date(2025, 1, 1, 10, 45, 0, 0, utc),
+				time.Date(2025, 1, 1, 11, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "step from a value",
+			expr:  "5/20 * * * *",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 1, 10, 25, 0, 0, utc),
+				time.Date(2025, 1, 1, 10, 45, 0, 0, utc),
+				time.Date(2025, 1, 1, 11, 5, 0, 0, utc),
+			},
+		},
+		{
+			name:  "hour range",
+			expr:  "0 9-11 * * *",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 1, 11, 0, 0, 0, utc),
+				time.Date(2025, 1, 2, 9, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "range with step",
+			expr:  "0 0-12/6 * * *",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 1, 12, 0, 0, 0, utc),
+				time.Date(2025, 1, 2, 0, 0, 0, 0, utc),
+				time.Date(2025, 1, 2, 6, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "list",
+			expr:  "30 8,20 * * *",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 1, 20, 30, 0, 0, utc),
+				time.Date(2025, 1, 2, 8, 30, 0, 0, utc),
+			},
+		},
+		{
+			name:  "month",
+			expr:  "0 0 1 3,6 *",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 3, 1, 0, 0, 0, 0, utc),
+				time.Date(2025, 6, 1, 0, 0, 0, 0, utc),
+				time.Date(2026, 3, 1, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "leap day",
+			expr:  "0 0 29 2 *",
+			after: start,
+			want: []time.Time{
+				time.Date(2028, 2, 29, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "hourly descriptor",
+			expr:  "@hourly",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 1, 11, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "daily descriptor",
+			expr:  "@daily",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 2, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "weekly descriptor runs on Sunday",
+			expr:  "@weekly",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 5, 0, 0, 0, 0, utc),
+				time.Date(2025, 1, 12, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "monthly descriptor",
+			expr:  "@monthly",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 2, 1, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "seven is Sunday",
+			expr:  "0 0 * * 7",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 5, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "weekday range",
+			expr:  "0 9 * * 1-5",
+			after: time.Date(2025, 1, 3, 10, 0, 0, 0, utc),
+			want: []time.Time{
+				time.Date(2025, 1, 6, 9, 0, 0, 0, utc),
+				time.Date(2025, 1, 7, 9, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "day of month and day of week restricted match either",
+			expr:  "0 0 10 * 1",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 6, 0, 0, 0, 0, utc),
+				time.Date(2025, 1, 10, 0, 0, 0, 0, utc),
+				time.Date(2025, 1, 13, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "day of month step with day of week has to match both",
+			expr:  "0 0 */1 * 1",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 6, 0, 0, 0, 0, utc),
+				time.Date(2025, 1, 13, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "full day of month range with day of week has to match both",
+			expr:  "0 0 1-31 * 1",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 6, 0, 0, 0, 0, utc),
+				time.Date(2025, 1, 13, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "odd days of month on a weekday",
+			expr:  "0 0 */2 * 1-5",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 1, 3, 0, 0, 0, 0, utc),
+				time.Date(2025, 1, 7, 0, 0, 0, 0, utc),
+				time.Date(2025, 1, 9, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name:  "day of week step with day of month has to match both",
+			expr:  "0 0 1 * */1",
+			after: start,
+			want: []time.Time{
+				time.Date(2025, 2, 1, 0, 0, 0, 0, utc),
+				time.Date(2025, 3, 1, 0, 0, 0, 0, utc),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := mustCron(t, tt.expr, utc)
+			after := tt.after
+			for i, want := range tt.want {
+				got := schedule.Next(after)
+				if !got.Equal(want) {
+					t.Fatalf("run %d: Next(%v) = %v, want %v", i, after, got, want)
+				}
+				after = got
+			}
+		})
+	}
+}
+
+func TestCronNextDaylightSaving(t *testing.T) {
+	berlin := mustLocation(t, "Europe/Berlin")
+	newYork := mustLocation(t, "America/New_York")
+
+	tests := []struct {
+		name     string
+		expr     string
+		location *time.Location
+		after    time.Time
+		want     []time.Time
+	}{
+		{
+			name:     "time skipped in spring runs at the jump",
+			expr:     "30 2 * * *",
+			location: berlin,
+			after:    time.Date(2025, 3, 30, 0, 0, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2025, 3, 30, 3, 0, 0, 0, berlin),
+				time.Date(2025, 3, 31, 2, 30, 0, 0, berlin),
+			},
+		},
+		{
+			name:     "time skipped in spring runs at the jump in the Americas",
+			expr:     "30 2 * * *",
+			location: newYork,
+			after:    time.Date(2025, 3, 9, 0, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2025, 3, 9, 3, 0, 0, 0, newYork),
+				time.Date(2025, 3, 10, 2, 30, 0, 0, newYork),
+			},
+		},
+		{
+			name:     "steps across the spring gap run once at the jump",
+			expr:     "*/20 * * * *",
+			location: berlin,
+			after:    time.Date(2025, 3, 30, 1, 40, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2025, 3, 30, 3, 0, 0, 0, berlin),
+				time.Date(2025, 3, 30, 3, 20, 0, 0, berlin),
+			},
+		},
+		{
+			name:     "repeated hour in autumn runs once",
+			expr:     "30 2 * * *",
+			location: berlin,
+			after:    time.Date(2025, 10, 26, 0, 0, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2025, 10, 26, 2, 30, 0, 0, berlin),
+				time.Date(2025, 10, 27, 2, 30, 0, 0, berlin),
+			},
+		},
+		{
+			name:     "repeated hour in autumn runs once in the Americas",
+			expr:     "30 1 * * *",
+			location: newYork,
+			after:    time.Date(2025, 11, 2, 0, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2025, 11, 2, 1, 30, 0, 0, newYork),
+				time.Date(2025, 11, 3, 1, 30, 0, 0, newYork),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := mustCron(t, tt.expr, tt.location)
+			after := tt.after
+			for i, want := range tt.want {
+				got := schedule.Next(after)
+				if !got.Equal(want) {
+					t.Fatalf("run %d: Next(%v) = %v, want %v", i, after, got, want)
+				}
+				after = got
+			}
+		})
+	}
+}
+
+func TestEveryNext(t *testing.T) {
+	after := time.Date(2025, 1, 1, 10, 7, 30, 0, time.UTC)
+	want := time.Date(2025, 1, 1, 16, 7, 30, 0, time.UTC)
+
+	if got := Every(6 * time.Hour).Next(after); !got.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", after, got, want)
+	}
+}
+
+func TestRegisterRejectsNonPositiveInterval(t *testing.T) {
+	scheduler := &Scheduler{}
+	run := func(context.Context) (JobResult, error) { return JobResult{}, nil }
+
+	for _, every := range []time.Duration{0, -time.Hour} {
+		err := scheduler.Register(Job{Name: "job", Schedule: Every(every), Run: run})
+		if err == nil {
+			t.Errorf("Register with interval %v succeeded, want an error", every)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownJob     = errors.New("no scheduled job for this worker")
+	ErrNotLeader      = errors.New("another instance holds the lease of this worker")
+	ErrJobRunning     = errors.New("previous run is still in progress")
+	ErrNotStarted     = errors.New("scheduler is not running yet")
+	ErrNoShowChecker  = errors.New("show checker is not registered")
+	ErrShowNotTracked = errors.New("user does not track this show")
+
+	// errLeaseLost is the cause of a run cancelled because another instance may have taken over its job
+	errLeaseLost = errors.New("worker lease lost to another instance")
+)
+
+// ConcurrencyPolicy tells the runner what to do when a job is due while its previous run is still going
+type ConcurrencyPolicy int
+
+const (
+	// ConcurrencyForbid skips the new run
+	ConcurrencyForbid ConcurrencyPolicy = iota
+	// ConcurrencyAllow starts the new run next to the old one
+	ConcurrencyAllow
+	// ConcurrencyReplace cancels the old run and starts the new one
+	ConcurrencyReplace
+)
+
+// JobResult is what one run of a job reports back to the runner
+type JobResult struct {
+	Checked       int
+	Updates       int
+	ApiCallsSaved int
+}
+
+// JobFunc does the work of one run. It should return early once the context is done.
+type JobFunc func(ctx context.Context) (JobResult, error)
+
+// Job is a periodic piece of work run by the Scheduler
+type Job struct {
+	Name        string
+	Schedule    Schedule
+	Timeout     time.Duration
+	Concurrency ConcurrencyPolicy
+	Run         JobFunc
+
+	// worker records the runs, jobs of an existing worker share its identity and lease
+	worker *workerBase
+}
+
+// jobState tracks the runs of one registered job
+type jobState struct {
+	job     Job
+	mu      sync.Mutex
+	running int
+	cancel  context.CancelFunc
+}
+
+// Scheduler runs registered jobs on their schedules. Every run is recorded in worker_states and
+// worker_tasks, and only the instance holding a job's lease runs it.
+type Scheduler struct {
+	app  *app.App
+	mu   sync.Mutex
+	jobs []*jobState
+	// ctx is the context passed to Start, manual runs are bound to it as well
+	ctx context.Context
+	// showChecker serves single show checks requested by admins
+	showChecker *TVShowChecker
+	// runs counts the job runs in flight, Start waits for them before returning
+	runs sync.WaitGroup
+}
+
+func NewScheduler(app *app.App) *Scheduler {
+	const op = "workers.NewScheduler"
+	app.Logger.WorkerInfo(op, "Initializing job scheduler")
+
+	return &Scheduler{
+		app: app,
+	}
+}
+
+// Register adds a job to the scheduler. Jobs without a worker get their own worker identity named after the job.
+func (s *Scheduler) Register(job Job) error {
+	const op = "workers.Register"
+	if job.Name == "" || job.Schedule == nil || job.Run == nil {
+		return errors.New("job needs a name, a schedule and a run function")
+	}
+	// A non-positive interval would run the job again right away, over and over
+	if interval, ok := job.Schedule.(intervalSchedule); ok && interval.every <= 0 {
+		return fmt.Errorf("job %q needs a positive interval, got %v", job.Name, interval.every)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.jobs {
+		if existing.job.Name == job.Name {
+			return fmt.Errorf("job %q is already registered", job.Name)
+		}
+	}
+
+	if job.worker == nil {
+		base := newWorkerBase(s.app, job.Name, job.Name)
+		job.worker = &base
+	}
+
+	s.jobs = append(s.jobs, &jobState{job: job})
+	s.app.Logger.WorkerInfo(op, "Job registered",
+		"job", job.Name, "schedule", job.Schedule.String(), "timeout", job.Timeout.String(),
+		"worker_id", job.worker.workerId)
+	return nil
+}
+
+// Start runs every registered job until the context is cancelled. Runs in flight get the cancelled context as
+// well and Start returns once all of them have stopped.
+func (s *Scheduler) Start(ctx context.Context) {
+	const op = "workers.Start"
+	s.mu.Lock()
+	s.ctx = ctx
+	jobs := append([]*jobState(nil), s.jobs...)
+	s.mu.Unlock()
+
+	s.app.Logger.WorkerInfo(op, "Starting job scheduler", "job_count", len(jobs))
+
+	var wg sync.WaitGroup
+	for _, state := range jobs {
+		wg.Add(1)
+		go func(state *jobState) {
+			defer wg.Done()
+			s.runJob(ctx, state)
+		}(state)
+	}
+	wg.Wait()
+
+	s.app.Logger.WorkerInfo(op, "Waiting for running jobs to finish")
+	s.runs.Wait()
+
+	for _, state := range jobs {
+		state.job.worker.releaseLease()
+	}
+
+	s.app.Logger.WorkerInfo(op, "Job scheduler stopped")
+}
+
+// runJob waits for each due time of a job and starts a run, following the job's concurrency policy
+func (s *Scheduler) runJob(ctx context.Context, state *jobState) {
+	const op = "workers.runJob"
+	job := state.job
+	job.worker.startLease(ctx)
+
+	next := s.firstRun(job)
+	for {
+		_, changed := job.worker.leader.state()
+		s.app.Logger.WorkerInfo(op, "Next run scheduled",
+			"job", job.Name, "next_run", next, "delay_minutes", time.Until(next).Minutes())
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.app.Logger.WorkerInfo(op, "Context cancelled, stopping job", "job", job.Name)
+			if job.worker.isLeader() {
+				job.worker.updateWorkerStatus(StatusIdle, nil)
+			}
+			return
+		case <-changed:
+			timer.Stop()
+			if job.worker.isLeader() {
+				// This instance took over, the schedule left by the previous leader decides when the job is due
+				s.app.Logger.WorkerInfo(op, "Took over the job, resuming its schedule", "job", job.Name)
+				next = s.firstRun(job)
+			}
+			continue
+		case <-timer.C:
+		}
+
+		switch {
+		case !job.worker.isLeader():
+			s.app.Logger.WorkerDebug(op, "Another instance holds the lease, skipping run", "job", job.Name)
+		case job.worker.isPaused():
+			s.app.Logger.WorkerInfo(op, "Job is paused, skipping run", "job", job.Name)
+		default:
+			if err := s.startRun(ctx, state); err != nil {
+				s.app.Logger.WorkerWarning(op, "Run not started", "job", job.Name, "reason", err.Error())
+			}
+		}
+		next = job.Schedule.Next(time.Now())
+	}
+}
+
+// firstRun resumes the schedule stored by a previous run. Missed runs and interrupted runs start right away.
+func (s *Scheduler) firstRun(job Job) time.Time {
+	const op = "workers.firstRun"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	state, err := s.app.Repository.Worker.GetWorkerState(ctx, job.worker.workerId)
+	if err != nil || !state.LastCheckTime.Valid {
+		return now
+	}
+
+	if state.CycleCursor != nil {
+		s.app.Logger.WorkerInfo(op, "Previous run was interrupted, resuming now",
+			"job", job.Name, "cursor", *state.CycleCursor)
+		return now
+	}
+
+	next := job.Schedule.Next(state.LastCheckTime.Time)
+	if next.Before(now) {
+		return now
+	}
+	return next
+}
+
+// startRun starts one run of a job unless this instance is not the leader or the concurrency policy says otherwise
+func (s *Scheduler) startRun(ctx context.Context, state *jobState) error {
+	const op = "workers.startRun"
+	job := state.job
+	if ctx.Err() != nil {
+		// The scheduler is shutting down
+		return ctx.Err()
+	}
+	leader, changed := job.worker.leader.state()
+	if !leader {
+		return ErrNotLeader
+	}
+
+	state.mu.Lock()
+	if state.running > 0 {
+		switch job.Concurrency {
+		case ConcurrencyForbid:
+			state.mu.Unlock()
+			return ErrJobRunning
+		case ConcurrencyReplace:
+			s.app.Logger.WorkerWarning(op, "Previous run still in progress, cancelling it", "job", job.Name)
+			state.cancel()
+		}
+	}
+
+	runCtx, cancelRun := context.WithCancelCause(ctx)
+	cancel := func() { cancelRun(nil) }
+	if job.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		runCtx, cancelTimeout = context.WithTimeout(runCtx, job.Timeout)
+		cancel = func() {
+			cancelTimeout()
+			cancelRun(nil)
+		}
+	}
+	state.running++
+	state.cancel = cancel
+	state.mu.Unlock()
+
+	s.runs.Add(1)
+	go func() {
+		defer func() {
+			cancel()
+			state.mu.Lock()
+			state.running--
+			state.mu.Unlock()
+			s.runs.Done()
+		}()
+		s.execute(runCtx, job)
+	}()
+
+	go func() {
+		select {
+		case <-changed:
+			// The lease could not be renewed, so another instance may already run the same job
+			s.app.Logger.WorkerWarning(op, "Lost the worker lease, cancelling run", "job", job.Name)
+			cancelRun(errLeaseLost)
+		case <-runCtx.Done():
+		}
+	}()
+	return nil
+}
+
+// RunNow starts a run of the job of a worker type right away, outside of its schedule and even while it is paused.
+// Only the instance holding the worker's lease can run it.
+func (s *Scheduler) RunNow(workerType string) error {
+	const op = "workers.RunNow"
+	state := s.jobByWorkerType(workerType)
+	if state == nil {
+		return ErrUnknownJob
+	}
+
+	s.mu.Lock()
+	ctx := s.ctx
+	s.mu.Unlock()
+	if ctx == nil {
+		return ErrNotStarted
+	}
+
+	if err := s.startRun(ctx, state); err != nil {
+		return err
+	}
+
+	s.app.Logger.WorkerInfo(op, "Manual run started", "job", state.job.Name)
+	return nil
+}
+
+// IsScheduled reports whether runs of a worker type are driven by the scheduler
+func (s *Scheduler) IsScheduled(workerType string) bool {
+	return s.jobByWorkerType(workerType) != nil
+}
+
+// RecheckShow checks one show of one user right away and reports whether an update was found. Like RunNow it
+// only works on the instance holding the show checker's lease, so a notification is never sent twice.
+func (s *Scheduler) RecheckShow(userId, showId int64) (bool, error) {
+	s.mu.Lock()
+	checker := s.showChecker
+	ctx := s.ctx
+	s.mu.Unlock()
+	if checker == nil {
+		return false, ErrNoShowChecker
+	}
+	if ctx == nil {
+		return false, ErrNotStarted
+	}
+	if !checker.isLeader() {
+		return false, ErrNotLeader
+	}
+
+	return checker.checkShow(ctx, userId, showId)
+}
+
+func (s *Scheduler) jobByWorkerType(workerType string) *jobState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, state := range s.jobs {
+		if state.job.worker.workerType == workerType {
+			return state
+		}
+	}
+	return nil
+}
+
+// execute runs a job once and records the run in worker_states and worker_tasks
+func (s *Scheduler) execute(ctx context.Context, job Job) {
+	const op = "workers.execute"
+	worker := job.worker
+	s.app.Logger.WorkerInfo(op, "Starting job run", "job", job.Name, "worker_id", worker.workerId)
+	start := time.Now()
+
+	worker.updateWorkerStatus(StatusRunning, nil)
+
+	taskID, err := worker.createWorkerTask(job.Name, nil, 0)
+	if err != nil {
+		s.app.Logger.WorkerError(op, "Failed to create task record",
+			"job", job.Name, "worker_id", worker.workerId, "error", err.Error())
+	}
+
+	result, err := s.safeRun(ctx, job)
+	if err == nil && ctx.Err() != nil {
+		// The job gave up early because of its timeout, a shutdown or a lost lease
+		err = context.Cause(ctx)
+	}
+
+	worker.completeWorkerTask(taskID, err, result.Checked, result.Updates)
+	if result.ApiCallsSaved > 0 {
+		worker.recordApiCallsSaved(taskID, result.ApiCallsSaved)
+	}
+	if !worker.isLeader() {
+		// The state belongs to the new leader now
+		s.app.Logger.WorkerWarning(op, "Job run stopped after the lease was lost",
+			"job", job.Name, "duration_ms", time.Since(start).Milliseconds())
+		return
+	}
+	if err != nil {
+		worker.updateWorkerStatus(StatusError, err)
+		s.app.Logger.WorkerError(op, "Job run failed",
+			"job", job.Name, "duration_ms", time.Since(start).Milliseconds(), "error", err.Error())
+		return
+	}
+
+	worker.updateWorkerCheck(start, job.Schedule.Next(start), result.Checked, result.Updates)
+	s.app.Logger.WorkerInfo(op, "Job run completed",
+		"job", job.Name,
+		"duration_ms", time.Since(start).Milliseconds(),
+		"checked", result.Checked,
+		"updates", result.Updates)
+}
+
+// safeRun keeps a panicking job from taking the whole bot down
+func (s *Scheduler) safeRun(ctx context.Context, job Job) (result JobResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job %s panicked: %v", job.Name, r)
+		}
+	}()
+
+	return job.Run(ctx)
+}
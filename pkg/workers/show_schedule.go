@@ -0,0 +1,88 @@
+package workers
+
+import (
+	"context"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/tv"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"time"
+)
+
+// Check intervals of a show depend on how likely it is to change soon
+const (
+	// finishedShowInterval applies to ended and canceled shows, they rarely come back
+	finishedShowInterval = 30 * 24 * time.Hour
+	// idleShowInterval applies to running shows without an announced episode
+	idleShowInterval = 7 * 24 * time.Hour
+	// airingShowInterval applies to shows with an episode airing within airingSoonWindow
+	airingShowInterval = 6 * time.Hour
+	airingSoonWindow   = 48 * time.Hour
+	// minShowInterval keeps shows with a far away episode from being checked more than daily
+	minShowInterval = 24 * time.Hour
+)
+
+// nextShowCheck decides when a show is fetched again. Shows with an upcoming episode are checked at half
+// the time left until it airs, so the checks get closer together as the air date approaches.
+func nextShowCheck(details *tv.TV, now time.Time) time.Time {
+	switch details.Status {
+	case constants.ShowStatusEnded, constants.ShowStatusCanceled:
+		return now.Add(finishedShowInterval)
+	}
+
+	if details.NextEpisodeToAir == nil {
+		return now.Add(idleShowInterval)
+	}
+
+	airDate, err := time.Parse(constants.DateFormat, details.NextEpisodeToAir.AirDate)
+	if err != nil {
+		return now.Add(idleShowInterval)
+	}
+
+	untilAir := airDate.Sub(now)
+	if untilAir <= airingSoonWindow {
+		return now.Add(airingShowInterval)
+	}
+
+	return now.Add(min(max(untilAir/2, minShowInterval), idleShowInterval))
+}
+
+// dueShows drops the groups whose next check time has not come yet, never checked shows are always due.
+// If the schedules can't be read every show is treated as due.
+func (c *TVShowChecker) dueShows(ctx context.Context, groups []*ShowGroup) []*ShowGroup {
+	const op = "workers.dueShows"
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	schedules, err := c.app.Repository.TVShows.GetShowCheckSchedules(ctx)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to get show check schedules, checking every show", "error", err.Error())
+		return groups
+	}
+
+	now := time.Now()
+	due := groups[:0]
+	for _, group := range groups {
+		if nextCheck, scheduled := schedules[group.ApiID]; scheduled && now.Before(nextCheck) {
+			continue
+		}
+		due = append(due, group)
+	}
+
+	return due
+}
+
+// scheduleNextCheck stores when a freshly fetched show is due again
+func (c *TVShowChecker) scheduleNextCheck(ctx context.Context, details *tv.TV) {
+	const op = "workers.scheduleNextCheck"
+	ctx, cancel := persistContext(ctx)
+	defer cancel()
+
+	nextCheck := nextShowCheck(details, time.Now())
+	if err := c.app.Repository.TVShows.UpsertShowCheckSchedule(ctx, details.Id, details.Status, nextCheck); err != nil {
+		c.app.Logger.WorkerError(op, "Failed to store next check time",
+			"show_id", details.Id, "error", err.Error())
+		return
+	}
+
+	c.app.Logger.WorkerDebug(op, "Scheduled next check of show",
+		"show_id", details.Id, "status", details.Status, "next_check", nextCheck)
+}
@@ -0,0 +1,351 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"time"
+)
+
+// workerBase holds the identity shared by every background worker and the
+// methods that record its state and tasks in the database
+type workerBase struct {
+	app        *app.App
+	workerId   string
+	workerType string
+	// leader is shared by copies of the base, it is set while this instance holds the worker lease
+	leader *leadership
+}
+
+// persistContext bounds a write that records work already done. It is not cancelled together with the run, so
+// a shutdown stops a job between two shows instead of halfway through storing one.
+func persistContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+}
+
+// newWorkerBase builds the worker ID from the type prefix and the configured instance name. Nothing is written
+// until the instance wins the lease of the worker type, a standby must not touch the state of a running leader.
+func newWorkerBase(app *app.App, workerType, idPrefix string) workerBase {
+	const op = "workers.newWorkerBase"
+
+	instance := app.Cfg.General.WorkerInstance
+	if instance == "" {
+		instance = defaultWorkerInstance
+	}
+	workerId := idPrefix + "-" + instance
+	app.Logger.WorkerDebug(op, "Resolved worker ID", "worker_id", workerId, "type", workerType)
+
+	return workerBase{
+		app:        app,
+		workerId:   workerId,
+		workerType: workerType,
+		leader:     newLeadership(),
+	}
+}
+
+// takeOver continues the work of the previous leader of the worker type once this instance wins the lease. Its
+// schedule and the cursor of an interrupted cycle move to this worker, and every task of the type still marked as
+// running is aborted, since nothing else of the type can run while the lease is held here.
+func (c *workerBase) takeOver() {
+	const op = "workers.takeOver"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	params := database.UpsertWorkerStateParams{
+		WorkerID:      c.workerId,
+		WorkerType:    c.workerType,
+		Status:        StatusIdle,
+		LastCheckTime: pgtype.Timestamptz{},
+		NextCheckTime: pgtype.Timestamptz{},
+		Error:         nil,
+		ShowsChecked:  0,
+		UpdatesFound:  0,
+		CreatedAt:     pgtype.Timestamptz{Time: now, Valid: true},
+		UpdatedAt:     pgtype.Timestamptz{Time: now, Valid: true},
+	}
+
+	// The counters belong to this worker, the schedule to whichever worker of the type ran last
+	own, err := c.app.Repository.Worker.GetWorkerState(ctx, c.workerId)
+	if err == nil {
+		params.ShowsChecked = own.ShowsChecked
+		params.UpdatesFound = own.UpdatesFound
+		params.CreatedAt = own.CreatedAt
+	}
+
+	var cursor *int64
+	previous, err := c.app.Repository.Worker.GetLatestWorkerState(ctx, c.workerType)
+	switch {
+	case err == nil:
+		params.LastCheckTime = previous.LastCheckTime
+		params.NextCheckTime = previous.NextCheckTime
+		cursor = previous.CycleCursor
+	case !errors.Is(err, pgx.ErrNoRows):
+		c.app.Logger.WorkerError(op, "Failed to get state of the previous leader",
+			"worker_id", c.workerId, "type", c.workerType, "error", err.Error())
+	}
+
+	if err = c.app.Repository.Worker.UpsertWorkerState(ctx, params); err != nil {
+		c.app.Logger.WorkerError(op, "Failed to initialize worker state in database",
+			"worker_id", c.workerId, "error", err.Error())
+	} else {
+		c.app.Logger.WorkerInfo(op, "Worker state taken over",
+			"worker_id", c.workerId, "type", c.workerType, "previous_worker_id", previous.WorkerID,
+			"last_check_time", params.LastCheckTime.Time)
+	}
+
+	if cursor != nil {
+		if err = c.app.Repository.Worker.UpdateWorkerCycleCursor(ctx, c.workerId, cursor); err != nil {
+			c.app.Logger.WorkerError(op, "Failed to take over cycle cursor",
+				"worker_id", c.workerId, "error", err.Error())
+		} else if previous.WorkerID != c.workerId {
+			// The interrupted cycle continues here, the previous leader's row must not claim it any longer
+			if err = c.app.Repository.Worker.UpdateWorkerCycleCursor(ctx, previous.WorkerID, nil); err != nil {
+				c.app.Logger.WorkerError(op, "Failed to clear cycle cursor of the previous leader",
+					"worker_id", previous.WorkerID, "error", err.Error())
+			}
+		}
+	}
+
+	aborted, err := c.app.Repository.Worker.AbortRunningWorkerTasks(ctx, c.workerType, "interrupted, lease taken over by "+c.workerId)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to abort orphaned worker tasks",
+			"worker_id", c.workerId, "type", c.workerType, "error", err.Error())
+	} else if aborted > 0 {
+		c.app.Logger.WorkerWarning(op, "Aborted tasks left running by a previous leader",
+			"worker_id", c.workerId, "type", c.workerType, "task_count", aborted)
+	}
+}
+
+// Database interaction methods
+
+// updateWorkerStatus updates the worker's status in the database
+func (c *workerBase) updateWorkerStatus(status string, err error) {
+	const op = "workers.updateWorkerStatus"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var errorStr *string
+	if err != nil {
+		errText := err.Error()
+		errorStr = &errText
+	}
+
+	now := time.Now()
+	params := database.UpsertWorkerStateParams{
+		WorkerID:   c.workerId,
+		WorkerType: c.workerType,
+		Status:     status,
+		Error:      errorStr,
+		UpdatedAt:  pgtype.Timestamptz{Time: now, Valid: true},
+	}
+
+	// Get current state to preserve other fields
+	currentState, dbErr := c.app.Repository.Worker.GetWorkerState(ctx, c.workerId)
+	if dbErr == nil {
+		// Keep existing values
+		params.LastCheckTime = currentState.LastCheckTime
+		params.NextCheckTime = currentState.NextCheckTime
+		params.ShowsChecked = currentState.ShowsChecked
+		params.UpdatesFound = currentState.UpdatesFound
+		params.CreatedAt = currentState.CreatedAt
+	} else {
+		// Set defaults for new record
+		params.LastCheckTime = pgtype.Timestamptz{}
+		params.NextCheckTime = pgtype.Timestamptz{}
+		params.ShowsChecked = 0
+		params.UpdatesFound = 0
+		params.CreatedAt = pgtype.Timestamptz{Time: now, Valid: true}
+	}
+
+	dbErr = c.app.Repository.Worker.UpsertWorkerState(ctx, params)
+	if dbErr != nil {
+		c.app.Logger.WorkerError(op, "Failed to update worker status",
+			"worker_id", c.workerId, "status", status, "error", dbErr.Error())
+	}
+}
+
+// isPaused reports whether an admin paused the worker type. The flag is read across every worker of the type, a
+// new leader has to honour a pause set while another instance ran it. A failed lookup lets the worker run.
+func (c *workerBase) isPaused() bool {
+	const op = "workers.isPaused"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	paused, err := c.app.Repository.Worker.IsWorkerTypePaused(ctx, c.workerType)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to get worker pause",
+			"worker_id", c.workerId, "type", c.workerType, "error", err.Error())
+		return false
+	}
+
+	return paused
+}
+
+// updateWorkerCheck records a completed check cycle and when the next one is due
+func (c *workerBase) updateWorkerCheck(checkTime, nextCheckTime time.Time, showsChecked, updatesFound int) {
+	const op = "workers.updateWorkerCheck"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Get current state
+	currentState, err := c.app.Repository.Worker.GetWorkerState(ctx, c.workerId)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to get worker state",
+			"worker_id", c.workerId, "error", err.Error())
+		return
+	}
+
+	params := database.UpsertWorkerStateParams{
+		WorkerID:      c.workerId,
+		WorkerType:    c.workerType,
+		Status:        StatusIdle, // Set to idle after completion
+		LastCheckTime: pgtype.Timestamptz{Time: checkTime, Valid: true},
+		NextCheckTime: pgtype.Timestamptz{Time: nextCheckTime, Valid: true},
+		ShowsChecked:  currentState.ShowsChecked + int32(showsChecked),
+		UpdatesFound:  currentState.UpdatesFound + int32(updatesFound),
+		Error:         nil, // Clear any previous error
+		CreatedAt:     currentState.CreatedAt,
+		UpdatedAt:     pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+
+	err = c.app.Repository.Worker.UpsertWorkerState(ctx, params)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to update worker check info",
+			"worker_id", c.workerId, "error", err.Error())
+	} else {
+		c.app.Logger.WorkerDebug(op, "Updated worker check info",
+			"worker_id", c.workerId,
+			"shows_checked", showsChecked,
+			"updates_found", updatesFound,
+			"next_check", nextCheckTime)
+	}
+}
+
+// createWorkerTask creates a new task record in the database
+func (c *workerBase) createWorkerTask(taskType string, userID *int64, showID int64) (uuid.UUID, error) {
+	const op = "workers.createWorkerTask"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+
+	// Convert nullable parameters
+	var userIDVal *int64
+	if userID != nil {
+		userIDVal = userID
+	}
+
+	// Only set showID if it's a valid show
+	var showIDVal *int64
+	if showID > 0 {
+		showIDVal = &showID
+	}
+
+	params := database.CreateWorkerTaskParams{
+		WorkerID:  c.workerId,
+		TaskType:  taskType,
+		Status:    TaskStatusRunning,
+		StartTime: pgtype.Timestamptz{Time: now, Valid: true},
+		EndTime:   pgtype.Timestamptz{}, // Will be set on completion
+		UserID:    userIDVal,
+		ShowID:    showIDVal,
+		CreatedAt: pgtype.Timestamptz{Time: now, Valid: true},
+	}
+
+	c.app.Logger.WorkerDebug(op, "Creating worker task",
+		"worker_id", c.workerId, "task_type", taskType, "show_id", showID)
+
+	taskID, err := c.app.Repository.Worker.CreateWorkerTask(ctx, params)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to create worker task",
+			"worker_id", c.workerId, "task_type", taskType, "error", err.Error())
+		return uuid.Nil, err
+	}
+
+	c.app.Logger.WorkerDebug(op, "Worker task created",
+		"task_id", taskID, "worker_id", c.workerId, "task_type", taskType)
+	return taskID, nil
+}
+
+// completeWorkerTask updates a task as completed or failed
+func (c *workerBase) completeWorkerTask(taskID uuid.UUID, err error, showsChecked, updatesFound int) {
+	const op = "workers.completeWorkerTask"
+	if taskID == uuid.Nil {
+		c.app.Logger.WorkerWarning(op, "Cannot update task with nil UUID", "worker_id", c.workerId)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+
+	var status string
+	var errorStr *string
+	if err != nil {
+		status = TaskStatusError
+		errText := err.Error()
+		errorStr = &errText
+	} else {
+		status = TaskStatusSuccess
+	}
+
+	// Get the original task to calculate duration
+	task, getErr := c.app.Repository.Worker.GetWorkerTask(ctx, taskID)
+	if getErr != nil {
+		c.app.Logger.WorkerError(op, "Failed to get task",
+			"task_id", taskID, "worker_id", c.workerId, "error", getErr.Error())
+		return
+	}
+
+	// Calculate duration in milliseconds
+	var durationMs int64
+	if task.StartTime.Valid {
+		durationMs = now.Sub(task.StartTime.Time).Milliseconds()
+	}
+
+	var v1 int32 = int32(showsChecked)
+	var v2 int32 = int32(updatesFound)
+
+	params := database.UpdateWorkerTaskParams{
+		ID:           taskID,
+		Status:       status,
+		EndTime:      pgtype.Timestamptz{Time: now, Valid: true},
+		DurationMs:   &durationMs,
+		Error:        errorStr,
+		ShowsChecked: &v1,
+		UpdatesFound: &v2,
+	}
+
+	err = c.app.Repository.Worker.UpdateWorkerTask(ctx, params)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to update worker task",
+			"task_id", taskID, "worker_id", c.workerId, "error", err.Error())
+	} else {
+		c.app.Logger.WorkerDebug(op, "Worker task completed",
+			"task_id", taskID, "worker_id", c.workerId,
+			"status", status, "duration_ms", durationMs,
+			"shows_checked", showsChecked, "updates_found", updatesFound)
+	}
+}
+
+// recordApiCallsSaved stores how many TMDB requests a task avoided by sharing results between users
+func (c *workerBase) recordApiCallsSaved(taskID uuid.UUID, saved int) {
+	const op = "workers.recordApiCallsSaved"
+	if taskID == uuid.Nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := c.app.Repository.Worker.UpdateWorkerTaskApiCallsSaved(ctx, taskID, int32(saved)); err != nil {
+		c.app.Logger.WorkerError(op, "Failed to record saved API calls",
+			"task_id", taskID, "worker_id", c.workerId, "error", err.Error())
+	}
+}
@@ -1,26 +1,28 @@
 package workers
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
-	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/image"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/tv"
-	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/utils"
+	"github.com/jackc/pgx/v5"
 	"gopkg.in/telebot.v3"
+	"sort"
 	"sync"
 	"time"
 )
 
-func (c *WorkerApiClient) GetShowDetails(app *app.App, apiId int, userId int64) (*tv.TV, error) {
+func (c *WorkerApiClient) GetShowDetails(ctx context.Context, app *app.App, apiId int, userId int64) (*tv.TV, error) {
 	const op = "workers.GetShowDetails"
 	app.Logger.WorkerDebug(op, "Attempting to fetch details for show",
 		"show_id", apiId, "user_id", userId)
 
-	err := c.limiter.Wait(context.Background())
+	err := c.limiter.Wait(ctx)
 	if err != nil {
 		app.Logger.WorkerError(op, "Rate limit wait error",
 			"show_id", apiId, "error", err.Error())
@@ -28,7 +30,7 @@ func (c *WorkerApiClient) GetShowDetails(app *app.App, apiId int, userId int64)
 	}
 
 	start := time.Now()
-	tvData, err := tv.GetTV(app, apiId, userId)
+	tvData, err := tv.GetTV(ctx, app, apiId, userId)
 	duration := time.Since(start)
 
 	if err != nil {
@@ -42,119 +44,150 @@ func (c *WorkerApiClient) GetShowDetails(app *app.App, apiId int, userId int64)
 	return tvData, nil
 }
 
-func (c *TVShowChecker) StartChecking(ctx context.Context, checkInterval int) {
-	const op = "workers.StartChecking"
-	c.app.Logger.WorkerInfo(op, "Starting TV show checker",
-		"worker_id", c.workerId, "check_interval_hours", checkInterval)
+func (c *WorkerApiClient) GetSeasonDetails(ctx context.Context, app *app.App, apiId, seasonNumber int, userId int64) (*tv.Season, error) {
+	const op = "workers.GetSeasonDetails"
+	app.Logger.WorkerDebug(op, "Attempting to fetch season details for show",
+		"show_id", apiId, "season", seasonNumber, "user_id", userId)
 
-	// Get the current worker state from the database
-	dbCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	err := c.limiter.Wait(ctx)
+	if err != nil {
+		app.Logger.WorkerError(op, "Rate limit wait error",
+			"show_id", apiId, "season", seasonNumber, "error", err.Error())
+		return nil, fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	start := time.Now()
+	seasonData, err := tv.GetSeason(ctx, app, apiId, seasonNumber, userId)
+	duration := time.Since(start)
 
+	if err != nil {
+		app.Logger.WorkerError(op, "API request failed",
+			"show_id", apiId, "season", seasonNumber, "duration_ms", duration.Milliseconds(), "error", err.Error())
+		return nil, fmt.Errorf("failed to get TV season details: %w", err)
+	}
+
+	app.Logger.WorkerInfo(op, "Successfully fetched season details",
+		"show_id", apiId, "season", seasonNumber, "episode_count", len(seasonData.Episodes),
+		"duration_ms", duration.Milliseconds())
+	return seasonData, nil
+}
+
+// runCheck is one run of the check_all_shows job. A cycle interrupted by a crash or a shutdown is continued
+// from the stored cursor, and held updates are bundled into digests once every show is checked.
+func (c *TVShowChecker) runCheck(ctx context.Context) (JobResult, error) {
+	const op = "workers.runCheck"
+	dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	workerState, err := c.app.Repository.Worker.GetWorkerState(dbCtx, c.workerId)
+	cancel()
 	if err != nil {
 		c.app.Logger.WorkerError(op, "Failed to get worker state",
 			"worker_id", c.workerId, "error", err.Error())
 	}
 
-	timeDuration := time.Duration(checkInterval) * time.Hour
+	var resumeAfter int64
+	if workerState.CycleCursor != nil {
+		resumeAfter = *workerState.CycleCursor
+		c.app.Logger.WorkerInfo(op, "Resuming interrupted check cycle",
+			"worker_id", c.workerId, "after_show_id", resumeAfter)
+	}
 
-	var initialDelay time.Duration
-	if workerState.LastCheckTime.Valid {
-		nextCheckTime := workerState.LastCheckTime.Time.Add(timeDuration)
-		if time.Now().Before(nextCheckTime) {
-			initialDelay = time.Until(nextCheckTime)
-		}
+	shows, updates, saved := c.checkAllShows(ctx, resumeAfter)
+	result := JobResult{Checked: shows, Updates: updates, ApiCallsSaved: saved}
+	if ctx.Err() != nil {
+		// The cursor stays in place, so the next run picks up where this one stopped
+		return result, ctx.Err()
 	}
 
-	if initialDelay > 0 {
-		c.app.Logger.WorkerInfo(op, "Waiting until next check",
-			"worker_id", c.workerId, "delay_minutes", initialDelay.Minutes())
-		select {
-		case <-ctx.Done():
-			return
-		case <-time.After(initialDelay):
-		}
+	// Updates held for digests are bundled once the whole cycle is done
+	digestTaskID, err := c.createWorkerTask(TaskTypeSendDigests, nil, 0)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to create digest task record",
+			"worker_id", c.workerId, "error", err.Error())
 	}
 
-	ticker := time.NewTicker(timeDuration)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			c.app.Logger.WorkerInfo(op, "Context cancelled, stopping TV show checker",
-				"worker_id", c.workerId)
-			// Update worker status to idle
-			c.updateWorkerStatus(StatusIdle, nil)
-			return
-		case <-ticker.C:
-			c.app.Logger.WorkerInfo(op, "Starting check cycle", "worker_id", c.workerId)
-			start := time.Now()
-
-			// Update worker status to running
-			c.updateWorkerStatus(StatusRunning, nil)
-
-			// Create a new task for this check cycle
-			taskID, err := c.createWorkerTask(TaskTypeCheckAllShows, nil, 0)
-			if err != nil {
-				c.app.Logger.WorkerError(op, "Failed to create task record",
-					"worker_id", c.workerId, "error", err.Error())
-			}
+	digestUsers, digests := c.sendDigests()
+	c.completeWorkerTask(digestTaskID, nil, digestUsers, digests)
 
-			shows, updates := c.checkAllShows()
+	return result, nil
+}
 
-			// Update task on completion
-			c.completeWorkerTask(taskID, nil, shows, updates)
+type ShowRequest struct {
+	User *database.GetUsersRow
+	Show database.GetUserTVShowsRow
+}
 
-			// Update worker state
-			c.updateWorkerCheck(start, shows, updates)
+// ShowGroup holds every user tracking or watchlisting the same show, so the show is fetched once per cycle
+type ShowGroup struct {
+	ApiID       int64
+	Requests    []ShowRequest
+	Watchlisted []database.GetWatchlistsByTypeRow
+}
 
-			c.app.Logger.WorkerInfo(op, "Completed check cycle",
-				"worker_id", c.workerId,
-				"duration_ms", time.Since(start).Milliseconds(),
-				"shows_checked", shows,
-				"updates_found", updates)
-		}
+// watchers returns how many users wait for updates of the show
+func (g *ShowGroup) watchers() int {
+	return len(g.Requests) + len(g.Watchlisted)
+}
+
+// keyUsers returns the users whose API keys can fetch the show, users tracking it come first
+func (g *ShowGroup) keyUsers() []int64 {
+	users := make([]int64, 0, g.watchers())
+	for _, req := range g.Requests {
+		users = append(users, req.User.TgID)
+	}
+	for _, entry := range g.Watchlisted {
+		users = append(users, entry.UserID)
 	}
+	return users
 }
 
-type ShowRequest struct {
-	User *database.GetUsersRow
-	Show database.GetUserTVShowsRow
+// showResult is what a show worker reports back for one group
+type showResult struct {
+	updates  int
+	apiCalls int
 }
 
-func (c *TVShowChecker) checkAllShows() (int, int) {
-	const op = "workers.checkAllShows"
-	ctxDb, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+const (
+	// maxKeyAttempts limits how many user keys are tried for one show before giving up for this cycle
+	maxKeyAttempts = 3
+	// cycleChunkSize is how many shows are checked between two saves of the cycle cursor
+	cycleChunkSize = 50
+)
 
+// checkAllShows checks every due tracked show with an API ID above resumeAfter and returns the number of
+// (user, show) pairs checked, the number of updates found and the number of TMDB calls saved by fetching
+// each show only once. Shows are processed in API ID order and the cycle cursor is stored after every
+// chunk, so a cycle interrupted by a crash continues from the last finished chunk. Once the context is done
+// no further chunks are started.
+func (c *TVShowChecker) checkAllShows(ctx context.Context, resumeAfter int64) (int, int, int) {
+	const op = "workers.checkAllShows"
+	// Every query gets a deadline of its own, one for the whole gathering would run out somewhere among many users
+	ctxDb, cancel := context.WithTimeout(ctx, 5*time.Second)
 	users, err := c.app.Repository.Users.GetUsers(ctxDb)
+	cancel()
 	if err != nil {
 		c.app.Logger.WorkerError(op, "Error fetching users", "error", err.Error())
-		return 0, 0
+		return 0, 0, 0
 	}
 	c.app.Logger.WorkerInfo(op, "Found users to process", "user_count", len(users))
 
-	showChan := make(chan ShowRequest, 1000)
-	resultChan := make(chan bool, 1000) // Channel to collect update results
-	var wg sync.WaitGroup
-
-	workerCount := 5
-	c.app.Logger.WorkerInfo(op, "Starting workers", "worker_count", workerCount)
-
-	for i := 0; i < workerCount; i++ {
-		wg.Add(1)
-		go func(workerId int) {
-			c.app.Logger.WorkerDebug(op, "Worker started", "worker_index", workerId)
-			c.showWorker(showChan, &wg, resultChan)
-			c.app.Logger.WorkerDebug(op, "Worker finished", "worker_index", workerId)
-		}(i)
+	// Show details are the same for everyone, so requests are grouped by show before anything is fetched
+	var groups []*ShowGroup
+	byShow := make(map[int64]*ShowGroup)
+	groupOf := func(apiID int64) *ShowGroup {
+		group, exists := byShow[apiID]
+		if !exists {
+			group = &ShowGroup{ApiID: apiID}
+			byShow[apiID] = group
+			groups = append(groups, group)
+		}
+		return group
 	}
 
 	showCount := 0
 	for _, user := range users {
+		ctxDb, cancel = context.WithTimeout(ctx, 5*time.Second)
 		shows, err := c.app.Repository.TVShows.GetUserTVShows(ctxDb, user.TgID)
+		cancel()
 		if err != nil {
 			c.app.Logger.WorkerError(op, "Error fetching shows for user",
 				"user_id", user.TgID, "error", err.Error())
@@ -165,110 +198,395 @@ func (c *TVShowChecker) checkAllShows() (int, int) {
 			"user_id", user.TgID, "show_count", len(shows))
 
 		for _, show := range shows {
-			showCount++
-			showChan <- ShowRequest{
+			if show.ApiID <= resumeAfter {
+				continue
+			}
+
+			group := groupOf(show.ApiID)
+			group.Requests = append(group.Requests, ShowRequest{
 				User: &user,
 				Show: show,
-			}
+			})
 		}
 	}
 
-	c.app.Logger.WorkerInfo(op, "Queued shows for processing", "total_shows", showCount)
+	// Shows that are only on watchlists are waited for just the same, they join the groups of tracked shows
+	ctxDb, cancel = context.WithTimeout(ctx, 5*time.Second)
+	watchlisted, err := c.app.Repository.Watchlists.GetWatchlistsByType(ctxDb, constants.TVShowType)
+	cancel()
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Error fetching watchlisted shows", "error", err.Error())
+	}
+	for _, entry := range watchlisted {
+		if entry.ShowApiID <= resumeAfter {
+			continue
+		}
+
+		group := groupOf(entry.ShowApiID)
+		group.Watchlisted = append(group.Watchlisted, entry)
+	}
+
+	tracked := len(groups)
+	groups = c.dueShows(ctx, groups)
+	for _, group := range groups {
+		showCount += group.watchers()
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].ApiID < groups[j].ApiID
+	})
+
+	c.app.Logger.WorkerInfo(op, "Queued shows for processing",
+		"total_shows", showCount, "unique_shows", len(groups), "not_due_shows", tracked-len(groups),
+		"resume_after", resumeAfter)
+
+	// A stored cursor marks the cycle as in progress, even before the first chunk is done
+	c.saveCycleCursor(&resumeAfter)
+
+	updateCount := 0
+	apiCalls := 0
+	for chunkStart := 0; chunkStart < len(groups); chunkStart += cycleChunkSize {
+		if ctx.Err() != nil {
+			c.app.Logger.WorkerWarning(op, "Check cycle stopped before all shows were checked",
+				"checked_unique_shows", chunkStart, "unique_shows", len(groups), "error", ctx.Err().Error())
+			return showCount, updateCount, showCount - apiCalls
+		}
+
+		chunk := groups[chunkStart:min(chunkStart+cycleChunkSize, len(groups))]
+		updates, calls := c.checkShowChunk(ctx, chunk)
+		updateCount += updates
+		apiCalls += calls
+
+		// Shows of an interrupted chunk may not have been checked, the cursor stays before the chunk so the
+		// next run checks it again
+		if ctx.Err() != nil {
+			c.app.Logger.WorkerWarning(op, "Check cycle stopped in the middle of a chunk",
+				"checked_unique_shows", chunkStart, "unique_shows", len(groups), "error", ctx.Err().Error())
+			return showCount, updateCount, showCount - apiCalls
+		}
+
+		cursor := chunk[len(chunk)-1].ApiID
+		c.saveCycleCursor(&cursor)
+	}
+
+	c.saveCycleCursor(nil)
+	saved := showCount - apiCalls
+
+	c.app.Logger.WorkerInfo(op, "All workers completed",
+		"shows_processed", showCount, "updates_found", updateCount,
+		"api_calls", apiCalls, "api_calls_saved", saved)
+	return showCount, updateCount, saved
+}
+
+// checkShowChunk checks a chunk of shows in parallel and returns the updates found and TMDB calls made
+func (c *TVShowChecker) checkShowChunk(ctx context.Context, chunk []*ShowGroup) (int, int) {
+	const op = "workers.checkShowChunk"
+	showChan := make(chan *ShowGroup, len(chunk))
+	resultChan := make(chan showResult, len(chunk)) // Channel to collect update results
+	var wg sync.WaitGroup
+
+	workerCount := 5
+	c.app.Logger.WorkerDebug(op, "Starting workers", "worker_count", workerCount, "chunk_size", len(chunk))
+
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func(workerId int) {
+			c.app.Logger.WorkerDebug(op, "Worker started", "worker_index", workerId)
+			c.showWorker(ctx, showChan, &wg, resultChan)
+			c.app.Logger.WorkerDebug(op, "Worker finished", "worker_index", workerId)
+		}(i)
+	}
+
+	for _, group := range chunk {
+		showChan <- group
+	}
 	close(showChan)
 
 	// Wait for all workers to complete
 	wg.Wait()
 	close(resultChan)
 
-	// Count updates
-	updateCount := 0
-	for update := range resultChan {
-		if update {
-			updateCount++
-		}
+	// Count updates and the requests actually sent to TMDB
+	updates := 0
+	apiCalls := 0
+	for result := range resultChan {
+		updates += result.updates
+		apiCalls += result.apiCalls
 	}
 
-	c.app.Logger.WorkerInfo(op, "All workers completed",
-		"shows_processed", showCount, "updates_found", updateCount)
-	return showCount, updateCount
+	return updates, apiCalls
+}
+
+// saveCycleCursor stores the API ID of the last show checked in the current cycle, nil ends the cycle
+func (c *TVShowChecker) saveCycleCursor(cursor *int64) {
+	const op = "workers.saveCycleCursor"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := c.app.Repository.Worker.UpdateWorkerCycleCursor(ctx, c.workerId, cursor); err != nil {
+		c.app.Logger.WorkerError(op, "Failed to store cycle cursor",
+			"worker_id", c.workerId, "error", err.Error())
+	}
 }
 
-func (c *TVShowChecker) showWorker(showChan chan ShowRequest, wg *sync.WaitGroup, resultChan chan bool) {
+func (c *TVShowChecker) showWorker(ctx context.Context, showChan chan *ShowGroup, wg *sync.WaitGroup, resultChan chan showResult) {
 	const op = "workers.showWorker"
 	defer wg.Done()
 
-	for req := range showChan {
+	for group := range showChan {
 		start := time.Now()
 		c.app.Logger.WorkerDebug(op, "Processing show",
-			"show_id", req.Show.ApiID, "user_id", req.User.TgID)
+			"show_id", group.ApiID, "watchers", len(group.Requests), "watchlisted", len(group.Watchlisted))
 
 		// Create a task for this show check
-		taskID, err := c.createWorkerTask(TaskTypeCheckShow, nil, req.Show.ApiID)
+		taskID, err := c.createWorkerTask(TaskTypeCheckShow, nil, group.ApiID)
 		if err != nil {
 			c.app.Logger.WorkerError(op, "Failed to create task record for show",
-				"show_id", req.Show.ApiID, "error", err.Error())
+				"show_id", group.ApiID, "error", err.Error())
 		}
 
-		updated := c.processShow(req.User, req.Show)
-		resultChan <- updated
-
-		// Complete the task
-		var processedCount int
-		var updatesCount int
-		if updated {
-			updatesCount = 1
-			processedCount = 1
-		} else {
-			processedCount = 1
-			updatesCount = 0
+		result := showResult{}
+		details, apiCalls, err := c.fetchShowDetails(ctx, group)
+		result.apiCalls = apiCalls
+		if err == nil {
+			c.scheduleNextCheck(ctx, details)
+			for _, req := range group.Requests {
+				if c.processShow(ctx, req.User, req.Show, details) {
+					result.updates++
+				}
+			}
+			for _, entry := range group.Watchlisted {
+				if c.processWatchlistShow(ctx, entry, details) {
+					result.updates++
+				}
+			}
 		}
+		resultChan <- result
 
-		c.completeWorkerTask(taskID, nil, processedCount, updatesCount)
+		// Complete the task
+		c.completeWorkerTask(taskID, err, group.watchers(), result.updates)
+		c.recordApiCallsSaved(taskID, group.watchers()-apiCalls)
 
 		c.app.Logger.WorkerDebug(op, "Completed processing show",
-			"show_id", req.Show.ApiID,
+			"show_id", group.ApiID,
 			"duration_ms", time.Since(start).Milliseconds(),
-			"updated", updated)
+			"updates", result.updates)
 	}
 }
 
-func (c *TVShowChecker) processShow(user *database.GetUsersRow, show database.GetUserTVShowsRow) bool {
-	const op = "workers.processShow"
-	details, err := c.apiClient.GetShowDetails(c.app, int(show.ApiID), user.TgID)
+// checkShow checks one show of one user outside of the regular cycle and reports whether an update was found
+func (c *TVShowChecker) checkShow(ctx context.Context, userId, showId int64) (bool, error) {
+	const op = "workers.checkShow"
+	ctxDb, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	show, err := c.app.Repository.TVShows.GetUserTVShow(ctxDb, showId, userId)
 	if err != nil {
-		c.app.Logger.WorkerError(op, "Error fetching show details",
-			"show_id", show.ApiID, "error", err.Error())
-		return false
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, ErrShowNotTracked
+		}
+		c.app.Logger.WorkerError(op, "Error fetching show of user",
+			"user_id", userId, "show_id", showId, "error", err.Error())
+		return false, err
+	}
+
+	taskID, err := c.createWorkerTask(TaskTypeCheckShow, &userId, showId)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to create task record for show",
+			"show_id", showId, "error", err.Error())
+	}
+
+	group := &ShowGroup{
+		ApiID: showId,
+		Requests: []ShowRequest{{
+			// Only the Telegram ID of the user is needed to compare and notify
+			User: &database.GetUsersRow{TgID: userId},
+			Show: database.GetUserTVShowsRow(show),
+		}},
+	}
+
+	details, _, err := c.fetchShowDetails(ctx, group)
+	updated := false
+	if err == nil {
+		c.scheduleNextCheck(ctx, details)
+		updated = c.processShow(ctx, group.Requests[0].User, group.Requests[0].Show, details)
+	}
+
+	updates := 0
+	if updated {
+		updates = 1
+	}
+	c.completeWorkerTask(taskID, err, 1, updates)
+
+	c.app.Logger.WorkerInfo(op, "Single show check completed",
+		"user_id", userId, "show_id", showId, "updated", updated)
+	return updated, err
+}
+
+// fetchShowDetails fetches a show with the key of one of its watchers. An invalid key of one user should not
+// hide updates from everyone else, so a few other watchers' keys are tried before giving up.
+// It returns the details and the number of TMDB calls made.
+func (c *TVShowChecker) fetchShowDetails(ctx context.Context, group *ShowGroup) (*tv.TV, int, error) {
+	const op = "workers.fetchShowDetails"
+	var lastErr error
+	apiCalls := 0
+	for _, userId := range group.keyUsers() {
+		if apiCalls == maxKeyAttempts {
+			break
+		}
+
+		apiCalls++
+		details, err := c.apiClient.GetShowDetails(ctx, c.app, int(group.ApiID), userId)
+		if err == nil {
+			return details, apiCalls, nil
+		}
+
+		lastErr = err
+		// Only a rejected key is worth retrying with another watcher's key, the client already retried
+		// transient failures and a missing show is missing for everyone
+		if !errors.Is(err, tmdb.ErrUnauthorized) {
+			break
+		}
+
+		c.app.Logger.WorkerWarning(op, "Key rejected while fetching show details, trying another watcher's key",
+			"show_id", group.ApiID, "user_id", userId, "error", err.Error())
+	}
+
+	c.app.Logger.WorkerError(op, "Error fetching show details",
+		"show_id", group.ApiID, "attempts", apiCalls, "error", lastErr.Error())
+	return nil, apiCalls, lastErr
+}
+
+// processShow compares freshly fetched show details with what one user has stored
+func (c *TVShowChecker) processShow(ctx context.Context, user *database.GetUsersRow, show database.GetUserTVShowsRow, details *tv.TV) bool {
+	const op = "workers.processShow"
+	statusChanged := c.processStatus(ctx, user, show, details)
+	if !statusChanged && !show.WatchStatusUpdatedAt.Valid {
+		// Shows tracked before watch statuses existed are all watching, an ended show watched to the end is not
+		c.processWatchStatus(ctx, user, show, details)
 	}
 
 	c.app.Logger.WorkerDebug(op, "Comparing seasons for show",
 		"show_id", show.ApiID, "db_seasons", show.Seasons, "api_seasons", details.Seasons)
 
 	if details.Seasons > show.Seasons {
+		if show.WatchStatus == constants.WatchStatusDropped {
+			c.app.Logger.WorkerDebug(op, "Skipping new seasons of a dropped show",
+				"show_id", show.ApiID, "name", details.Name, "api_seasons", details.Seasons)
+			return statusChanged
+		}
+
+		if !c.shouldNotifySeasons(ctx, user.TgID, show.ApiID, details.Seasons) {
+			c.app.Logger.WorkerDebug(op, "User was already notified about these seasons",
+				"show_id", show.ApiID, "name", details.Name, "api_seasons", details.Seasons)
+			return statusChanged
+		}
+
 		c.app.Logger.WorkerInfo(op, "New season detected for show",
 			"show_id", show.ApiID, "name", details.Name,
 			"old_seasons", show.Seasons, "new_seasons", details.Seasons)
-		c.notifyUser(*user, &show, details)
+		if !c.notifyUser(*user, &show, details) {
+			return statusChanged
+		}
+
+		ctxDb, cancel := persistContext(ctx)
+		defer cancel()
+
+		err := c.app.Repository.TVShows.UpsertShowNotificationState(ctxDb, user.TgID, show.ApiID, details.Seasons)
+		if err != nil {
+			c.app.Logger.WorkerError(op, "Failed to record notified seasons",
+				"show_id", show.ApiID, "user_id", user.TgID, "error", err.Error())
+		}
 		return true
 	} else {
 		c.app.Logger.WorkerDebug(op, "No new seasons for show",
 			"show_id", show.ApiID, "name", details.Name)
+		return statusChanged
+	}
+}
+
+// shouldNotifySeasons reports whether the season count is new to the user or a snoozed alert is due again
+func (c *TVShowChecker) shouldNotifySeasons(ctx context.Context, userId, showId int64, seasons int32) bool {
+	const op = "workers.shouldNotifySeasons"
+	ctxDb, cancel := persistContext(ctx)
+	defer cancel()
+
+	state, err := c.app.Repository.TVShows.GetShowNotificationState(ctxDb, userId, showId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return true
+		}
+		// Skipping is safer than repeating an alert, the next cycle will try again
+		c.app.Logger.WorkerError(op, "Failed to get notification state",
+			"show_id", showId, "user_id", userId, "error", err.Error())
+		return false
+	}
+
+	if seasons > state.LastNotifiedSeasons {
+		return true
+	}
+
+	return state.SnoozedUntil.Valid && !time.Now().Before(state.SnoozedUntil.Time)
+}
+
+// processStatus persists a changed show status and notifies the user about the transition
+func (c *TVShowChecker) processStatus(ctx context.Context, user *database.GetUsersRow, show database.GetUserTVShowsRow, details *tv.TV) bool {
+	const op = "workers.processStatus"
+	if details.Status == "" || details.Status == show.Status {
 		return false
 	}
+
+	c.app.Logger.WorkerInfo(op, "Status change detected for show",
+		"show_id", show.ApiID, "name", details.Name,
+		"old_status", show.Status, "new_status", details.Status)
+
+	ctxDb, cancel := persistContext(ctx)
+	defer cancel()
+
+	if err := c.app.Repository.TVShows.UpdateTVShowStatus(ctxDb, show.ApiID, user.TgID, details.Status); err != nil {
+		c.app.Logger.WorkerError(op, "Failed to update show status",
+			"show_id", show.ApiID, "user_id", user.TgID, "error", err.Error())
+		return false
+	}
+
+	c.processWatchStatus(ctx, user, show, details)
+	c.notifyStatusChange(*user, &show, details)
+	return true
+}
+
+// processWatchStatus completes a show once it has ended and every season is watched, and moves a completed
+// show back to watching when it gets renewed. Only called on status changes and once for shows whose status was
+// never decided, so a status picked by hand sticks. Storing the status marks it as decided even when it stays.
+func (c *TVShowChecker) processWatchStatus(ctx context.Context, user *database.GetUsersRow, show database.GetUserTVShowsRow, details *tv.TV) {
+	const op = "workers.processWatchStatus"
+	watchStatus := utils.AutoWatchStatus(show.WatchStatus, show.Seasons, details.Seasons, details.Status)
+	if watchStatus == show.WatchStatus && show.WatchStatusUpdatedAt.Valid {
+		return
+	}
+
+	ctxDb, cancel := persistContext(ctx)
+	defer cancel()
+
+	if err := c.app.Repository.TVShows.UpdateTVShowWatchStatus(ctxDb, show.ApiID, user.TgID, watchStatus); err != nil {
+		c.app.Logger.WorkerError(op, "Failed to update watch status",
+			"show_id", show.ApiID, "user_id", user.TgID, "error", err.Error())
+		return
+	}
+
+	c.app.Logger.WorkerInfo(op, "Watch status changed for show",
+		"show_id", show.ApiID, "user_id", user.TgID,
+		"old_watch_status", show.WatchStatus, "new_watch_status", watchStatus)
 }
 
-func (c *TVShowChecker) notifyUser(user database.GetUsersRow, watched *database.GetUserTVShowsRow, show *tv.TV) {
+func (c *TVShowChecker) notifyUser(user database.GetUsersRow, watched *database.GetUserTVShowsRow, show *tv.TV) bool {
 	const op = "workers.notifyUser"
-	c.app.Logger.WorkerInfo(op, "Sending notification to user",
+	c.app.Logger.WorkerInfo(op, "Queueing notification for user",
 		"user_id", user.TgID, "show_id", show.Id,
 		"name", show.Name, "seasons", show.Seasons)
 
-	// Retrieve TV poster image
-	imgBuffer, err := image.GetImage(c.app, show.PosterPath)
-	if err != nil {
-		c.app.Logger.WorkerError(op, "Error retrieving image",
-			"poster_path", show.PosterPath, "error", err.Error())
-		return
+	if c.digestMode(user.TgID) != constants.DigestModeOff {
+		summary := fmt.Sprintf("🆕 New seasons: %v ➡️ %v", watched.Seasons, show.Seasons)
+		return c.holdForDigest(user.TgID, constants.NotificationKindNewSeason, show.Id, show.Name, summary) == nil
 	}
 
 	// Prepare TV details caption
@@ -288,220 +606,65 @@ func (c *TVShowChecker) notifyUser(user database.GetUsersRow, watched *database.
 
 	replyMarkup := &telebot.ReplyMarkup{}
 	backButton := replyMarkup.Data("📝 Update Data", fmt.Sprintf("tv|select_seasons|%v", show.Id))
+	snoozeButton := replyMarkup.Data("⏰ Remind me later", fmt.Sprintf("tv|snooze|%v", show.Id))
 	replyMarkup.Inline(
 		replyMarkup.Row(backButton),
+		replyMarkup.Row(snoozeButton),
 	)
 
-	// Send the TV details with poster and buttons
-	imageFile := &telebot.Photo{
-		File:    telebot.File{FileReader: bytes.NewReader(imgBuffer.Bytes())},
-		Caption: caption,
-	}
-
-	c.app.Logger.WorkerDebug(op, "Sending notification message", "user_id", user.TgID)
-	_, err = c.bot.Send(&telebot.User{ID: user.TgID}, imageFile, replyMarkup, telebot.ModeMarkdown)
-	if err != nil {
-		c.app.Logger.WorkerError(op, "Failed to send TV details", "user_id", user.TgID, "error", err.Error())
-		return
-	}
-
-	c.app.Logger.WorkerInfo(op, "Notification sent successfully", "user_id", user.TgID, "show_id", show.Id)
-}
-
-// Database interaction methods
-
-// updateWorkerStatus updates the worker's status in the database
-func (c *TVShowChecker) updateWorkerStatus(status string, err error) {
-	const op = "workers.updateWorkerStatus"
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	var errorStr *string
+	// The dispatcher sends the TV details with poster and buttons
+	err := c.enqueueNotification(user.TgID, constants.NotificationKindNewSeason, show.Id, caption, show.PosterPath, replyMarkup)
 	if err != nil {
-		errText := err.Error()
-		errorStr = &errText
-	}
-
-	now := time.Now()
-	params := database.UpsertWorkerStateParams{
-		WorkerID:   c.workerId,
-		WorkerType: WorkerTypeTVShowChecker,
-		Status:     status,
-		Error:      errorStr,
-		UpdatedAt:  pgtype.Timestamptz{Time: now, Valid: true},
-	}
-
-	// Get current state to preserve other fields
-	currentState, dbErr := c.app.Repository.Worker.GetWorkerState(ctx, c.workerId)
-	if dbErr == nil {
-		// Keep existing values
-		params.LastCheckTime = currentState.LastCheckTime
-		params.NextCheckTime = currentState.NextCheckTime
-		params.ShowsChecked = currentState.ShowsChecked
-		params.UpdatesFound = currentState.UpdatesFound
-		params.CreatedAt = currentState.CreatedAt
-	} else {
-		// Set defaults for new record
-		params.LastCheckTime = pgtype.Timestamptz{}
-		params.NextCheckTime = pgtype.Timestamptz{}
-		params.ShowsChecked = 0
-		params.UpdatesFound = 0
-		params.CreatedAt = pgtype.Timestamptz{Time: now, Valid: true}
+		c.app.Logger.WorkerError(op, "Failed to queue TV details", "user_id", user.TgID, "error", err.Error())
+		return false
 	}
 
-	dbErr = c.app.Repository.Worker.UpsertWorkerState(ctx, params)
-	if dbErr != nil {
-		c.app.Logger.WorkerError(op, "Failed to update worker status",
-			"worker_id", c.workerId, "status", status, "error", dbErr.Error())
-	}
+	c.app.Logger.WorkerInfo(op, "Notification queued successfully", "user_id", user.TgID, "show_id", show.Id)
+	return true
 }
 
-// updateWorkerCheck records a completed check cycle
-func (c *TVShowChecker) updateWorkerCheck(checkTime time.Time, showsChecked, updatesFound int) {
-	const op = "workers.updateWorkerCheck"
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+func (c *TVShowChecker) notifyStatusChange(user database.GetUsersRow, watched *database.GetUserTVShowsRow, show *tv.TV) {
+	const op = "workers.notifyStatusChange"
+	c.app.Logger.WorkerInfo(op, "Queueing status change notification for user",
+		"user_id", user.TgID, "show_id", show.Id,
+		"old_status", watched.Status, "new_status", show.Status)
 
-	// Get current state
-	currentState, err := c.app.Repository.Worker.GetWorkerState(ctx, c.workerId)
-	if err != nil {
-		c.app.Logger.WorkerError(op, "Failed to get worker state",
-			"worker_id", c.workerId, "error", err.Error())
+	if c.digestMode(user.TgID) != constants.DigestModeOff {
+		summary := fmt.Sprintf("📜 Status: %v ➡️ %v", watched.Status, show.Status)
+		c.holdForDigest(user.TgID, constants.NotificationKindStatusChange, show.Id, show.Name, summary)
 		return
 	}
 
-	nextCheckTime := checkTime.Add(24 * time.Hour) // Default to 24 hours if no interval specified
-
-	params := database.UpsertWorkerStateParams{
-		WorkerID:      c.workerId,
-		WorkerType:    WorkerTypeTVShowChecker,
-		Status:        StatusIdle, // Set to idle after completion
-		LastCheckTime: pgtype.Timestamptz{Time: checkTime, Valid: true},
-		NextCheckTime: pgtype.Timestamptz{Time: nextCheckTime, Valid: true},
-		ShowsChecked:  currentState.ShowsChecked + int32(showsChecked),
-		UpdatesFound:  currentState.UpdatesFound + int32(updatesFound),
-		Error:         nil, // Clear any previous error
-		CreatedAt:     currentState.CreatedAt,
-		UpdatedAt:     pgtype.Timestamptz{Time: time.Now(), Valid: true},
-	}
-
-	err = c.app.Repository.Worker.UpsertWorkerState(ctx, params)
-	if err != nil {
-		c.app.Logger.WorkerError(op, "Failed to update worker check info",
-			"worker_id", c.workerId, "error", err.Error())
-	} else {
-		c.app.Logger.WorkerDebug(op, "Updated worker check info",
-			"worker_id", c.workerId,
-			"shows_checked", showsChecked,
-			"updates_found", updatesFound,
-			"next_check", nextCheckTime)
-	}
-}
-
-// createWorkerTask creates a new task record in the database
-func (c *TVShowChecker) createWorkerTask(taskType string, userID *int64, showID int64) (uuid.UUID, error) {
-	const op = "workers.createWorkerTask"
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	now := time.Now()
-
-	// Convert nullable parameters
-	var userIDVal *int64
-	if userID != nil {
-		userIDVal = userID
-	}
-
-	// Only set showID if it's a valid show
-	var showIDVal *int64
-	if showID > 0 {
-		showIDVal = &showID
-	}
-
-	params := database.CreateWorkerTaskParams{
-		WorkerID:  c.workerId,
-		TaskType:  taskType,
-		Status:    TaskStatusRunning,
-		StartTime: pgtype.Timestamptz{Time: now, Valid: true},
-		EndTime:   pgtype.Timestamptz{}, // Will be set on completion
-		UserID:    userIDVal,
-		ShowID:    showIDVal,
-		CreatedAt: pgtype.Timestamptz{Time: now, Valid: true},
-	}
-
-	c.app.Logger.WorkerDebug(op, "Creating worker task",
-		"worker_id", c.workerId, "task_type", taskType, "show_id", showID)
-
-	taskID, err := c.app.Repository.Worker.CreateWorkerTask(ctx, params)
-	if err != nil {
-		c.app.Logger.WorkerError(op, "Failed to create worker task",
-			"worker_id", c.workerId, "task_type", taskType, "error", err.Error())
-		return uuid.Nil, err
+	var headline string
+	switch show.Status {
+	case constants.ShowStatusCanceled:
+		headline = "❌ A show you watch has been canceled"
+	case constants.ShowStatusEnded:
+		headline = "🏁 A show you watch has ended"
+	case constants.ShowStatusReturning:
+		headline = "🔄 A show you watch is returning"
+	default:
+		headline = "📜 A show you watch changed its status"
 	}
 
-	c.app.Logger.WorkerDebug(op, "Worker task created",
-		"task_id", taskID, "worker_id", c.workerId, "task_type", taskType)
-	return taskID, nil
-}
-
-// completeWorkerTask updates a task as completed or failed
-func (c *TVShowChecker) completeWorkerTask(taskID uuid.UUID, err error, showsChecked, updatesFound int) {
-	const op = "workers.completeWorkerTask"
-	if taskID == uuid.Nil {
-		c.app.Logger.WorkerWarning(op, "Cannot update task with nil UUID", "worker_id", c.workerId)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	now := time.Now()
+	text := fmt.Sprintf(
+		"%v\n\n"+
+			"📺 *Name*: %v\n\n"+
+			"📜 *Status*: %v ➡️ %v\n\n"+
+			"🎥 *Watched Seasons*: %v of %v\n",
+		headline,
+		show.Name,
+		watched.Status,
+		show.Status,
+		watched.Seasons,
+		show.Seasons,
+	)
 
-	var status string
-	var errorStr *string
+	err := c.enqueueNotification(user.TgID, constants.NotificationKindStatusChange, show.Id, text, "", nil)
 	if err != nil {
-		status = TaskStatusError
-		errText := err.Error()
-		errorStr = &errText
-	} else {
-		status = TaskStatusSuccess
-	}
-
-	// Get the original task to calculate duration
-	task, getErr := c.app.Repository.Worker.GetWorkerTask(ctx, taskID)
-	if getErr != nil {
-		c.app.Logger.WorkerError(op, "Failed to get task",
-			"task_id", taskID, "worker_id", c.workerId, "error", getErr.Error())
+		c.app.Logger.WorkerError(op, "Failed to queue status change", "user_id", user.TgID, "error", err.Error())
 		return
 	}
 
-	// Calculate duration in milliseconds
-	var durationMs int64
-	if task.StartTime.Valid {
-		durationMs = now.Sub(task.StartTime.Time).Milliseconds()
-	}
-
-	var v1 int32 = int32(showsChecked)
-	var v2 int32 = int32(updatesFound)
-
-	params := database.UpdateWorkerTaskParams{
-		ID:           taskID,
-		Status:       status,
-		EndTime:      pgtype.Timestamptz{Time: now, Valid: true},
-		DurationMs:   &durationMs,
-		Error:        errorStr,
-		ShowsChecked: &v1,
-		UpdatesFound: &v2,
-	}
-
-	err = c.app.Repository.Worker.UpdateWorkerTask(ctx, params)
-	if err != nil {
-		c.app.Logger.WorkerError(op, "Failed to update worker task",
-			"task_id", taskID, "worker_id", c.workerId, "error", err.Error())
-	} else {
-		c.app.Logger.WorkerDebug(op, "Worker task completed",
-			"task_id", taskID, "worker_id", c.workerId,
-			"status", status, "duration_ms", durationMs,
-			"shows_checked", showsChecked, "updates_found", updatesFound)
-	}
+	c.app.Logger.WorkerInfo(op, "Status change notification queued successfully", "user_id", user.TgID, "show_id", show.Id)
 }
@@ -3,13 +3,11 @@ package workers
 import (
 	"context"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/config/app"
-	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/movie"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/providers"
 	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/tv"
-	"github.com/jackc/pgx/v5/pgtype"
 	"golang.org/x/time/rate"
 	"gopkg.in/telebot.v3"
-	"sync"
-	"time"
 )
 
 const (
@@ -20,20 +18,60 @@ const (
 	TaskStatusRunning = "running"
 	TaskStatusSuccess = "success"
 	TaskStatusError   = "error"
-
-	WorkerTypeTVShowChecker = "tv_show_checker"
-
-	TaskTypeCheckShow     = "check_show"
-	TaskTypeCheckAllShows = "check_all_shows"
+	TaskStatusAborted = "aborted"
+
+	// defaultWorkerInstance names the worker instance when none is configured
+	defaultWorkerInstance = "main"
+
+	WorkerTypeTVShowChecker          = "tv_show_checker"
+	WorkerTypeEpisodeScheduler       = "episode_scheduler"
+	WorkerTypeMovieReleaseChecker    = "movie_release_checker"
+	WorkerTypeAvailabilityChecker    = "availability_checker"
+	WorkerTypeNotificationDispatcher = "notification_dispatcher"
+
+	TaskTypeCheckShow             = "check_show"
+	TaskTypeRefreshSchedules      = "refresh_schedules"
+	TaskTypeSendReminders         = "send_reminders"
+	TaskTypeDispatchNotifications = "dispatch_notifications"
+	TaskTypeSendDigests           = "send_digests"
+
+	// Job names double as the task type of a run and as keys of the job_schedules config
+	JobCheckAllShows     = "check_all_shows"
+	JobScheduleEpisodes  = "schedule_episodes"
+	JobCheckAllMovies    = "check_all_movies"
+	JobCheckAvailability = "check_availability"
+	JobPruneWorkerTasks  = "prune_worker_tasks"
 )
 
 type TVShowChecker struct {
-	app       *app.App
+	workerBase
 	apiClient TVShowAPIClient
-	bot       *telebot.Bot
 	limiter   *rate.Limiter
-	workerId  string
-	stateMux  sync.Mutex
+}
+
+// EpisodeScheduler stores upcoming episode air dates of tracked shows and reminds users on air day
+type EpisodeScheduler struct {
+	workerBase
+	apiClient TVShowAPIClient
+}
+
+// AvailabilityChecker watches which streaming services offer watchlisted titles in each owner's region
+type AvailabilityChecker struct {
+	workerBase
+	apiClient ProviderAPIClient
+}
+
+// MovieReleaseChecker watches release dates and statuses of watchlisted movies
+type MovieReleaseChecker struct {
+	workerBase
+	apiClient MovieAPIClient
+}
+
+// NotificationDispatcher delivers notifications queued by other workers, retrying failed sends
+type NotificationDispatcher struct {
+	workerBase
+	bot         *telebot.Bot
+	maxAttempts int
 }
 
 type WorkerApiClient struct {
@@ -42,7 +80,16 @@ type WorkerApiClient struct {
 }
 
 type TVShowAPIClient interface {
-	GetShowDetails(app *app.App, apiId int, userId int64) (*tv.TV, error)
+	GetShowDetails(ctx context.Context, app *app.App, apiId int, userId int64) (*tv.TV, error)
+	GetSeasonDetails(ctx context.Context, app *app.App, apiId, seasonNumber int, userId int64) (*tv.Season, error)
+}
+
+type MovieAPIClient interface {
+	GetMovieDetails(ctx context.Context, app *app.App, apiId int, userId int64) (*movie.Movie, error)
+}
+
+type ProviderAPIClient interface {
+	GetWatchProviders(ctx context.Context, app *app.App, showType string, apiId int, userId int64) (*providers.WatchProviders, error)
 }
 
 func NewWorkerApiClient(app *app.App, requestsPerSecond int) *WorkerApiClient {
@@ -56,45 +103,53 @@ func NewWorkerApiClient(app *app.App, requestsPerSecond int) *WorkerApiClient {
 	}
 }
 
-func NewTVShowChecker(app *app.App, bot *telebot.Bot, apiClient TVShowAPIClient) *TVShowChecker {
+func NewTVShowChecker(app *app.App, apiClient TVShowAPIClient) *TVShowChecker {
 	const op = "workers.NewTVShowChecker"
 	app.Logger.WorkerInfo(op, "Initializing TV Show Checker")
 
-	// Generate a unique worker ID
-	workerId := "tvshow-checker-" + time.Now().Format("20060102-150405")
-	app.Logger.WorkerDebug(op, "Generated worker ID", "worker_id", workerId)
-
-	// Initialize worker state in database
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// Initialize the worker state in the database
-	app.Logger.WorkerDebug(op, "Initializing worker state in database", "worker_id", workerId)
-	err := app.Repository.Worker.UpsertWorkerState(ctx, database.UpsertWorkerStateParams{
-		WorkerID:      workerId,
-		WorkerType:    WorkerTypeTVShowChecker,
-		Status:        StatusIdle,
-		LastCheckTime: pgtype.Timestamptz{},
-		NextCheckTime: pgtype.Timestamptz{},
-		Error:         nil,
-		ShowsChecked:  0,
-		UpdatesFound:  0,
-		CreatedAt:     pgtype.Timestamptz{Time: time.Now(), Valid: true},
-		UpdatedAt:     pgtype.Timestamptz{Time: time.Now(), Valid: true},
-	})
-
-	if err != nil {
-		app.Logger.WorkerError(op, "Failed to initialize worker state in database",
-			"worker_id", workerId, "error", err.Error())
-	} else {
-		app.Logger.WorkerInfo(op, "Worker state initialized successfully",
-			"worker_id", workerId, "type", WorkerTypeTVShowChecker)
+	return &TVShowChecker{
+		workerBase: newWorkerBase(app, WorkerTypeTVShowChecker, "tvshow-checker"),
+		apiClient:  apiClient,
 	}
+}
 
-	return &TVShowChecker{
-		app:       app,
-		bot:       bot,
-		apiClient: apiClient,
-		workerId:  workerId,
+func NewEpisodeScheduler(app *app.App, apiClient TVShowAPIClient) *EpisodeScheduler {
+	const op = "workers.NewEpisodeScheduler"
+	app.Logger.WorkerInfo(op, "Initializing Episode Scheduler")
+
+	return &EpisodeScheduler{
+		workerBase: newWorkerBase(app, WorkerTypeEpisodeScheduler, "episode-scheduler"),
+		apiClient:  apiClient,
+	}
+}
+
+func NewMovieReleaseChecker(app *app.App, apiClient MovieAPIClient) *MovieReleaseChecker {
+	const op = "workers.NewMovieReleaseChecker"
+	app.Logger.WorkerInfo(op, "Initializing Movie Release Checker")
+
+	return &MovieReleaseChecker{
+		workerBase: newWorkerBase(app, WorkerTypeMovieReleaseChecker, "movie-release-checker"),
+		apiClient:  apiClient,
+	}
+}
+
+func NewAvailabilityChecker(app *app.App, apiClient ProviderAPIClient) *AvailabilityChecker {
+	const op = "workers.NewAvailabilityChecker"
+	app.Logger.WorkerInfo(op, "Initializing Availability Checker")
+
+	return &AvailabilityChecker{
+		workerBase: newWorkerBase(app, WorkerTypeAvailabilityChecker, "availability-checker"),
+		apiClient:  apiClient,
+	}
+}
+
+func NewNotificationDispatcher(app *app.App, bot *telebot.Bot, maxAttempts int) *NotificationDispatcher {
+	const op = "workers.NewNotificationDispatcher"
+	app.Logger.WorkerInfo(op, "Initializing Notification Dispatcher", "max_attempts", maxAttempts)
+
+	return &NotificationDispatcher{
+		workerBase:  newWorkerBase(app, WorkerTypeNotificationDispatcher, "notification-dispatcher"),
+		bot:         bot,
+		maxAttempts: maxAttempts,
 	}
 }
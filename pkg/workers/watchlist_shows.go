@@ -0,0 +1,137 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/storage/database"
+	"github.com/erkinov-wtf/movie-manager-bot/internal/tmdb/tv"
+	"github.com/erkinov-wtf/movie-manager-bot/pkg/constants"
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/telebot.v3"
+	"time"
+)
+
+// processWatchlistShow stores fresh release data of a show on a user's watchlist and tells the user when a
+// season premiere is announced or moved and when a new season starts airing. The first check of an entry
+// only records a baseline.
+func (c *TVShowChecker) processWatchlistShow(ctx context.Context, entry database.GetWatchlistsByTypeRow, details *tv.TV) bool {
+	const op = "workers.processWatchlistShow"
+
+	// Only the first episode of a season marks a premiere, later episodes are covered by episode reminders
+	var premiere pgtype.Date
+	next := details.NextEpisodeToAir
+	if next != nil && next.EpisodeNumber == 1 {
+		if parsed, err := time.Parse(constants.DateFormat, next.AirDate); err == nil {
+			premiere = pgtype.Date{Time: parsed, Valid: true}
+		}
+	}
+
+	var airedSeasons int32
+	if details.LastEpisodeToAir != nil {
+		airedSeasons = details.LastEpisodeToAir.SeasonNumber
+	}
+
+	premiereChanged := premiere.Valid != entry.ReleaseDate.Valid ||
+		(premiere.Valid && !premiere.Time.Equal(entry.ReleaseDate.Time))
+	seasonsChanged := entry.AiredSeasons == nil || *entry.AiredSeasons != airedSeasons
+	statusChanged := entry.ReleaseStatus == nil || *entry.ReleaseStatus != details.Status
+	// Entries added before runtimes were stored get theirs here, the runtime sort needs it
+	runtime := tv.EpisodeRuntime(details)
+	runtimeChanged := !sameRuntime(entry.Runtime, runtime)
+	if !premiereChanged && !seasonsChanged && !statusChanged && !runtimeChanged {
+		return false
+	}
+
+	ctxDb, cancel := persistContext(ctx)
+	defer cancel()
+
+	status := details.Status
+	err := c.app.Repository.Watchlists.UpdateWatchlistShowRelease(ctxDb, database.UpdateWatchlistShowReleaseParams{
+		ID:            entry.ID,
+		ReleaseDate:   premiere,
+		ReleaseStatus: &status,
+		AiredSeasons:  &airedSeasons,
+		Runtime:       runtime,
+	})
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to store release data of watchlisted show",
+			"show_id", entry.ShowApiID, "user_id", entry.UserID, "error", err.Error())
+		return false
+	}
+
+	if entry.ReleaseStatus == nil || entry.AiredSeasons == nil {
+		c.app.Logger.WorkerDebug(op, "Stored initial release data of watchlisted show",
+			"show_id", entry.ShowApiID, "user_id", entry.UserID, "aired_seasons", airedSeasons)
+		return false
+	}
+
+	var headline string
+	switch {
+	case airedSeasons > *entry.AiredSeasons && airedSeasons == 1:
+		headline = "🎉 A show from your watchlist has premiered"
+	case airedSeasons > *entry.AiredSeasons:
+		headline = fmt.Sprintf("🆕 Season %v of a show from your watchlist started airing", airedSeasons)
+	case premiere.Valid && !entry.ReleaseDate.Valid:
+		headline = fmt.Sprintf("📅 Season %v premiere date announced", next.SeasonNumber)
+	case premiere.Valid && premiereChanged:
+		headline = fmt.Sprintf("📅 Season %v premiere moved from %v", next.SeasonNumber,
+			entry.ReleaseDate.Time.Format(constants.DateFormat))
+	default:
+		c.app.Logger.WorkerDebug(op, "Release data of watchlisted show changed without a notable event",
+			"show_id", entry.ShowApiID, "user_id", entry.UserID,
+			"old_status", *entry.ReleaseStatus, "new_status", details.Status)
+		return false
+	}
+
+	c.app.Logger.WorkerInfo(op, "Release update detected for watchlisted show",
+		"show_id", entry.ShowApiID, "user_id", entry.UserID, "name", details.Name,
+		"old_aired_seasons", *entry.AiredSeasons, "new_aired_seasons", airedSeasons)
+	return c.notifyWatchlistShow(entry.UserID, headline, details)
+}
+
+// sameRuntime reports whether a stored runtime matches a fresh one, both may be unknown
+func sameRuntime(stored, fresh *int32) bool {
+	if stored == nil || fresh == nil {
+		return stored == fresh
+	}
+	return *stored == *fresh
+}
+
+func (c *TVShowChecker) notifyWatchlistShow(userId int64, headline string, details *tv.TV) bool {
+	const op = "workers.notifyWatchlistShow"
+	c.app.Logger.WorkerInfo(op, "Queueing watchlist alert for user",
+		"user_id", userId, "show_id", details.Id, "name", details.Name)
+
+	nextEpisode := "TBA"
+	if next := details.NextEpisodeToAir; next != nil && next.AirDate != "" {
+		nextEpisode = fmt.Sprintf("S%02dE%02d on %v", next.SeasonNumber, next.EpisodeNumber, next.AirDate)
+	}
+
+	caption := fmt.Sprintf(
+		"%v\n\n"+
+			"📺 *Name*: %v\n\n"+
+			"📜 *Status*: %v\n\n"+
+			"📅 *Next Episode*: %v\n",
+		headline,
+		details.Name,
+		details.Status,
+		nextEpisode,
+	)
+
+	replyMarkup := &telebot.ReplyMarkup{}
+	trackButton := replyMarkup.Data("👀 Start tracking", fmt.Sprintf("tv|select_seasons|%v", details.Id))
+	removeButton := replyMarkup.Data("🗑 Remove from watchlist", fmt.Sprintf("tv|unwatchlist|%v", details.Id))
+	replyMarkup.Inline(
+		replyMarkup.Row(trackButton),
+		replyMarkup.Row(removeButton),
+	)
+
+	err := c.enqueueNotification(userId, constants.NotificationKindWatchlistShow, details.Id, caption, details.PosterPath, replyMarkup)
+	if err != nil {
+		c.app.Logger.WorkerError(op, "Failed to queue watchlist alert", "user_id", userId, "error", err.Error())
+		return false
+	}
+
+	c.app.Logger.WorkerInfo(op, "Watchlist alert queued successfully", "user_id", userId, "show_id", details.Id)
+	return true
+}